@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net"
 	"net/http"
@@ -10,9 +11,21 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/bharathbbg/delivery-service/internal/api/rest"
+	"github.com/bharathbbg/delivery-service/internal/blob"
+	"github.com/bharathbbg/delivery-service/internal/carrier"
 	"github.com/bharathbbg/delivery-service/internal/config"
+	"github.com/bharathbbg/delivery-service/internal/eta"
+	"github.com/bharathbbg/delivery-service/internal/geo"
+	"github.com/bharathbbg/delivery-service/internal/geofence"
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/notify"
+	"github.com/bharathbbg/delivery-service/internal/rate"
 	"github.com/bharathbbg/delivery-service/internal/repository"
 	"github.com/bharathbbg/delivery-service/internal/service"
+	"github.com/bharathbbg/delivery-service/internal/slot"
+	"github.com/bharathbbg/delivery-service/internal/tracking"
+	"github.com/bharathbbg/delivery-service/internal/tracknum"
 	"google.golang.org/grpc"
 )
 
@@ -36,9 +49,124 @@ func main() {
 	}
 	defer cache.Close()
 
+	// Initialize ETA engine
+	etaEngine, err := newETAEngine(cfg.ETA, repo)
+	if err != nil {
+		log.Fatalf("Failed to initialize ETA engine: %v", err)
+	}
+
 	// Initialize service
-	// Initialize service
-	service.NewDeliveryService(repo, cache)
+	deliveryService := service.NewDeliveryService(repo, cache, etaEngine)
+	if err := configureServiceLevels(deliveryService, cfg.ServiceLevels); err != nil {
+		log.Fatalf("Failed to configure service levels: %v", err)
+	}
+	slotWindows, err := slot.ParseWindows(cfg.Slots.Windows)
+	if err != nil {
+		log.Fatalf("Failed to configure time slots: %v", err)
+	}
+	deliveryService.SetSlotSchedule(slot.Schedule{
+		Windows:     slotWindows,
+		Capacity:    cfg.Slots.Capacity,
+		Hold:        time.Duration(cfg.Slots.HoldMinutes) * time.Minute,
+		HorizonDays: cfg.Slots.HorizonDays,
+	})
+	if cfg.Geocoder.CentroidsPath != "" {
+		geocoder, err := geo.LoadCentroidsFile(cfg.Geocoder.CentroidsPath)
+		if err != nil {
+			log.Fatalf("Failed to load geocoder centroids: %v", err)
+		}
+		log.Printf("Loaded %d postal code centroids", geocoder.Len())
+		deliveryService.SetGeocoder(geocoder)
+	}
+	deliveryService.SetTrackingPolicy(tracking.Policy{
+		Downsampler: tracking.Downsampler{
+			MinInterval: time.Duration(cfg.Tracking.MinIntervalSeconds) * time.Second,
+			MinDistance: float64(cfg.Tracking.MinDistanceMeters),
+		},
+		LastKnownTTL: time.Duration(cfg.Tracking.LastKnownTTLMinutes) * time.Minute,
+		MaxAge:       24 * time.Hour,
+		MaxSkew:      time.Minute,
+		MaxItems:     cfg.Tracking.MaxPings,
+	})
+	deliveryService.SetGeofencePolicy(service.GeofencePolicy{
+		Tracker:      geofence.Tracker{ExitFactor: cfg.Tracking.GeofenceExitFactor},
+		NearbyRadius: float64(cfg.Tracking.NearbyRadiusMeters),
+		StateTTL:     24 * time.Hour,
+	})
+	deliveryService.SetRoutePolicy(service.RoutePolicy{
+		SpeedKmh:    cfg.Routes.SpeedKmh,
+		Detour:      cfg.Routes.Detour,
+		ServiceTime: time.Duration(cfg.Routes.ServiceTimeMinutes) * time.Minute,
+		Capacity:    cfg.Routes.Capacity,
+	})
+	blobStore, err := blob.NewLocalStore(cfg.Blobs.Dir)
+	if err != nil {
+		log.Fatalf("Failed to initialize blob store: %v", err)
+	}
+	deliveryService.SetBlobStore(blobStore)
+	deliveryService.SetOTPPolicy(service.OTPPolicy{
+		Length:      cfg.OTP.Length,
+		TTL:         time.Duration(cfg.OTP.TTLHours) * time.Hour,
+		MaxAttempts: cfg.OTP.MaxAttempts,
+		Lockout:     time.Duration(cfg.OTP.LockoutMinutes) * time.Minute,
+		Secret:      requireSecret("OTP_SECRET", cfg.OTP.Secret),
+	})
+	deliveryService.SetAttemptPolicy(service.AttemptPolicy{
+		MaxAttempts:  cfg.Attempts.MaxAttempts,
+		ReturnOn:     cfg.Attempts.ReturnOn,
+		ReattemptGap: cfg.Attempts.ReattemptGap,
+	})
+	deliveryService.SetReturnPolicy(service.ReturnPolicy{
+		Window:    time.Duration(cfg.Returns.WindowDays) * 24 * time.Hour,
+		Warehouse: cfg.Returns.Warehouse,
+	})
+	deliveryService.SetHubPolicy(service.HubPolicy{
+		OriginCode: cfg.Hubs.OriginCode,
+		SortCode:   cfg.Hubs.SortCode,
+	})
+	deliveryService.SetLabelPolicy(service.LabelPolicy{SenderName: cfg.Labels.SenderName})
+	deliveryService.SetManifestPolicy(service.ManifestPolicy{Secret: requireSecret("MANIFEST_SECRET", cfg.Manifests.Secret)})
+	deliveryService.SetTrackingNumberScheme(tracknum.Crockford{
+		Prefix: cfg.TrackingNumbers.Prefix,
+		Length: cfg.TrackingNumbers.Length,
+	}, cfg.TrackingNumbers.Prefix)
+	if cfg.Rates.CardsPath != "" {
+		rates, err := rate.LoadPath(cfg.Rates.CardsPath)
+		if err != nil {
+			log.Fatalf("Failed to load rate cards: %v", err)
+		}
+		log.Printf("Loaded %d rate card versions", rates.Len())
+		deliveryService.SetRateCards(rates)
+	}
+	carriers, err := newCarrierPolicy(cfg.Carriers)
+	if err != nil {
+		log.Fatalf("Failed to configure carriers: %v", err)
+	}
+	if err := deliveryService.SetCarrierPolicy(carriers); err != nil {
+		log.Fatalf("Failed to configure carriers: %v", err)
+	}
+	deliveryService.SetScanPolicy(service.ScanPolicy{
+		MaxItems:        cfg.Scans.MaxItems,
+		BatchSize:       cfg.Scans.BatchSize,
+		DuplicateWindow: time.Duration(cfg.Scans.DuplicateWindowSeconds) * time.Second,
+		MaxSkew:         5 * time.Minute,
+	})
+	deliveryService.SetPickupPolicy(service.PickupPolicy{HoldDays: cfg.Pickups.HoldDays})
+	if notifications := cfg.Services.NotificationService; notifications.Host != "" {
+		deliveryService.SetNotifier(notify.NewHTTPNotifier(fmt.Sprintf("http://%s:%d", notifications.Host, notifications.Port)))
+	}
+
+	// Return parcels left at pickup points past their hold
+	jobs, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	if cfg.Pickups.ExpiryCheckMinutes > 0 {
+		go expirePickupHolds(jobs, deliveryService, time.Duration(cfg.Pickups.ExpiryCheckMinutes)*time.Minute)
+	}
+
+	// Pull tracking for deliveries handed to carriers
+	if len(carriers.Carriers) > 0 && cfg.Carriers.SyncMinutes > 0 {
+		go syncCarrierShipments(jobs, deliveryService, time.Duration(cfg.Carriers.SyncMinutes)*time.Minute)
+	}
 
 	// Initialize gRPC server
 	grpcServer := grpc.NewServer()
@@ -59,6 +187,7 @@ func main() {
 	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
+	rest.NewHandler(deliveryService).RegisterRoutes(router)
 	server := &http.Server{
 		Addr:    cfg.HTTPAddr,
 		Handler: router,
@@ -88,3 +217,130 @@ func main() {
 	grpcServer.GracefulStop()
 	log.Println("Server exited properly")
 }
+
+// requireSecret stops the server when a signing secret is not configured. A
+// random one would break every PIN and manifest signature across restarts
+// and replicas.
+func requireSecret(name, value string) []byte {
+	if value == "" {
+		log.Fatalf("%s must be set", name)
+	}
+	return []byte(value)
+}
+
+func expirePickupHolds(ctx context.Context, s *service.DeliveryService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			returned, err := s.ExpirePickupHolds(ctx, now)
+			if err != nil {
+				log.Printf("Failed to expire pickup holds: %v", err)
+			}
+			if returned > 0 {
+				log.Printf("Returned %d uncollected deliveries to sender", returned)
+			}
+		}
+	}
+}
+
+func syncCarrierShipments(ctx context.Context, s *service.DeliveryService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			recorded, err := s.SyncCarrierShipments(ctx)
+			if err != nil {
+				log.Printf("Failed to sync carrier tracking: %v", err)
+			}
+			if recorded > 0 {
+				log.Printf("Recorded %d carrier tracking updates", recorded)
+			}
+		}
+	}
+}
+
+func newCarrierPolicy(cfg config.CarriersConfig) (service.CarrierPolicy, error) {
+	policy := service.CarrierPolicy{Carriers: make(map[string]carrier.Carrier)}
+	for _, c := range cfg.Carriers {
+		if c.URL == "" {
+			return policy, fmt.Errorf("carrier %s has no URL", c.Name)
+		}
+		statuses, err := carrier.ParseStatusMap(c.Statuses)
+		if err != nil {
+			return policy, fmt.Errorf("carrier %s: %w", c.Name, err)
+		}
+		policy.Carriers[c.Name] = carrier.NewHTTPCarrier(c.URL, c.APIKey, statuses)
+	}
+
+	rules, err := carrier.ParseRules(cfg.Routes)
+	if err != nil {
+		return policy, err
+	}
+	policy.Router = carrier.Router{Rules: rules, Uncovered: cfg.Uncovered}
+	return policy, nil
+}
+
+func newETAEngine(cfg config.ETAConfig, history eta.TransitHistory) (*eta.Engine, error) {
+	calendar, err := eta.NewCalendar(cfg.Timezone, cfg.Holidays)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultRule, err := newETARule(cfg.TransitDays, cfg.Cutoff, cfg.DeliverBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return eta.NewEngine(calendar, history, eta.Options{
+		DefaultRule: defaultRule,
+		MinSamples:  cfg.MinSamples,
+		Lookback:    time.Duration(cfg.LookbackDays) * 24 * time.Hour,
+	}), nil
+}
+
+func newETARule(transitDays int, cutoff, deliverBy string) (eta.Rule, error) {
+	cutoffClock, err := eta.ParseClock(cutoff)
+	if err != nil {
+		return eta.Rule{}, err
+	}
+	deliverByClock, err := eta.ParseClock(deliverBy)
+	if err != nil {
+		return eta.Rule{}, err
+	}
+	return eta.Rule{TransitDays: transitDays, Cutoff: cutoffClock, DeliverBy: deliverByClock}, nil
+}
+
+func configureServiceLevels(s *service.DeliveryService, cfg config.ServiceLevelsConfig) error {
+	levels := []struct {
+		name   string
+		cfg    config.ServiceLevelConfig
+		policy service.ServiceLevelPolicy
+	}{
+		{model.ServiceLevelExpress, cfg.Express, service.ServiceLevelPolicy{}},
+		{model.ServiceLevelSameDay, cfg.SameDay, service.ServiceLevelPolicy{StrictCutoff: true}},
+		{model.ServiceLevelScheduled, cfg.Scheduled, service.ServiceLevelPolicy{
+			MaxScheduleAhead: time.Duration(cfg.MaxScheduleDays) * 24 * time.Hour,
+		}},
+	}
+
+	for _, level := range levels {
+		rule, err := newETARule(level.cfg.TransitDays, level.cfg.Cutoff, level.cfg.DeliverBy)
+		if err != nil {
+			return fmt.Errorf("service level %s: %w", level.name, err)
+		}
+		level.policy.ETA = rule
+		level.policy.AllowedZones = level.cfg.Zones
+		s.SetServiceLevel(level.name, level.policy)
+	}
+
+	return nil
+}
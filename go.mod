@@ -3,10 +3,12 @@ module github.com/bharathbbg/delivery-service
 go 1.25
 
 require (
+	github.com/boombuler/barcode v1.1.0
 	github.com/go-redis/redis/v8 v8.11.5
 	github.com/google/uuid v1.6.0
 	github.com/lib/pq v1.10.9
 	google.golang.org/grpc v1.76.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,156 @@
+package address
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// Field error codes
+const (
+	CodeRequired       = "REQUIRED"
+	CodeInvalidFormat  = "INVALID_FORMAT"
+	CodeUnknownState   = "UNKNOWN_STATE"
+	CodeUnknownCountry = "UNKNOWN_COUNTRY"
+	CodeUndeliverable  = "UNDELIVERABLE"
+	CodeTooLong        = "TOO_LONG"
+)
+
+// ValidationError lists every field that failed validation.
+type ValidationError struct {
+	Errors []model.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Field + ": " + fe.Message
+	}
+	return "invalid address: " + strings.Join(msgs, "; ")
+}
+
+// Pipeline cleans up an address, applies the rules for its country and then
+// asks the verifier to confirm it.
+type Pipeline struct {
+	rules    map[string]*CountryRules // keyed by code and upper-cased name
+	verifier Verifier
+}
+
+func NewPipeline(verifier Verifier) *Pipeline {
+	p := &Pipeline{rules: make(map[string]*CountryRules), verifier: verifier}
+	for _, r := range defaultRules {
+		p.AddCountry(r)
+	}
+	return p
+}
+
+// AddCountry registers or replaces the rules for a country.
+func (p *Pipeline) AddCountry(rules *CountryRules) {
+	p.rules[rules.Code] = rules
+	for _, name := range rules.Names {
+		p.rules[strings.ToUpper(name)] = rules
+	}
+}
+
+// NormalizeCountry returns the ISO code for a known country name or code, and
+// the upper-cased input otherwise.
+func (p *Pipeline) NormalizeCountry(country string) string {
+	key := strings.ToUpper(strings.Join(strings.Fields(country), " "))
+	if r, ok := p.rules[key]; ok {
+		return r.Code
+	}
+	return key
+}
+
+// Process returns the normalized address, a *ValidationError if any field is
+// invalid, or the verifier's error if it could not be reached.
+func (p *Pipeline) Process(ctx context.Context, addr model.Address) (model.Address, error) {
+	var errs []model.FieldError
+
+	out := model.Address{
+		Street:  normalizeStreet(addr.Street),
+		City:    normalizeName(addr.City),
+		State:   strings.Join(strings.Fields(addr.State), " "),
+		Country: p.NormalizeCountry(addr.Country),
+		ZipCode: compactPostal(addr.ZipCode),
+
+		Latitude:  addr.Latitude,
+		Longitude: addr.Longitude,
+	}
+
+	// Required fields and column limits from delivery_addresses
+	for _, f := range []struct {
+		name, value string
+		max         int
+	}{
+		{"street", out.Street, 255},
+		{"city", out.City, 100},
+		{"country", out.Country, 100},
+		{"zip_code", out.ZipCode, 20},
+	} {
+		if f.value == "" {
+			errs = append(errs, model.FieldError{Field: f.name, Code: CodeRequired, Message: f.name + " is required"})
+		} else if len(f.value) > f.max {
+			errs = append(errs, model.FieldError{Field: f.name, Code: CodeTooLong, Message: f.name + " is too long"})
+		}
+	}
+
+	// Caller-supplied coordinates must come as a valid pair
+	if (out.Latitude == nil) != (out.Longitude == nil) {
+		errs = append(errs, model.FieldError{Field: "latitude", Code: CodeInvalidFormat, Message: "latitude and longitude must be given together"})
+	} else if out.Latitude != nil && (*out.Latitude < -90 || *out.Latitude > 90 || *out.Longitude < -180 || *out.Longitude > 180) {
+		errs = append(errs, model.FieldError{Field: "latitude", Code: CodeInvalidFormat, Message: "coordinates are out of range"})
+	}
+
+	rules, known := p.rules[out.Country]
+	if out.Country != "" && !known && len(out.Country) != 2 {
+		errs = append(errs, model.FieldError{Field: "country", Code: CodeUnknownCountry, Message: "country must be an ISO 3166 alpha-2 code"})
+	}
+
+	if known {
+		errs = append(errs, applyRules(rules, &out)...)
+	}
+
+	if len(errs) > 0 {
+		return model.Address{}, &ValidationError{Errors: errs}
+	}
+
+	// External verification runs only on addresses that passed local checks
+	verified, fieldErrs, err := p.verifier.Verify(ctx, out)
+	if err != nil {
+		return model.Address{}, err
+	}
+	if len(fieldErrs) > 0 {
+		return model.Address{}, &ValidationError{Errors: fieldErrs}
+	}
+
+	return verified, nil
+}
+
+func applyRules(rules *CountryRules, addr *model.Address) []model.FieldError {
+	var errs []model.FieldError
+
+	if addr.ZipCode != "" && rules.PostalPattern != nil {
+		if !rules.PostalPattern.MatchString(addr.ZipCode) {
+			errs = append(errs, model.FieldError{Field: "zip_code", Code: CodeInvalidFormat, Message: "zip_code is not valid for " + rules.Code})
+		} else if rules.FormatPostal != nil {
+			addr.ZipCode = rules.FormatPostal(addr.ZipCode)
+		}
+	}
+
+	if rules.States != nil {
+		switch code, ok := rules.States[strings.ToUpper(addr.State)]; {
+		case ok:
+			addr.State = code
+		case addr.State == "" && rules.StateRequired:
+			errs = append(errs, model.FieldError{Field: "state", Code: CodeRequired, Message: "state is required"})
+		case addr.State != "":
+			errs = append(errs, model.FieldError{Field: "state", Code: CodeUnknownState, Message: "state is not valid for " + rules.Code})
+		}
+	} else {
+		addr.State = normalizeName(addr.State)
+	}
+
+	return errs
+}
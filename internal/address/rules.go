@@ -0,0 +1,112 @@
+package address
+
+import (
+	"regexp"
+	"strings"
+)
+
+// CountryRules holds the per-country formats used to validate and normalize
+// an address.
+type CountryRules struct {
+	Code          string
+	Names         []string       // accepted spellings besides the code
+	PostalPattern *regexp.Regexp // matched against the compacted, upper-cased postal code
+	FormatPostal  func(compact string) string
+	States        map[string]string // upper-cased name or code -> canonical code
+	StateRequired bool
+}
+
+var defaultRules = []*CountryRules{
+	{
+		Code:          "US",
+		Names:         []string{"USA", "UNITED STATES", "UNITED STATES OF AMERICA"},
+		PostalPattern: regexp.MustCompile(`^\d{5}(\d{4})?$`),
+		FormatPostal: func(c string) string {
+			if len(c) == 9 {
+				return c[:5] + "-" + c[5:]
+			}
+			return c
+		},
+		States: stateTable(map[string]string{
+			"AL": "Alabama", "AK": "Alaska", "AZ": "Arizona", "AR": "Arkansas", "CA": "California",
+			"CO": "Colorado", "CT": "Connecticut", "DE": "Delaware", "DC": "District of Columbia",
+			"FL": "Florida", "GA": "Georgia", "HI": "Hawaii", "ID": "Idaho", "IL": "Illinois",
+			"IN": "Indiana", "IA": "Iowa", "KS": "Kansas", "KY": "Kentucky", "LA": "Louisiana",
+			"ME": "Maine", "MD": "Maryland", "MA": "Massachusetts", "MI": "Michigan", "MN": "Minnesota",
+			"MS": "Mississippi", "MO": "Missouri", "MT": "Montana", "NE": "Nebraska", "NV": "Nevada",
+			"NH": "New Hampshire", "NJ": "New Jersey", "NM": "New Mexico", "NY": "New York",
+			"NC": "North Carolina", "ND": "North Dakota", "OH": "Ohio", "OK": "Oklahoma", "OR": "Oregon",
+			"PA": "Pennsylvania", "RI": "Rhode Island", "SC": "South Carolina", "SD": "South Dakota",
+			"TN": "Tennessee", "TX": "Texas", "UT": "Utah", "VT": "Vermont", "VA": "Virginia",
+			"WA": "Washington", "WV": "West Virginia", "WI": "Wisconsin", "WY": "Wyoming",
+			"PR": "Puerto Rico",
+		}),
+		StateRequired: true,
+	},
+	{
+		Code:          "CA",
+		Names:         []string{"CAN", "CANADA"},
+		PostalPattern: regexp.MustCompile(`^[ABCEGHJ-NPRSTVXY]\d[A-Z]\d[A-Z]\d$`),
+		FormatPostal:  func(c string) string { return c[:3] + " " + c[3:] },
+		States: stateTable(map[string]string{
+			"AB": "Alberta", "BC": "British Columbia", "MB": "Manitoba", "NB": "New Brunswick",
+			"NL": "Newfoundland and Labrador", "NS": "Nova Scotia", "NT": "Northwest Territories",
+			"NU": "Nunavut", "ON": "Ontario", "PE": "Prince Edward Island", "QC": "Quebec",
+			"SK": "Saskatchewan", "YT": "Yukon",
+		}),
+		StateRequired: true,
+	},
+	{
+		Code:          "GB",
+		Names:         []string{"GBR", "UK", "UNITED KINGDOM", "GREAT BRITAIN"},
+		PostalPattern: regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]?\d[A-Z]{2}$`),
+		FormatPostal:  func(c string) string { return c[:len(c)-3] + " " + c[len(c)-3:] },
+	},
+	{
+		Code:          "IN",
+		Names:         []string{"IND", "INDIA"},
+		PostalPattern: regexp.MustCompile(`^[1-9]\d{5}$`),
+		States: stateTable(map[string]string{
+			"AP": "Andhra Pradesh", "AR": "Arunachal Pradesh", "AS": "Assam", "BR": "Bihar",
+			"CT": "Chhattisgarh", "GA": "Goa", "GJ": "Gujarat", "HR": "Haryana", "HP": "Himachal Pradesh",
+			"JH": "Jharkhand", "KA": "Karnataka", "KL": "Kerala", "MP": "Madhya Pradesh",
+			"MH": "Maharashtra", "MN": "Manipur", "ML": "Meghalaya", "MZ": "Mizoram", "NL": "Nagaland",
+			"OR": "Odisha", "PB": "Punjab", "RJ": "Rajasthan", "SK": "Sikkim", "TN": "Tamil Nadu",
+			"TG": "Telangana", "TR": "Tripura", "UP": "Uttar Pradesh", "UT": "Uttarakhand",
+			"WB": "West Bengal", "DL": "Delhi", "JK": "Jammu and Kashmir", "LA": "Ladakh",
+			"PY": "Puducherry", "CH": "Chandigarh",
+		}),
+		StateRequired: true,
+	},
+	{
+		Code:          "DE",
+		Names:         []string{"DEU", "GERMANY", "DEUTSCHLAND"},
+		PostalPattern: regexp.MustCompile(`^\d{5}$`),
+	},
+	{
+		Code:          "FR",
+		Names:         []string{"FRA", "FRANCE"},
+		PostalPattern: regexp.MustCompile(`^\d{5}$`),
+	},
+	{
+		Code:          "AU",
+		Names:         []string{"AUS", "AUSTRALIA"},
+		PostalPattern: regexp.MustCompile(`^\d{4}$`),
+		States: stateTable(map[string]string{
+			"ACT": "Australian Capital Territory", "NSW": "New South Wales", "NT": "Northern Territory",
+			"QLD": "Queensland", "SA": "South Australia", "TAS": "Tasmania", "VIC": "Victoria",
+			"WA": "Western Australia",
+		}),
+		StateRequired: true,
+	},
+}
+
+// stateTable indexes states by both code and upper-cased name.
+func stateTable(codes map[string]string) map[string]string {
+	table := make(map[string]string, len(codes)*2)
+	for code, name := range codes {
+		table[code] = code
+		table[strings.ToUpper(name)] = code
+	}
+	return table
+}
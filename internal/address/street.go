@@ -0,0 +1,156 @@
+package address
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Abbreviations by the position they are expanded in; keys are lower-case
+// without a trailing period. The same letters mean different things
+// elsewhere, as in "St James Road" or "Apt N".
+var (
+	// streetTypes end the street name.
+	streetTypes = map[string]string{
+		"st":   "Street",
+		"str":  "Street",
+		"ave":  "Avenue",
+		"av":   "Avenue",
+		"rd":   "Road",
+		"blvd": "Boulevard",
+		"dr":   "Drive",
+		"ln":   "Lane",
+		"ct":   "Court",
+		"pl":   "Place",
+		"sq":   "Square",
+		"hwy":  "Highway",
+		"pkwy": "Parkway",
+		"cir":  "Circle",
+		"ter":  "Terrace",
+	}
+	// directionals lead or trail the street name.
+	directionals = map[string]string{
+		"n":  "North",
+		"s":  "South",
+		"e":  "East",
+		"w":  "West",
+		"ne": "Northeast",
+		"nw": "Northwest",
+		"se": "Southeast",
+		"sw": "Southwest",
+	}
+	// unitDesignators start the unit part of the line.
+	unitDesignators = map[string]string{
+		"apt": "Apartment",
+		"ste": "Suite",
+		"fl":  "Floor",
+	}
+)
+
+// normalizeStreet collapses whitespace, expands abbreviations and title-cases
+// words. Tokens containing digits (house and unit numbers) are upper-cased.
+// Only the street type ending the street name, a directional leading or
+// trailing it, and the designator starting a unit are expanded.
+func normalizeStreet(street string) string {
+	words := strings.Fields(street)
+	for i, w := range words {
+		if strings.IndexFunc(w, unicode.IsDigit) >= 0 {
+			words[i] = strings.ToUpper(w)
+		} else {
+			words[i] = titleWord(w)
+		}
+	}
+
+	// Comma-separated parts after the first can only be units, e.g. ", Apt 4"
+	start := 0
+	for i := range words {
+		if i == len(words)-1 || strings.HasSuffix(words[i], ",") {
+			if start == 0 {
+				expandStreetLine(words[:i+1])
+			} else {
+				expandWord(words, start, unitDesignators)
+			}
+			start = i + 1
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// expandStreetLine expands the abbreviations of "123 N Main St NW Apt 4".
+func expandStreetLine(words []string) {
+	// The unit, if any, follows the street name
+	end := len(words)
+	for i := 1; i < len(words); i++ {
+		if _, ok := unitDesignators[abbreviation(words[i])]; ok {
+			expandWord(words, i, unitDesignators)
+			end = i
+			break
+		}
+	}
+
+	// Skip the house number
+	first := 0
+	if first < end && strings.IndexFunc(words[first], unicode.IsDigit) >= 0 {
+		first++
+	}
+	name := words[first:end]
+
+	// A directional needs a name and street type beside it; in "123 E St" or
+	// "N Main" the letter is the name
+	if len(name) >= 3 && expandWord(name, len(name)-1, directionals) {
+		name = name[:len(name)-1]
+	} else if len(name) >= 3 && expandWord(name, 0, directionals) {
+		name = name[1:]
+	}
+	if len(name) >= 2 {
+		expandWord(name, len(name)-1, streetTypes)
+	}
+}
+
+// expandWord replaces words[i] with its expansion, keeping a trailing comma,
+// and reports whether it had one.
+func expandWord(words []string, i int, expansions map[string]string) bool {
+	full, ok := expansions[abbreviation(words[i])]
+	if !ok {
+		return false
+	}
+	if strings.HasSuffix(words[i], ",") {
+		full += ","
+	}
+	words[i] = full
+	return true
+}
+
+func abbreviation(w string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSuffix(w, ","), "."))
+}
+
+// normalizeName collapses whitespace and title-cases each word.
+func normalizeName(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		words[i] = titleWord(w)
+	}
+	return strings.Join(words, " ")
+}
+
+func titleWord(w string) string {
+	runes := []rune(strings.ToLower(w))
+	capNext := true
+	for i, r := range runes {
+		if capNext && unicode.IsLetter(r) {
+			runes[i] = unicode.ToUpper(r)
+		}
+		capNext = r == '-' || r == '\''
+	}
+	return string(runes)
+}
+
+// compactPostal removes spaces and dashes and upper-cases the postal code.
+func compactPostal(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, s)
+}
@@ -0,0 +1,31 @@
+package address
+
+import "testing"
+
+func TestNormalizeStreet(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"123 main st", "123 Main Street"},
+		{"  123   Main   St.  ", "123 Main Street"},
+		{"123 St James Rd", "123 St James Road"},
+		{"123 N Main St", "123 North Main Street"},
+		{"45 Main St NW", "45 Main Street Northwest"},
+		{"123 E St", "123 E Street"},
+		{"9 N Main", "9 N Main"},
+		{"123 Main St, Apt N", "123 Main Street, Apartment N"},
+		{"123 Main St Apt 4b", "123 Main Street Apartment 4B"},
+		{"123 Main St, Ste 200, Fl 3", "123 Main Street, Suite 200, Floor 3"},
+		{"7 o'neil ln", "7 O'Neil Lane"},
+		{"Apt 5", "Apt 5"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := normalizeStreet(tt.in); got != tt.want {
+				t.Errorf("normalizeStreet(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
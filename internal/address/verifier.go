@@ -0,0 +1,22 @@
+package address
+
+import (
+	"context"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// Verifier confirms an address against an external source of truth, such as a
+// postal authority API. It may return a corrected address, field errors for an
+// undeliverable address, or an error if the check itself failed.
+type Verifier interface {
+	Verify(ctx context.Context, addr model.Address) (model.Address, []model.FieldError, error)
+}
+
+// StubVerifier accepts every address unchanged. It is used when no external
+// verifier is configured.
+type StubVerifier struct{}
+
+func (StubVerifier) Verify(ctx context.Context, addr model.Address) (model.Address, []model.FieldError, error) {
+	return addr, nil, nil
+}
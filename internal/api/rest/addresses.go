@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func (h *Handler) validateAddress(w http.ResponseWriter, r *http.Request) {
+	var addr model.Address
+	if err := decodeJSON(r, &addr); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	normalized, err := h.service.ValidateAddress(r.Context(), addr)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, normalized)
+}
+
+func (h *Handler) updateDeliveryAddress(w http.ResponseWriter, r *http.Request) {
+	var req model.UpdateAddressRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	req.DeliveryID = r.PathValue("id")
+	req.Actor = actorFrom(r)
+
+	delivery, err := h.service.UpdateDeliveryAddress(r.Context(), &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, delivery)
+}
+
+func (h *Handler) getAddressHistory(w http.ResponseWriter, r *http.Request) {
+	changes, err := h.service.GetAddressHistory(r.Context(), r.PathValue("id"), actorFrom(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"changes": changes})
+}
@@ -0,0 +1,25 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func (h *Handler) cancelDelivery(w http.ResponseWriter, r *http.Request) {
+	var req model.CancelDeliveryRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	req.DeliveryID = r.PathValue("id")
+	req.Actor = actorFrom(r)
+
+	delivery, err := h.service.CancelDelivery(r.Context(), &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, delivery)
+}
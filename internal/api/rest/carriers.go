@@ -0,0 +1,15 @@
+package rest
+
+import (
+	"net/http"
+)
+
+func (h *Handler) syncCarrierTracking(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.service.SyncCarrierTracking(r.Context(), r.PathValue("id"), actorFrom(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, delivery)
+}
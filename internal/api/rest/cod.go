@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func (h *Handler) getCODCollection(w http.ResponseWriter, r *http.Request) {
+	collection, err := h.service.GetCODCollection(r.Context(), r.PathValue("id"), actorFrom(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, collection)
+}
+
+func (h *Handler) getCashLedger(w http.ResponseWriter, r *http.Request) {
+	ledger, err := h.service.GetCashLedger(r.Context(), r.PathValue("id"), r.URL.Query().Get("date"), actorFrom(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ledger)
+}
+
+func (h *Handler) recordDeposit(w http.ResponseWriter, r *http.Request) {
+	var req model.DepositRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	req.CourierID = r.PathValue("id")
+	req.Actor = actorFrom(r)
+
+	entry, err := h.service.RecordDeposit(r.Context(), &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, entry)
+}
+
+func (h *Handler) reconcileCOD(w http.ResponseWriter, r *http.Request) {
+	var req model.ReconcileRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	req.CourierID = r.PathValue("id")
+	req.Actor = actorFrom(r)
+
+	reconciliations, err := h.service.ReconcileCOD(r.Context(), &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, reconciliations)
+}
+
+func (h *Handler) listDiscrepancies(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := model.DiscrepancyFilter{
+		CourierID: query.Get("courier_id"),
+		From:      query.Get("from"),
+		To:        query.Get("to"),
+	}
+
+	discrepancies, err := h.service.ListDiscrepancies(r.Context(), filter, actorFrom(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, discrepancies)
+}
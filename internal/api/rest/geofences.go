@@ -0,0 +1,33 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/bharathbbg/delivery-service/internal/geofence"
+)
+
+func (h *Handler) listGeofences(w http.ResponseWriter, r *http.Request) {
+	fences, err := h.service.ListHubGeofences(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"geofences": fences})
+}
+
+func (h *Handler) createGeofence(w http.ResponseWriter, r *http.Request) {
+	var fence geofence.Fence
+	if err := decodeJSON(r, &fence); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	created, err := h.service.CreateHubGeofence(r.Context(), &fence)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
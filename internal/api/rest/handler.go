@@ -0,0 +1,184 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/repository"
+	"github.com/bharathbbg/delivery-service/internal/service"
+)
+
+type Handler struct {
+	service *service.DeliveryService
+}
+
+func NewHandler(service *service.DeliveryService) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	// Tracking
+	mux.HandleFunc("GET /track/{tracking_number}", h.trackDelivery)
+
+	// Time slots
+	mux.HandleFunc("POST /slots/search", h.listAvailableSlots)
+	mux.HandleFunc("POST /slots/{id}/reservations", h.reserveSlot)
+	mux.HandleFunc("DELETE /slot-reservations/{id}", h.releaseSlot)
+
+	// Zones
+	mux.HandleFunc("POST /serviceability", h.checkServiceability)
+	mux.HandleFunc("GET /zones", h.listZones)
+	mux.HandleFunc("POST /zones", h.createZone)
+	mux.HandleFunc("PUT /zones/{id}", h.updateZone)
+
+	// Rate quotes
+	mux.HandleFunc("POST /quotes", h.quoteDelivery)
+
+	// Hubs
+	mux.HandleFunc("GET /hubs", h.listHubs)
+	mux.HandleFunc("POST /hubs", h.createHub)
+	mux.HandleFunc("GET /hubs/{id}", h.getHub)
+	mux.HandleFunc("PUT /hubs/{id}", h.updateHub)
+	mux.HandleFunc("POST /hubs/{id}/scans", h.scanAtHub)
+	mux.HandleFunc("GET /hubs/{id}/inventory", h.getHubInventory)
+	mux.HandleFunc("POST /hub-scans", h.ingestHubScans)
+
+	// Pickup points
+	mux.HandleFunc("GET /pickup-points", h.listPickupPoints)
+	mux.HandleFunc("POST /pickup-points", h.createPickupPoint)
+	mux.HandleFunc("GET /pickup-points/{id}", h.getPickupPoint)
+	mux.HandleFunc("PUT /pickup-points/{id}", h.updatePickupPoint)
+	mux.HandleFunc("POST /deliveries/{id}/redirect", h.redirectToPickupPoint)
+	mux.HandleFunc("POST /deliveries/{id}/pickup/hold", h.holdAtPickupPoint)
+	mux.HandleFunc("POST /deliveries/{id}/pickup/collect", h.collectFromPickupPoint)
+
+	// Addresses
+	mux.HandleFunc("POST /addresses/validate", h.validateAddress)
+	mux.HandleFunc("PUT /deliveries/{id}/address", h.updateDeliveryAddress)
+	mux.HandleFunc("GET /deliveries/{id}/address-history", h.getAddressHistory)
+
+	// Courier locations
+	mux.HandleFunc("POST /couriers/locations", h.ingestLocations)
+	mux.HandleFunc("GET /deliveries/{id}/location", h.getDeliveryLocation)
+
+	// Geofences
+	mux.HandleFunc("GET /geofences", h.listGeofences)
+	mux.HandleFunc("POST /geofences", h.createGeofence)
+
+	// Courier assignment and routes
+	mux.HandleFunc("PUT /deliveries/{id}/courier", h.assignCourier)
+	mux.HandleFunc("POST /couriers/{id}/routes", h.planRoute)
+	mux.HandleFunc("GET /couriers/{id}/routes/latest", h.getRoute)
+
+	// Shipping labels
+	mux.HandleFunc("GET /deliveries/{id}/label", h.getLabel)
+	mux.HandleFunc("GET /couriers/{id}/labels", h.getCourierLabels)
+
+	// Third-party carriers
+	mux.HandleFunc("POST /deliveries/{id}/carrier/sync", h.syncCarrierTracking)
+
+	// End-of-day manifests
+	mux.HandleFunc("POST /manifests", h.createManifest)
+	mux.HandleFunc("GET /manifests/{id}", h.getManifest)
+	mux.HandleFunc("POST /manifests/{id}/close", h.closeManifest)
+	mux.HandleFunc("GET /couriers/{id}/manifests", h.listManifests)
+
+	// Cash on delivery
+	mux.HandleFunc("GET /deliveries/{id}/cod", h.getCODCollection)
+	mux.HandleFunc("GET /couriers/{id}/cash", h.getCashLedger)
+	mux.HandleFunc("POST /couriers/{id}/cash/deposits", h.recordDeposit)
+	mux.HandleFunc("POST /couriers/{id}/cod/reconciliations", h.reconcileCOD)
+	mux.HandleFunc("GET /cod/discrepancies", h.listDiscrepancies)
+
+	// Proof of delivery
+	mux.HandleFunc("POST /deliveries/{id}/proof", h.submitProof)
+	mux.HandleFunc("GET /deliveries/{id}/proof", h.getProof)
+	mux.HandleFunc("GET /deliveries/{id}/proof/{kind}", h.getProofAttachment)
+
+	// Recipient PIN verification
+	mux.HandleFunc("POST /deliveries/{id}/otp", h.issueOTP)
+	mux.HandleFunc("POST /deliveries/{id}/otp/verify", h.verifyOTP)
+
+	// Delivery attempts
+	mux.HandleFunc("POST /deliveries/{id}/attempts", h.recordFailedAttempt)
+
+	// Cancellation
+	mux.HandleFunc("POST /deliveries/{id}/cancel", h.cancelDelivery)
+
+	// Returns
+	mux.HandleFunc("POST /deliveries/{id}/returns", h.createReturn)
+
+	// Parcels
+	mux.HandleFunc("GET /deliveries/{id}/parcels", h.listParcels)
+	mux.HandleFunc("POST /deliveries/{id}/parcels", h.addParcel)
+	mux.HandleFunc("PUT /deliveries/{id}/parcels/{parcel_id}/status", h.updateParcelStatus)
+}
+
+type errorResponse struct {
+	Code   string             `json:"code,omitempty"`
+	Error  string             `json:"error"`
+	Fields []model.FieldError `json:"fields,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	resp := errorResponse{Error: err.Error()}
+	var serviceErr *service.Error
+	if errors.As(err, &serviceErr) {
+		resp.Code = serviceErr.Code
+		resp.Fields = serviceErr.Fields
+	}
+	writeJSON(w, statusFor(err), resp)
+}
+
+func statusFor(err error) int {
+	var serviceErr *service.Error
+	if errors.As(err, &serviceErr) {
+		switch serviceErr.Code {
+		case service.CodeNotServiceable, service.CodeInvalidAddress, service.CodeRateUnavailable:
+			return http.StatusUnprocessableEntity
+		case service.CodeNotFound:
+			return http.StatusNotFound
+		case service.CodeLocationUnavailable, service.CodeInvalidTransition:
+			return http.StatusConflict
+		case service.CodeForbidden:
+			return http.StatusForbidden
+		case service.CodeVerificationRequired, service.CodeInvalidPIN, service.CodeCODRequired:
+			return http.StatusUnprocessableEntity
+		case service.CodePINLocked:
+			return http.StatusTooManyRequests
+		case service.CodeCarrierUnavailable:
+			return http.StatusBadGateway
+		default:
+			return http.StatusBadRequest
+		}
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrSlotFull), errors.Is(err, repository.ErrProofExists),
+		errors.Is(err, repository.ErrPickupPointFull), errors.Is(err, repository.ErrDeliveryLocked),
+		errors.Is(err, repository.ErrManifestClosed), errors.Is(err, repository.ErrManifestChanged),
+		errors.Is(err, repository.ErrScanStale):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrReservationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrReservationExpired):
+		return http.StatusGone
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+func decodeJSON(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return errors.New("invalid request body")
+	}
+	return nil
+}
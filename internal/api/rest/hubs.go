@@ -0,0 +1,106 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func (h *Handler) listHubs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	hubs, err := h.service.ListHubs(r.Context(), query.Get("kind"), query.Get("zone_id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"hubs": hubs})
+}
+
+func (h *Handler) getHub(w http.ResponseWriter, r *http.Request) {
+	hub, err := h.service.GetHub(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, hub)
+}
+
+func (h *Handler) createHub(w http.ResponseWriter, r *http.Request) {
+	var hub model.Hub
+	if err := decodeJSON(r, &hub); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	created, err := h.service.CreateHub(r.Context(), &hub)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (h *Handler) updateHub(w http.ResponseWriter, r *http.Request) {
+	var hub model.Hub
+	if err := decodeJSON(r, &hub); err != nil {
+		writeError(w, err)
+		return
+	}
+	hub.ID = r.PathValue("id")
+
+	updated, err := h.service.UpdateHub(r.Context(), &hub)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+func (h *Handler) scanAtHub(w http.ResponseWriter, r *http.Request) {
+	var scan model.HubScan
+	if err := decodeJSON(r, &scan); err != nil {
+		writeError(w, err)
+		return
+	}
+	scan.HubID = r.PathValue("id")
+	scan.Actor = actorFrom(r)
+
+	delivery, err := h.service.ScanAtHub(r.Context(), &scan)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, delivery)
+}
+
+func (h *Handler) getHubInventory(w http.ResponseWriter, r *http.Request) {
+	inventory, err := h.service.GetHubInventory(r.Context(), r.PathValue("id"), actorFrom(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, inventory)
+}
+
+func (h *Handler) ingestHubScans(w http.ResponseWriter, r *http.Request) {
+	var req model.BulkScanRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	req.Actor = actorFrom(r)
+
+	resp, err := h.service.IngestHubScans(r.Context(), &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
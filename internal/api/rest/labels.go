@@ -0,0 +1,44 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/bharathbbg/delivery-service/internal/label"
+)
+
+func (h *Handler) getLabel(w http.ResponseWriter, r *http.Request) {
+	id, format := r.PathValue("id"), labelFormat(r)
+	body, err := h.service.RenderLabel(r.Context(), id, format, actorFrom(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeLabel(w, fmt.Sprintf("label-%s", id), format, body)
+}
+
+func (h *Handler) getCourierLabels(w http.ResponseWriter, r *http.Request) {
+	id, format := r.PathValue("id"), labelFormat(r)
+	body, err := h.service.RenderCourierLabels(r.Context(), id, format, actorFrom(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeLabel(w, fmt.Sprintf("labels-courier-%s", id), format, body)
+}
+
+func labelFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format
+	}
+	return label.FormatPDF
+}
+
+func writeLabel(w http.ResponseWriter, name, format string, body []byte) {
+	w.Header().Set("Content-Type", label.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", name+"."+format))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
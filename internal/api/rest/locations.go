@@ -0,0 +1,35 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func (h *Handler) ingestLocations(w http.ResponseWriter, r *http.Request) {
+	var req model.IngestLocationsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	req.Actor = actorFrom(r)
+
+	resp, err := h.service.IngestLocations(r.Context(), &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, resp)
+}
+
+func (h *Handler) getDeliveryLocation(w http.ResponseWriter, r *http.Request) {
+	loc, err := h.service.GetDeliveryLocation(r.Context(), r.PathValue("id"), actorFrom(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, loc)
+}
@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bharathbbg/delivery-service/internal/manifest"
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func (h *Handler) createManifest(w http.ResponseWriter, r *http.Request) {
+	var req model.CreateManifestRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	req.Actor = actorFrom(r)
+
+	m, err := h.service.CreateManifest(r.Context(), &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, m)
+}
+
+// getManifest returns the manifest as JSON, or exports it when ?format=csv or
+// ?format=pdf is given.
+func (h *Handler) getManifest(w http.ResponseWriter, r *http.Request) {
+	id, format := r.PathValue("id"), strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" || format == "json" {
+		m, err := h.service.GetManifest(r.Context(), id, actorFrom(r))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, m)
+		return
+	}
+
+	body, err := h.service.RenderManifest(r.Context(), id, format, actorFrom(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", manifest.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "manifest-"+id+"."+format))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+func (h *Handler) closeManifest(w http.ResponseWriter, r *http.Request) {
+	m, err := h.service.CloseManifest(r.Context(), r.PathValue("id"), actorFrom(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, m)
+}
+
+func (h *Handler) listManifests(w http.ResponseWriter, r *http.Request) {
+	manifests, err := h.service.ListManifests(r.Context(), r.PathValue("id"), r.URL.Query().Get("date"), actorFrom(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, manifests)
+}
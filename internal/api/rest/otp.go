@@ -0,0 +1,34 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func (h *Handler) issueOTP(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.IssueOTP(r.Context(), r.PathValue("id"), actorFrom(r)); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *Handler) verifyOTP(w http.ResponseWriter, r *http.Request) {
+	var req model.VerifyOTPRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	req.DeliveryID = r.PathValue("id")
+	req.Actor = actorFrom(r)
+
+	resp, err := h.service.VerifyOTP(r.Context(), &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
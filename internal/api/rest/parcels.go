@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func (h *Handler) listParcels(w http.ResponseWriter, r *http.Request) {
+	parcels, err := h.service.ListParcels(r.Context(), r.PathValue("id"), actorFrom(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"parcels": parcels})
+}
+
+func (h *Handler) addParcel(w http.ResponseWriter, r *http.Request) {
+	var parcel model.Parcel
+	if err := decodeJSON(r, &parcel); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	created, err := h.service.AddParcel(r.Context(), r.PathValue("id"), &parcel, actorFrom(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (h *Handler) updateParcelStatus(w http.ResponseWriter, r *http.Request) {
+	var req model.UpdateParcelRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	req.DeliveryID = r.PathValue("id")
+	req.ParcelID = r.PathValue("parcel_id")
+	req.Actor = actorFrom(r)
+
+	delivery, err := h.service.UpdateParcelStatus(r.Context(), &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, delivery)
+}
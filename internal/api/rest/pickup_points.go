@@ -0,0 +1,106 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func (h *Handler) listPickupPoints(w http.ResponseWriter, r *http.Request) {
+	points, err := h.service.ListPickupPoints(r.Context(), r.URL.Query().Get("zone_id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"pickup_points": points})
+}
+
+func (h *Handler) getPickupPoint(w http.ResponseWriter, r *http.Request) {
+	point, err := h.service.GetPickupPoint(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, point)
+}
+
+func (h *Handler) createPickupPoint(w http.ResponseWriter, r *http.Request) {
+	var point model.PickupPoint
+	if err := decodeJSON(r, &point); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	created, err := h.service.CreatePickupPoint(r.Context(), &point)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (h *Handler) updatePickupPoint(w http.ResponseWriter, r *http.Request) {
+	var point model.PickupPoint
+	if err := decodeJSON(r, &point); err != nil {
+		writeError(w, err)
+		return
+	}
+	point.ID = r.PathValue("id")
+
+	updated, err := h.service.UpdatePickupPoint(r.Context(), &point)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+func (h *Handler) redirectToPickupPoint(w http.ResponseWriter, r *http.Request) {
+	var req model.RedirectRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	req.DeliveryID = r.PathValue("id")
+	req.Actor = actorFrom(r)
+
+	delivery, err := h.service.RedirectToPickupPoint(r.Context(), &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, delivery)
+}
+
+func (h *Handler) holdAtPickupPoint(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.service.HoldAtPickupPoint(r.Context(), r.PathValue("id"), actorFrom(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, delivery)
+}
+
+func (h *Handler) collectFromPickupPoint(w http.ResponseWriter, r *http.Request) {
+	var req model.CollectRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	req.DeliveryID = r.PathValue("id")
+	req.Actor = actorFrom(r)
+
+	delivery, err := h.service.CollectFromPickupPoint(r.Context(), &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, delivery)
+}
@@ -0,0 +1,117 @@
+package rest
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/service"
+)
+
+// actorFrom reads the caller identity forwarded by the API gateway.
+func actorFrom(r *http.Request) model.Actor {
+	return model.Actor{
+		ID:   r.Header.Get("X-Actor-ID"),
+		Role: r.Header.Get("X-Actor-Role"),
+	}
+}
+
+func (h *Handler) submitProof(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 2*service.MaxAttachmentBytes+1<<20)
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		writeError(w, errors.New("invalid multipart body"))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	req := model.SubmitProofRequest{
+		DeliveryID:    r.PathValue("id"),
+		Actor:         actorFrom(r),
+		RecipientName: r.FormValue("recipient_name"),
+		PIN:           r.FormValue("pin"),
+	}
+
+	if lat, lng := r.FormValue("latitude"), r.FormValue("longitude"); lat != "" || lng != "" {
+		latitude, err := strconv.ParseFloat(lat, 64)
+		if err != nil {
+			writeError(w, errors.New("invalid latitude"))
+			return
+		}
+		longitude, err := strconv.ParseFloat(lng, 64)
+		if err != nil {
+			writeError(w, errors.New("invalid longitude"))
+			return
+		}
+		req.Latitude, req.Longitude = &latitude, &longitude
+	}
+
+	if amount := r.FormValue("cod_amount_cents"); amount != "" {
+		cents, err := strconv.ParseInt(amount, 10, 64)
+		if err != nil {
+			writeError(w, errors.New("invalid cod_amount_cents"))
+			return
+		}
+		req.COD = &model.CODPayment{
+			AmountCents: cents,
+			Currency:    r.FormValue("cod_currency"),
+			Method:      r.FormValue("cod_method"),
+			Reference:   r.FormValue("cod_reference"),
+		}
+	}
+
+	var err error
+	if req.Signature, err = formAttachment(r, model.AttachmentSignature); err != nil {
+		writeError(w, err)
+		return
+	}
+	if req.Photo, err = formAttachment(r, model.AttachmentPhoto); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	proof, err := h.service.SubmitProof(r.Context(), &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, proof)
+}
+
+// formAttachment returns the named file part, or nil if it was not sent.
+func formAttachment(r *http.Request, name string) (*model.ProofAttachment, error) {
+	file, header, err := r.FormFile(name)
+	if err != nil {
+		if errors.Is(err, http.ErrMissingFile) {
+			return nil, nil
+		}
+		return nil, errors.New("invalid " + name + " upload")
+	}
+	return &model.ProofAttachment{ContentType: header.Header.Get("Content-Type"), Body: file}, nil
+}
+
+func (h *Handler) getProof(w http.ResponseWriter, r *http.Request) {
+	proof, err := h.service.GetProof(r.Context(), r.PathValue("id"), actorFrom(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, proof)
+}
+
+func (h *Handler) getProofAttachment(w http.ResponseWriter, r *http.Request) {
+	body, contentType, err := h.service.OpenProofAttachment(r.Context(), r.PathValue("id"), r.PathValue("kind"), actorFrom(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, body)
+}
@@ -0,0 +1,25 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func (h *Handler) createReturn(w http.ResponseWriter, r *http.Request) {
+	var req model.CreateReturnRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	req.DeliveryID = r.PathValue("id")
+	req.Actor = actorFrom(r)
+
+	delivery, err := h.service.CreateReturn(r.Context(), &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, delivery)
+}
@@ -0,0 +1,53 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func (h *Handler) assignCourier(w http.ResponseWriter, r *http.Request) {
+	var req model.AssignCourierRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	req.DeliveryID = r.PathValue("id")
+	req.Actor = actorFrom(r)
+
+	delivery, err := h.service.AssignCourier(r.Context(), &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, delivery)
+}
+
+func (h *Handler) planRoute(w http.ResponseWriter, r *http.Request) {
+	var req model.PlanRouteRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	req.CourierID = r.PathValue("id")
+	req.Actor = actorFrom(r)
+
+	plan, err := h.service.PlanRoute(r.Context(), &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, plan)
+}
+
+func (h *Handler) getRoute(w http.ResponseWriter, r *http.Request) {
+	plan, err := h.service.GetRoute(r.Context(), r.PathValue("id"), actorFrom(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, plan)
+}
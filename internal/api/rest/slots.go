@@ -0,0 +1,42 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func (h *Handler) listAvailableSlots(w http.ResponseWriter, r *http.Request) {
+	var req model.ListSlotsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	slots, err := h.service.ListAvailableSlots(r.Context(), &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"slots": slots})
+}
+
+func (h *Handler) reserveSlot(w http.ResponseWriter, r *http.Request) {
+	reservation, err := h.service.ReserveSlot(r.Context(), r.PathValue("id"), actorFrom(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, reservation)
+}
+
+func (h *Handler) releaseSlot(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.ReleaseSlot(r.Context(), r.PathValue("id"), actorFrom(r)); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
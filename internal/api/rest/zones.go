@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func (h *Handler) checkServiceability(w http.ResponseWriter, r *http.Request) {
+	var req model.ServiceabilityRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp, err := h.service.CheckServiceability(r.Context(), req.Address)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) listZones(w http.ResponseWriter, r *http.Request) {
+	zones, err := h.service.ListZones(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"zones": zones})
+}
+
+func (h *Handler) createZone(w http.ResponseWriter, r *http.Request) {
+	var zone model.Zone
+	if err := decodeJSON(r, &zone); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	created, err := h.service.CreateZone(r.Context(), &zone)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (h *Handler) updateZone(w http.ResponseWriter, r *http.Request) {
+	var zone model.Zone
+	if err := decodeJSON(r, &zone); err != nil {
+		writeError(w, err)
+		return
+	}
+	zone.ID = r.PathValue("id")
+
+	updated, err := h.service.UpdateZone(r.Context(), &zone)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
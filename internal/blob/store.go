@@ -0,0 +1,18 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when a key has no stored object.
+var ErrNotFound = errors.New("blob not found")
+
+// Store keeps opaque binary objects such as signatures and photos. Keys are
+// slash-separated paths chosen by the caller.
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,57 @@
+// Package carrier hands deliveries to third-party carriers in regions and
+// service levels we do not cover ourselves.
+package carrier
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+var (
+	ErrShipmentNotFound = errors.New("carrier does not know the shipment")
+	ErrNotCancellable   = errors.New("carrier can no longer cancel the shipment")
+)
+
+// Label formats carriers are asked for; they match the in-house labels.
+const (
+	LabelPDF = "pdf"
+	LabelZPL = "zpl"
+)
+
+// Shipment is what we ask a carrier to deliver.
+type Shipment struct {
+	Reference    string          `json:"reference"` // our tracking number
+	OrderID      string          `json:"order_id"`
+	ServiceLevel string          `json:"service_level"`
+	Recipient    model.Address   `json:"recipient"`
+	Parcels      []*model.Parcel `json:"parcels"`
+}
+
+// Booking is the carrier's acceptance of a shipment.
+type Booking struct {
+	TrackingNumber string `json:"tracking_number"`
+}
+
+// Event is one tracking update from a carrier. Code is the carrier's own
+// status; Status is our delivery status or informational event for it.
+type Event struct {
+	ID          string    `json:"id"` // stable across fetches, used to skip updates already recorded
+	Code        string    `json:"code"`
+	Status      string    `json:"-"`
+	Description string    `json:"description"`
+	Location    string    `json:"location"`
+	Timestamp   time.Time `json:"occurred_at"`
+}
+
+// Carrier is a third-party carrier's API.
+type Carrier interface {
+	CreateShipment(ctx context.Context, shipment *Shipment) (*Booking, error)
+	CancelShipment(ctx context.Context, trackingNumber string) error
+	// FetchTracking returns every update for a shipment, oldest first, with
+	// Status normalized.
+	FetchTracking(ctx context.Context, trackingNumber string) ([]Event, error)
+	GetLabel(ctx context.Context, trackingNumber, format string) ([]byte, error)
+}
@@ -0,0 +1,213 @@
+// Package carriertest provides an in-memory carrier that speaks the API
+// carrier.HTTPCarrier expects, for tests and local development.
+package carriertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/carrier"
+	"github.com/bharathbbg/delivery-service/internal/pdf"
+)
+
+// Server is a mock carrier listening on a local port.
+type Server struct {
+	*httptest.Server
+	*Handler
+}
+
+// NewServer starts a mock carrier. Requests must carry apiKey as a bearer
+// token unless it is empty. Close the server when done.
+func NewServer(apiKey string) *Server {
+	h := NewHandler(apiKey)
+	return &Server{Server: httptest.NewServer(h), Handler: h}
+}
+
+// Shipment is a shipment as the mock carrier sees it.
+type Shipment struct {
+	carrier.Shipment
+	TrackingNumber string
+	Cancelled      bool
+	Events         []carrier.Event
+}
+
+// Handler serves the mock carrier API from memory.
+type Handler struct {
+	apiKey string
+	mux    *http.ServeMux
+
+	mu        sync.Mutex
+	shipments map[string]*Shipment
+	issued    int
+	events    int
+}
+
+func NewHandler(apiKey string) *Handler {
+	h := &Handler{
+		apiKey:    apiKey,
+		mux:       http.NewServeMux(),
+		shipments: make(map[string]*Shipment),
+	}
+	h.mux.HandleFunc("POST /v1/shipments", h.createShipment)
+	h.mux.HandleFunc("POST /v1/shipments/{tracking_number}/cancel", h.cancelShipment)
+	h.mux.HandleFunc("GET /v1/shipments/{tracking_number}/events", h.listEvents)
+	h.mux.HandleFunc("GET /v1/shipments/{tracking_number}/label", h.getLabel)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.apiKey != "" && r.Header.Get("Authorization") != "Bearer "+h.apiKey {
+		http.Error(w, "invalid API key", http.StatusUnauthorized)
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// Push records a tracking update for a shipment, as the carrier's network
+// would.
+func (h *Handler) Push(trackingNumber, code, description, location string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.shipments[trackingNumber]
+	if !ok {
+		return carrier.ErrShipmentNotFound
+	}
+	h.push(s, code, description, location)
+	return nil
+}
+
+// Shipment returns a copy of a shipment, if the carrier has it.
+func (h *Handler) Shipment(trackingNumber string) (Shipment, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.shipments[trackingNumber]
+	if !ok {
+		return Shipment{}, false
+	}
+	copied := *s
+	copied.Events = append([]carrier.Event(nil), s.Events...)
+	return copied, true
+}
+
+func (h *Handler) push(s *Shipment, code, description, location string) {
+	h.events++
+	s.Events = append(s.Events, carrier.Event{
+		ID:          fmt.Sprintf("EV%08d", h.events),
+		Code:        code,
+		Description: description,
+		Location:    location,
+		Timestamp:   time.Now().UTC(),
+	})
+}
+
+func (h *Handler) createShipment(w http.ResponseWriter, r *http.Request) {
+	var req carrier.Shipment
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid shipment", http.StatusBadRequest)
+		return
+	}
+	if req.Reference == "" || len(req.Parcels) == 0 {
+		http.Error(w, "reference and parcels are required", http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	h.issued++
+	s := &Shipment{Shipment: req, TrackingNumber: fmt.Sprintf("MC%010d", h.issued)}
+	h.shipments[s.TrackingNumber] = s
+	h.push(s, "LABEL_CREATED", "Shipment information received", "")
+	h.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, carrier.Booking{TrackingNumber: s.TrackingNumber})
+}
+
+// cancelShipment accepts cancellations until the carrier has picked the
+// shipment up.
+func (h *Handler) cancelShipment(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.shipments[r.PathValue("tracking_number")]
+	if !ok {
+		http.Error(w, "shipment not found", http.StatusNotFound)
+		return
+	}
+	if s.Cancelled {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	for _, e := range s.Events {
+		if e.Code != "LABEL_CREATED" {
+			http.Error(w, "shipment is already moving", http.StatusConflict)
+			return
+		}
+	}
+
+	s.Cancelled = true
+	h.push(s, "CANCELLED", "Shipment cancelled by shipper", "")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) listEvents(w http.ResponseWriter, r *http.Request) {
+	s, ok := h.Shipment(r.PathValue("tracking_number"))
+	if !ok {
+		http.Error(w, "shipment not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]carrier.Event{"events": s.Events})
+}
+
+func (h *Handler) getLabel(w http.ResponseWriter, r *http.Request) {
+	s, ok := h.Shipment(r.PathValue("tracking_number"))
+	if !ok {
+		http.Error(w, "shipment not found", http.StatusNotFound)
+		return
+	}
+
+	var buf bytes.Buffer
+	switch format := r.URL.Query().Get("format"); format {
+	case carrier.LabelPDF:
+		if err := renderPDF(&buf, s); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+	case carrier.LabelZPL:
+		fmt.Fprintf(&buf, "^XA^FO60,60^A0N,40,40^FDMOCK CARRIER^FS^FO60,140^BY3^BCN,180,Y,N,N^FD%s^FS^XZ\n", s.TrackingNumber)
+		w.Header().Set("Content-Type", "application/zpl")
+	default:
+		http.Error(w, "unsupported label format", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// renderPDF draws a 4x6 inch label with the carrier's tracking barcode.
+func renderPDF(buf *bytes.Buffer, s Shipment) error {
+	var c pdf.Content
+	c.Text(pdf.FontBold, 20, 20, 390, "MOCK CARRIER")
+	c.Text(pdf.FontRegular, 11, 20, 360, "Ref "+s.Reference)
+	c.Text(pdf.FontRegular, 11, 20, 344, s.Recipient.City+" "+s.Recipient.ZipCode)
+	if err := c.Code128(s.TrackingNumber, 20, 200, 248, 100); err != nil {
+		return err
+	}
+	c.Text(pdf.FontRegular, 12, 20, 184, s.TrackingNumber)
+
+	doc := pdf.NewDocument()
+	doc.AddPage(288, 432, &c)
+	return doc.Write(buf)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
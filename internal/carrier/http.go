@@ -0,0 +1,129 @@
+package carrier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HTTPCarrier talks to a carrier over a JSON API:
+//
+//	POST /v1/shipments                         Shipment -> 201 Booking
+//	POST /v1/shipments/{tracking_number}/cancel         -> 200, or 409 once it cannot be cancelled
+//	GET  /v1/shipments/{tracking_number}/events         -> {"events": [Event, ...]}
+//	GET  /v1/shipments/{tracking_number}/label?format=  -> the label document
+//
+// Unknown shipments answer 404. Requests carry the API key as a bearer token.
+type HTTPCarrier struct {
+	baseURL  string
+	apiKey   string
+	statuses StatusMap
+	client   *http.Client
+}
+
+func NewHTTPCarrier(baseURL, apiKey string, statuses StatusMap) *HTTPCarrier {
+	if statuses == nil {
+		statuses = DefaultStatuses
+	}
+	return &HTTPCarrier{
+		baseURL:  baseURL,
+		apiKey:   apiKey,
+		statuses: statuses,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *HTTPCarrier) CreateShipment(ctx context.Context, shipment *Shipment) (*Booking, error) {
+	body, err := json.Marshal(shipment)
+	if err != nil {
+		return nil, err
+	}
+
+	var booking Booking
+	if err := c.do(ctx, http.MethodPost, "/v1/shipments", body, &booking); err != nil {
+		return nil, fmt.Errorf("error creating carrier shipment: %w", err)
+	}
+	if booking.TrackingNumber == "" {
+		return nil, fmt.Errorf("carrier returned no tracking number")
+	}
+	return &booking, nil
+}
+
+func (c *HTTPCarrier) CancelShipment(ctx context.Context, trackingNumber string) error {
+	if err := c.do(ctx, http.MethodPost, shipmentPath(trackingNumber, "cancel"), nil, nil); err != nil {
+		return fmt.Errorf("error cancelling carrier shipment: %w", err)
+	}
+	return nil
+}
+
+func (c *HTTPCarrier) FetchTracking(ctx context.Context, trackingNumber string) ([]Event, error) {
+	var resp struct {
+		Events []Event `json:"events"`
+	}
+	if err := c.do(ctx, http.MethodGet, shipmentPath(trackingNumber, "events"), nil, &resp); err != nil {
+		return nil, fmt.Errorf("error fetching carrier tracking: %w", err)
+	}
+
+	for i := range resp.Events {
+		resp.Events[i].Status = c.statuses.Normalize(resp.Events[i].Code)
+	}
+	return resp.Events, nil
+}
+
+func (c *HTTPCarrier) GetLabel(ctx context.Context, trackingNumber, format string) ([]byte, error) {
+	var label bytes.Buffer
+	path := shipmentPath(trackingNumber, "label") + "?format=" + url.QueryEscape(format)
+	if err := c.do(ctx, http.MethodGet, path, nil, &label); err != nil {
+		return nil, fmt.Errorf("error fetching carrier label: %w", err)
+	}
+	return label.Bytes(), nil
+}
+
+// do sends a request and decodes a JSON response into out, or copies the raw
+// body when out is a *bytes.Buffer.
+func (c *HTTPCarrier) do(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrShipmentNotFound
+	case resp.StatusCode == http.StatusConflict:
+		return ErrNotCancellable
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("carrier returned %s", resp.Status)
+	}
+
+	switch out := out.(type) {
+	case nil:
+		return nil
+	case *bytes.Buffer:
+		_, err = io.Copy(out, resp.Body)
+		return err
+	default:
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+}
+
+func shipmentPath(trackingNumber, action string) string {
+	return "/v1/shipments/" + url.PathEscape(trackingNumber) + "/" + action
+}
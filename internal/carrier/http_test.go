@@ -0,0 +1,175 @@
+package carrier_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bharathbbg/delivery-service/internal/carrier"
+	"github.com/bharathbbg/delivery-service/internal/carrier/carriertest"
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+const apiKey = "secret"
+
+func newCarrier(t *testing.T) (*carrier.HTTPCarrier, *carriertest.Server) {
+	t.Helper()
+	srv := carriertest.NewServer(apiKey)
+	t.Cleanup(srv.Close)
+	statuses, err := carrier.ParseStatusMap("SCAN=IN_TRANSIT")
+	if err != nil {
+		t.Fatalf("ParseStatusMap: %v", err)
+	}
+	return carrier.NewHTTPCarrier(srv.URL, apiKey, statuses), srv
+}
+
+func book(t *testing.T, c carrier.Carrier) string {
+	t.Helper()
+	booking, err := c.CreateShipment(context.Background(), &carrier.Shipment{
+		Reference: "TRK-0000000001",
+		OrderID:   "order-1",
+		Recipient: model.Address{City: "Springfield", ZipCode: "12345"},
+		Parcels:   []*model.Parcel{{ID: "parcel-1"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateShipment: %v", err)
+	}
+	return booking.TrackingNumber
+}
+
+func TestCreateShipment(t *testing.T) {
+	c, srv := newCarrier(t)
+
+	tn := book(t, c)
+	if tn == "" {
+		t.Fatal("CreateShipment returned no tracking number")
+	}
+	s, ok := srv.Shipment(tn)
+	if !ok {
+		t.Fatalf("carrier has no shipment %s", tn)
+	}
+	if s.Reference != "TRK-0000000001" || s.OrderID != "order-1" || s.Recipient.City != "Springfield" {
+		t.Errorf("carrier received %+v", s.Shipment)
+	}
+
+	if _, err := c.CreateShipment(context.Background(), &carrier.Shipment{Reference: "TRK-2"}); err == nil {
+		t.Error("CreateShipment without parcels succeeded")
+	}
+}
+
+func TestCreateShipmentRequiresAPIKey(t *testing.T) {
+	_, srv := newCarrier(t)
+	c := carrier.NewHTTPCarrier(srv.URL, "wrong", nil)
+
+	_, err := c.CreateShipment(context.Background(), &carrier.Shipment{
+		Reference: "TRK-1",
+		Parcels:   []*model.Parcel{{ID: "parcel-1"}},
+	})
+	if err == nil {
+		t.Fatal("CreateShipment with a wrong API key succeeded")
+	}
+}
+
+func TestCancelShipment(t *testing.T) {
+	c, srv := newCarrier(t)
+	ctx := context.Background()
+
+	tn := book(t, c)
+	if err := c.CancelShipment(ctx, tn); err != nil {
+		t.Fatalf("CancelShipment: %v", err)
+	}
+	if s, _ := srv.Shipment(tn); !s.Cancelled {
+		t.Error("shipment was not cancelled")
+	}
+	// Cancelling twice is not an error
+	if err := c.CancelShipment(ctx, tn); err != nil {
+		t.Errorf("second CancelShipment: %v", err)
+	}
+
+	moving := book(t, c)
+	if err := srv.Push(moving, "PICKED_UP", "Picked up", "Depot"); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if err := c.CancelShipment(ctx, moving); !errors.Is(err, carrier.ErrNotCancellable) {
+		t.Errorf("CancelShipment after pickup = %v, want ErrNotCancellable", err)
+	}
+
+	if err := c.CancelShipment(ctx, "MC9999999999"); !errors.Is(err, carrier.ErrShipmentNotFound) {
+		t.Errorf("CancelShipment of unknown shipment = %v, want ErrShipmentNotFound", err)
+	}
+}
+
+func TestFetchTracking(t *testing.T) {
+	c, srv := newCarrier(t)
+	ctx := context.Background()
+
+	tn := book(t, c)
+	for _, code := range []string{"PICKED_UP", "SCAN", "HELD_AT_CUSTOMS", "ofd", "DELIVERED"} {
+		if err := srv.Push(tn, code, "", ""); err != nil {
+			t.Fatalf("Push(%s): %v", code, err)
+		}
+	}
+
+	events, err := c.FetchTracking(ctx, tn)
+	if err != nil {
+		t.Fatalf("FetchTracking: %v", err)
+	}
+	want := []struct{ code, status string }{
+		{"LABEL_CREATED", model.EventCarrierUpdate},
+		{"PICKED_UP", model.StatusInTransit},
+		{"SCAN", model.StatusInTransit},
+		{"HELD_AT_CUSTOMS", model.EventCarrierUpdate},
+		{"ofd", model.StatusOutForDelivery},
+		{"DELIVERED", model.StatusDelivered},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("FetchTracking returned %d events, want %d", len(events), len(want))
+	}
+	seen := make(map[string]bool)
+	for i, e := range events {
+		if e.Code != want[i].code || e.Status != want[i].status {
+			t.Errorf("event %d = %s/%s, want %s/%s", i, e.Code, e.Status, want[i].code, want[i].status)
+		}
+		if e.ID == "" || seen[e.ID] {
+			t.Errorf("event %d has missing or repeated ID %q", i, e.ID)
+		}
+		seen[e.ID] = true
+	}
+
+	if _, err := c.FetchTracking(ctx, "MC9999999999"); !errors.Is(err, carrier.ErrShipmentNotFound) {
+		t.Errorf("FetchTracking of unknown shipment = %v, want ErrShipmentNotFound", err)
+	}
+}
+
+func TestGetLabel(t *testing.T) {
+	c, _ := newCarrier(t)
+	ctx := context.Background()
+	tn := book(t, c)
+
+	tests := []struct {
+		format string
+		prefix string
+	}{
+		{carrier.LabelPDF, "%PDF-"},
+		{carrier.LabelZPL, "^XA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			label, err := c.GetLabel(ctx, tn, tt.format)
+			if err != nil {
+				t.Fatalf("GetLabel: %v", err)
+			}
+			if !bytes.HasPrefix(label, []byte(tt.prefix)) {
+				t.Errorf("label starts %q, want %q", label[:min(len(label), 8)], tt.prefix)
+			}
+		})
+	}
+
+	if _, err := c.GetLabel(ctx, tn, "png"); err == nil {
+		t.Error("GetLabel with an unsupported format succeeded")
+	}
+	if _, err := c.GetLabel(ctx, "MC9999999999", carrier.LabelPDF); !errors.Is(err, carrier.ErrShipmentNotFound) {
+		t.Errorf("GetLabel of unknown shipment = %v, want ErrShipmentNotFound", err)
+	}
+}
@@ -0,0 +1,103 @@
+package carrier
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// InHouse is the rule target for deliveries we make ourselves.
+	InHouse = "inhouse"
+	// Any matches every zone or service level in a rule.
+	Any = "*"
+)
+
+// Rule sends deliveries to a zone, by name, at a service level to a carrier,
+// or keeps them in-house.
+type Rule struct {
+	Zone         string
+	ServiceLevel string
+	Carrier      string // a carrier name, or InHouse
+}
+
+func (r Rule) matches(zone, serviceLevel string) bool {
+	return (r.Zone == Any || r.Zone == zone) && (r.ServiceLevel == Any || r.ServiceLevel == serviceLevel)
+}
+
+// Router decides who makes a delivery. The first matching rule wins;
+// deliveries no rule matches stay in-house. Addresses outside all zones go to
+// the Uncovered carrier, if there is one.
+type Router struct {
+	Rules     []Rule
+	Uncovered string
+}
+
+// ParseRules reads rules such as "Islands:*=acme,*:SAME_DAY=inhouse", in
+// order of precedence.
+func ParseRules(s string) ([]Rule, error) {
+	var rules []Rule
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		match, target, ok := strings.Cut(part, "=")
+		zone, level, hasLevel := strings.Cut(match, ":")
+		if !ok || !hasLevel {
+			return nil, fmt.Errorf("invalid carrier rule %q, want zone:service_level=carrier", part)
+		}
+		rule := Rule{
+			Zone:         strings.TrimSpace(zone),
+			ServiceLevel: strings.ToUpper(strings.TrimSpace(level)),
+			Carrier:      strings.TrimSpace(target),
+		}
+		if rule.Zone == "" || rule.ServiceLevel == "" || rule.Carrier == "" {
+			return nil, fmt.Errorf("invalid carrier rule %q, want zone:service_level=carrier", part)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Covers reports whether addresses outside all zones can still be delivered.
+func (r Router) Covers() bool {
+	return r.Uncovered != ""
+}
+
+// Route returns the carrier for a delivery to a zone, by name, at a service
+// level, or "" to deliver in-house. An empty zone is outside all zones.
+func (r Router) Route(zone, serviceLevel string) string {
+	if zone == "" {
+		return r.Uncovered
+	}
+	for _, rule := range r.Rules {
+		if rule.matches(zone, serviceLevel) {
+			if rule.Carrier == InHouse {
+				return ""
+			}
+			return rule.Carrier
+		}
+	}
+	return ""
+}
+
+// Carriers returns every carrier the router can send deliveries to.
+func (r Router) Carriers() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range append([]string{r.Uncovered}, ruleCarriers(r.Rules)...) {
+		if name != "" && name != InHouse && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func ruleCarriers(rules []Rule) []string {
+	names := make([]string, len(rules))
+	for i, rule := range rules {
+		names[i] = rule.Carrier
+	}
+	return names
+}
@@ -0,0 +1,88 @@
+package carrier
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []Rule
+		wantErr bool
+	}{
+		{in: "", want: nil},
+		{
+			in: "Islands:*=acme, *:same_day=inhouse",
+			want: []Rule{
+				{Zone: "Islands", ServiceLevel: "*", Carrier: "acme"},
+				{Zone: "*", ServiceLevel: "SAME_DAY", Carrier: InHouse},
+			},
+		},
+		{in: "Islands:*=acme,,", want: []Rule{{Zone: "Islands", ServiceLevel: "*", Carrier: "acme"}}},
+		{in: "Islands=acme", wantErr: true},
+		{in: "Islands:*", wantErr: true},
+		{in: ":*=acme", wantErr: true},
+		{in: "Islands:=acme", wantErr: true},
+		{in: "Islands:*=", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseRules(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRules(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRules(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouterRoute(t *testing.T) {
+	rules, err := ParseRules("Islands:*=acme,*:SAME_DAY=inhouse,*:EXPRESS=swift,North:STANDARD=acme")
+	if err != nil {
+		t.Fatalf("ParseRules: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		router       Router
+		zone         string
+		serviceLevel string
+		want         string
+	}{
+		{"zone rule", Router{Rules: rules}, "Islands", "STANDARD", "acme"},
+		{"first match wins", Router{Rules: rules}, "Islands", "SAME_DAY", "acme"},
+		{"in-house rule", Router{Rules: rules}, "North", "SAME_DAY", ""},
+		{"service level rule", Router{Rules: rules}, "Downtown", "EXPRESS", "swift"},
+		{"zone and service level", Router{Rules: rules}, "North", "STANDARD", "acme"},
+		{"no match", Router{Rules: rules}, "Downtown", "STANDARD", ""},
+		{"uncovered address", Router{Rules: rules, Uncovered: "acme"}, "", "STANDARD", "acme"},
+		{"uncovered without carrier", Router{Rules: rules}, "", "STANDARD", ""},
+		{"no rules", Router{}, "Downtown", "EXPRESS", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.router.Route(tt.zone, tt.serviceLevel); got != tt.want {
+				t.Errorf("Route(%q, %q) = %q, want %q", tt.zone, tt.serviceLevel, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouterCarriers(t *testing.T) {
+	rules, err := ParseRules("Islands:*=acme,*:SAME_DAY=inhouse,*:EXPRESS=swift,North:*=acme")
+	if err != nil {
+		t.Fatalf("ParseRules: %v", err)
+	}
+	r := Router{Rules: rules, Uncovered: "remote"}
+
+	want := []string{"remote", "acme", "swift"}
+	if got := r.Carriers(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Carriers() = %v, want %v", got, want)
+	}
+	if !r.Covers() || (Router{Rules: rules}).Covers() {
+		t.Error("Covers() should follow Uncovered")
+	}
+}
@@ -0,0 +1,82 @@
+package carrier
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// StatusMap maps carrier status codes to our delivery statuses. Codes are
+// matched case-insensitively.
+type StatusMap map[string]string
+
+// DefaultStatuses covers the codes most carriers use. Carriers with their own
+// codes extend it with ParseStatusMap.
+var DefaultStatuses = StatusMap{
+	"LABEL_CREATED":    model.EventCarrierUpdate,
+	"INFO_RECEIVED":    model.EventCarrierUpdate,
+	"PICKED_UP":        model.StatusInTransit,
+	"PU":               model.StatusInTransit,
+	"IN_TRANSIT":       model.StatusInTransit,
+	"IT":               model.StatusInTransit,
+	"ARRIVED":          model.StatusInTransit,
+	"DEPARTED":         model.StatusInTransit,
+	"OUT_FOR_DELIVERY": model.StatusOutForDelivery,
+	"OFD":              model.StatusOutForDelivery,
+	"DELIVERED":        model.StatusDelivered,
+	"DL":               model.StatusDelivered,
+	"DELIVERY_FAILED":  model.StatusFailedAttempt,
+	"EXCEPTION":        model.StatusFailedAttempt,
+	"EX":               model.StatusFailedAttempt,
+	"RETURNED":         model.StatusReturnToSender,
+	"RTS":              model.StatusReturnToSender,
+}
+
+// statusTargets are the statuses a carrier update may move a delivery to.
+// Cancellations only come from our side, through CancelDelivery.
+var statusTargets = []string{
+	model.EventCarrierUpdate, model.StatusInTransit, model.StatusOutForDelivery, model.StatusDelivered,
+	model.StatusFailedAttempt, model.StatusReturnToSender,
+}
+
+// ParseStatusMap reads overrides such as "SCAN=IN_TRANSIT,NH=FAILED_ATTEMPT"
+// on top of DefaultStatuses.
+func ParseStatusMap(s string) (StatusMap, error) {
+	m := make(StatusMap, len(DefaultStatuses))
+	for code, status := range DefaultStatuses {
+		m[code] = status
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		code, status, ok := strings.Cut(part, "=")
+		code, status = strings.ToUpper(strings.TrimSpace(code)), strings.ToUpper(strings.TrimSpace(status))
+		if !ok || code == "" || !isStatusTarget(status) {
+			return nil, fmt.Errorf("invalid carrier status mapping %q", part)
+		}
+		m[code] = status
+	}
+	return m, nil
+}
+
+// Normalize returns our status for a carrier code. Codes we do not know are
+// recorded as informational updates rather than guessed at.
+func (m StatusMap) Normalize(code string) string {
+	if status, ok := m[strings.ToUpper(code)]; ok {
+		return status
+	}
+	return model.EventCarrierUpdate
+}
+
+func isStatusTarget(status string) bool {
+	for _, s := range statusTargets {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
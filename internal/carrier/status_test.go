@@ -0,0 +1,74 @@
+package carrier
+
+import (
+	"testing"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func TestParseStatusMap(t *testing.T) {
+	tests := []struct {
+		in      string
+		code    string
+		want    string
+		wantErr bool
+	}{
+		{in: "", code: "DELIVERED", want: model.StatusDelivered},
+		{in: "SCAN=IN_TRANSIT", code: "SCAN", want: model.StatusInTransit},
+		{in: " nh = failed_attempt ", code: "NH", want: model.StatusFailedAttempt},
+		{in: "DL=CARRIER_UPDATE", code: "DL", want: model.EventCarrierUpdate},
+		{in: "SCAN=IN_TRANSIT,,", code: "SCAN", want: model.StatusInTransit},
+		{in: "SCAN", wantErr: true},
+		{in: "=IN_TRANSIT", wantErr: true},
+		{in: "SCAN=BOGUS", wantErr: true},
+		{in: "VOID=CANCELLED", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			m, err := ParseStatusMap(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseStatusMap(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := m.Normalize(tt.code); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStatusMapKeepsDefaults(t *testing.T) {
+	if _, err := ParseStatusMap("DL=CARRIER_UPDATE"); err != nil {
+		t.Fatalf("ParseStatusMap: %v", err)
+	}
+	if got := DefaultStatuses.Normalize("DL"); got != model.StatusDelivered {
+		t.Errorf("overrides changed DefaultStatuses: DL = %q", got)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"LABEL_CREATED", model.EventCarrierUpdate},
+		{"PICKED_UP", model.StatusInTransit},
+		{"picked_up", model.StatusInTransit},
+		{"OFD", model.StatusOutForDelivery},
+		{"DELIVERED", model.StatusDelivered},
+		{"EXCEPTION", model.StatusFailedAttempt},
+		{"RTS", model.StatusReturnToSender},
+		{"CANCELLED", model.EventCarrierUpdate},
+		{"HELD_AT_CUSTOMS", model.EventCarrierUpdate},
+		{"", model.EventCarrierUpdate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := DefaultStatuses.Normalize(tt.code); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
@@ -11,6 +12,24 @@ type Config struct {
 	Database  DatabaseConfig
 	Redis     RedisConfig
 	Services  ServicesConfig
+	ETA           ETAConfig
+	ServiceLevels ServiceLevelsConfig
+	Slots         SlotsConfig
+	Geocoder      GeocoderConfig
+	Tracking      TrackingConfig
+	Routes        RoutesConfig
+	Blobs         BlobsConfig
+	OTP           OTPConfig
+	Attempts      AttemptsConfig
+	Returns       ReturnsConfig
+	Pickups       PickupsConfig
+	Hubs          HubsConfig
+	Scans         ScansConfig
+	Labels        LabelsConfig
+	Manifests     ManifestsConfig
+	TrackingNumbers TrackingNumbersConfig
+	Rates         RatesConfig
+	Carriers      CarriersConfig
 }
 
 type DatabaseConfig struct {
@@ -28,7 +47,8 @@ type RedisConfig struct {
 }
 
 type ServicesConfig struct {
-	OrderService ServiceConfig
+	OrderService        ServiceConfig
+	NotificationService ServiceConfig // notifications are only logged if Host is empty
 }
 
 type ServiceConfig struct {
@@ -36,10 +56,171 @@ type ServiceConfig struct {
 	Port int
 }
 
+type ETAConfig struct {
+	Timezone     string
+	Holidays     []string
+	MinSamples   int
+	LookbackDays int
+	TransitDays  int
+	Cutoff       string
+	DeliverBy    string
+}
+
+type ServiceLevelsConfig struct {
+	Express         ServiceLevelConfig
+	SameDay         ServiceLevelConfig
+	Scheduled       ServiceLevelConfig
+	MaxScheduleDays int
+}
+
+type TrackingConfig struct {
+	MinIntervalSeconds  int
+	MinDistanceMeters   int
+	LastKnownTTLMinutes int
+	NearbyRadiusMeters  int
+	GeofenceExitFactor  float64
+	MaxPings            int
+}
+
+type RoutesConfig struct {
+	SpeedKmh           float64
+	Detour             float64
+	ServiceTimeMinutes int
+	Capacity           int
+}
+
+type OTPConfig struct {
+	Length         int
+	TTLHours       int
+	MaxAttempts    int
+	LockoutMinutes int
+	Secret         string
+}
+
+type AttemptsConfig struct {
+	MaxAttempts  int
+	ReturnOn     []string // reason codes that return to sender immediately
+	ReattemptGap int      // business days
+}
+
+type ReturnsConfig struct {
+	WindowDays int
+	Warehouse  string
+}
+
+type HubsConfig struct {
+	OriginCode string
+	SortCode   string
+}
+
+type LabelsConfig struct {
+	SenderName string
+}
+
+type ManifestsConfig struct {
+	Secret string
+}
+
+type TrackingNumbersConfig struct {
+	Prefix string
+	Length int // random symbols, before the check symbol
+}
+
+type ScansConfig struct {
+	MaxItems               int
+	BatchSize              int
+	DuplicateWindowSeconds int
+}
+
+type PickupsConfig struct {
+	HoldDays           int
+	ExpiryCheckMinutes int
+}
+
+type BlobsConfig struct {
+	Dir string
+}
+
+// RatesConfig points at a rate card file or a directory of them; quotes are
+// disabled when it is empty.
+type RatesConfig struct {
+	CardsPath string
+}
+
+// CarriersConfig lists the third-party carriers and the rules routing
+// deliveries to them.
+type CarriersConfig struct {
+	Carriers    []CarrierConfig
+	Routes      string
+	Uncovered   string
+	SyncMinutes int
+}
+
+type CarrierConfig struct {
+	Name     string
+	URL      string
+	APIKey   string
+	Statuses string
+}
+
+type GeocoderConfig struct {
+	CentroidsPath string
+}
+
+type SlotsConfig struct {
+	Windows     string
+	Capacity    int
+	HoldMinutes int
+	HorizonDays int
+}
+
+type ServiceLevelConfig struct {
+	TransitDays int
+	Cutoff      string
+	DeliverBy   string
+	Zones       []string // zone names the level is offered in
+}
+
 func Load() (*Config, error) {
 	dbPort, _ := strconv.Atoi(getEnv("DB_PORT", "5432"))
 	redisPort, _ := strconv.Atoi(getEnv("REDIS_PORT", "6379"))
 	orderPort, _ := strconv.Atoi(getEnv("ORDER_SERVICE_PORT", "50051"))
+	notificationPort, _ := strconv.Atoi(getEnv("NOTIFICATION_SERVICE_PORT", "8085"))
+	etaMinSamples, _ := strconv.Atoi(getEnv("ETA_MIN_SAMPLES", "20"))
+	etaLookbackDays, _ := strconv.Atoi(getEnv("ETA_LOOKBACK_DAYS", "90"))
+	etaTransitDays, _ := strconv.Atoi(getEnv("ETA_TRANSIT_DAYS", "3"))
+	maxScheduleDays, _ := strconv.Atoi(getEnv("SCHEDULED_MAX_DAYS", "14"))
+	slotCapacity, _ := strconv.Atoi(getEnv("SLOT_CAPACITY", "20"))
+	slotHoldMinutes, _ := strconv.Atoi(getEnv("SLOT_HOLD_MINUTES", "15"))
+	slotHorizonDays, _ := strconv.Atoi(getEnv("SLOT_HORIZON_DAYS", "7"))
+	locationInterval, _ := strconv.Atoi(getEnv("LOCATION_MIN_INTERVAL_SECONDS", "30"))
+	locationDistance, _ := strconv.Atoi(getEnv("LOCATION_MIN_DISTANCE_METERS", "50"))
+	locationTTL, _ := strconv.Atoi(getEnv("LOCATION_TTL_MINUTES", "60"))
+	locationMaxPings, _ := strconv.Atoi(getEnv("LOCATION_MAX_PINGS", "1000"))
+	nearbyRadius, _ := strconv.Atoi(getEnv("GEOFENCE_NEARBY_RADIUS_METERS", "300"))
+	exitFactor, _ := strconv.ParseFloat(getEnv("GEOFENCE_EXIT_FACTOR", "1.5"), 64)
+	routeSpeed, _ := strconv.ParseFloat(getEnv("ROUTE_SPEED_KMH", "30"), 64)
+	routeDetour, _ := strconv.ParseFloat(getEnv("ROUTE_DETOUR", "1.3"), 64)
+	routeServiceTime, _ := strconv.Atoi(getEnv("ROUTE_SERVICE_MINUTES", "5"))
+	routeCapacity, _ := strconv.Atoi(getEnv("ROUTE_CAPACITY", "60"))
+	otpLength, _ := strconv.Atoi(getEnv("OTP_LENGTH", "6"))
+	otpTTL, _ := strconv.Atoi(getEnv("OTP_TTL_HOURS", "24"))
+	otpMaxAttempts, _ := strconv.Atoi(getEnv("OTP_MAX_ATTEMPTS", "5"))
+	otpLockout, _ := strconv.Atoi(getEnv("OTP_LOCKOUT_MINUTES", "15"))
+	maxAttempts, _ := strconv.Atoi(getEnv("ATTEMPT_MAX", "3"))
+	reattemptGap, _ := strconv.Atoi(getEnv("ATTEMPT_GAP_DAYS", "1"))
+	returnWindow, _ := strconv.Atoi(getEnv("RETURN_WINDOW_DAYS", "30"))
+	pickupHoldDays, _ := strconv.Atoi(getEnv("PICKUP_HOLD_DAYS", "7"))
+	pickupExpiryCheck, _ := strconv.Atoi(getEnv("PICKUP_EXPIRY_CHECK_MINUTES", "15"))
+	scanMaxItems, _ := strconv.Atoi(getEnv("SCAN_MAX_ITEMS", "1000"))
+	scanBatchSize, _ := strconv.Atoi(getEnv("SCAN_BATCH_SIZE", "100"))
+	scanDuplicateWindow, _ := strconv.Atoi(getEnv("SCAN_DUPLICATE_WINDOW_SECONDS", "300"))
+	trackingNumberLength, _ := strconv.Atoi(getEnv("TRACKING_NUMBER_LENGTH", "10"))
+	carrierSyncMinutes, _ := strconv.Atoi(getEnv("CARRIER_SYNC_MINUTES", "15"))
+	returnOn := getEnvList("ATTEMPT_RETURN_ON")
+	if _, ok := os.LookupEnv("ATTEMPT_RETURN_ON"); !ok {
+		returnOn = []string{"REFUSED"}
+	}
 
 	return &Config{
 		HTTPAddr: getEnv("HTTP_ADDR", ":8081"),
@@ -61,13 +242,145 @@ func Load() (*Config, error) {
 				Host: getEnv("ORDER_SERVICE_HOST", "localhost"),
 				Port: orderPort,
 			},
+			NotificationService: ServiceConfig{
+				Host: getEnv("NOTIFICATION_SERVICE_HOST", ""),
+				Port: notificationPort,
+			},
+		},
+		ETA: ETAConfig{
+			Timezone:     getEnv("ETA_TIMEZONE", "UTC"),
+			Holidays:     getEnvList("ETA_HOLIDAYS"),
+			MinSamples:   etaMinSamples,
+			LookbackDays: etaLookbackDays,
+			TransitDays:  etaTransitDays,
+			Cutoff:       getEnv("ETA_CUTOFF", "15:00"),
+			DeliverBy:    getEnv("ETA_DELIVER_BY", "20:00"),
+		},
+		ServiceLevels: ServiceLevelsConfig{
+			Express:         loadServiceLevel("EXPRESS", "1", "15:00", "20:00"),
+			SameDay:         loadServiceLevel("SAME_DAY", "0", "11:00", "21:00"),
+			Scheduled:       loadServiceLevel("SCHEDULED", "3", "15:00", "20:00"),
+			MaxScheduleDays: maxScheduleDays,
+		},
+		Slots: SlotsConfig{
+			Windows:     getEnv("SLOT_WINDOWS", "09:00-12:00,12:00-15:00,15:00-18:00,18:00-21:00"),
+			Capacity:    slotCapacity,
+			HoldMinutes: slotHoldMinutes,
+			HorizonDays: slotHorizonDays,
+		},
+		Geocoder: GeocoderConfig{
+			CentroidsPath: getEnv("GEOCODER_CENTROIDS_PATH", ""),
+		},
+		Tracking: TrackingConfig{
+			MinIntervalSeconds:  locationInterval,
+			MinDistanceMeters:   locationDistance,
+			LastKnownTTLMinutes: locationTTL,
+			NearbyRadiusMeters:  nearbyRadius,
+			GeofenceExitFactor:  exitFactor,
+			MaxPings:            locationMaxPings,
+		},
+		Routes: RoutesConfig{
+			SpeedKmh:           routeSpeed,
+			Detour:             routeDetour,
+			ServiceTimeMinutes: routeServiceTime,
+			Capacity:           routeCapacity,
+		},
+		Blobs: BlobsConfig{
+			Dir: getEnv("BLOB_DIR", "data/blobs"),
+		},
+		OTP: OTPConfig{
+			Length:         otpLength,
+			TTLHours:       otpTTL,
+			MaxAttempts:    otpMaxAttempts,
+			LockoutMinutes: otpLockout,
+			Secret:         getEnv("OTP_SECRET", ""),
+		},
+		Attempts: AttemptsConfig{
+			MaxAttempts:  maxAttempts,
+			ReturnOn:     returnOn,
+			ReattemptGap: reattemptGap,
+		},
+		Returns: ReturnsConfig{
+			WindowDays: returnWindow,
+			Warehouse:  getEnv("RETURN_WAREHOUSE", "WAREHOUSE"),
+		},
+		Hubs: HubsConfig{
+			OriginCode: getEnv("HUB_ORIGIN", ""),
+			SortCode:   getEnv("HUB_SORT", ""),
+		},
+		Labels: LabelsConfig{
+			SenderName: getEnv("LABEL_SENDER_NAME", "Delivery Service"),
+		},
+		Manifests: ManifestsConfig{
+			Secret: getEnv("MANIFEST_SECRET", ""),
+		},
+		TrackingNumbers: TrackingNumbersConfig{
+			Prefix: getEnv("TRACKING_NUMBER_PREFIX", "TRK-"),
+			Length: trackingNumberLength,
+		},
+		Rates: RatesConfig{
+			CardsPath: getEnv("RATE_CARDS_PATH", ""),
+		},
+		Carriers: CarriersConfig{
+			Carriers:    loadCarriers(getEnvList("CARRIERS")),
+			Routes:      getEnv("CARRIER_ROUTES", ""),
+			Uncovered:   getEnv("CARRIER_UNCOVERED", ""),
+			SyncMinutes: carrierSyncMinutes,
+		},
+		Scans: ScansConfig{
+			MaxItems:               scanMaxItems,
+			BatchSize:              scanBatchSize,
+			DuplicateWindowSeconds: scanDuplicateWindow,
+		},
+		Pickups: PickupsConfig{
+			HoldDays:           pickupHoldDays,
+			ExpiryCheckMinutes: pickupExpiryCheck,
 		},
 	}, nil
 }
 
+// loadServiceLevel reads <PREFIX>_TRANSIT_DAYS, _CUTOFF, _DELIVER_BY and _ZONES,
+// a comma-separated list of zone names.
+func loadServiceLevel(prefix, transitDays, cutoff, deliverBy string) ServiceLevelConfig {
+	days, _ := strconv.Atoi(getEnv(prefix+"_TRANSIT_DAYS", transitDays))
+	return ServiceLevelConfig{
+		TransitDays: days,
+		Cutoff:      getEnv(prefix+"_CUTOFF", cutoff),
+		DeliverBy:   getEnv(prefix+"_DELIVER_BY", deliverBy),
+		Zones:       getEnvList(prefix + "_ZONES"),
+	}
+}
+
+// loadCarriers reads CARRIER_<NAME>_URL, _API_KEY and _STATUSES for each
+// carrier name.
+func loadCarriers(names []string) []CarrierConfig {
+	carriers := make([]CarrierConfig, 0, len(names))
+	for _, name := range names {
+		prefix := "CARRIER_" + strings.ToUpper(name)
+		carriers = append(carriers, CarrierConfig{
+			Name:     name,
+			URL:      getEnv(prefix+"_URL", ""),
+			APIKey:   getEnv(prefix+"_API_KEY", ""),
+			Statuses: getEnv(prefix+"_STATUSES", ""),
+		})
+	}
+	return carriers
+}
+
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
 	}
 	return defaultValue
+}
+
+// getEnvList reads a comma-separated list, skipping empty entries.
+func getEnvList(key string) []string {
+	var values []string
+	for _, v := range strings.Split(getEnv(key, ""), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
 }
\ No newline at end of file
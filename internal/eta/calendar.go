@@ -0,0 +1,79 @@
+package eta
+
+import (
+	"fmt"
+	"time"
+)
+
+// Calendar answers business-day questions in a single time zone.
+type Calendar struct {
+	loc      *time.Location
+	weekend  map[time.Weekday]bool
+	holidays map[string]bool
+}
+
+func NewCalendar(timezone string, holidays []string) (*Calendar, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar timezone %q: %w", timezone, err)
+	}
+
+	cal := &Calendar{
+		loc:      loc,
+		weekend:  map[time.Weekday]bool{time.Saturday: true, time.Sunday: true},
+		holidays: make(map[string]bool),
+	}
+
+	for _, h := range holidays {
+		day, err := time.ParseInLocation("2006-01-02", h, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday %q: %w", h, err)
+		}
+		cal.holidays[day.Format("2006-01-02")] = true
+	}
+
+	return cal, nil
+}
+
+func (c *Calendar) Location() *time.Location {
+	return c.loc
+}
+
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	t = t.In(c.loc)
+	if c.weekend[t.Weekday()] {
+		return false
+	}
+	return !c.holidays[t.Format("2006-01-02")]
+}
+
+// NextBusinessDay returns the start of the first business day strictly after t.
+func (c *Calendar) NextBusinessDay(t time.Time) time.Time {
+	day := startOfDay(t.In(c.loc)).AddDate(0, 0, 1)
+	for !c.IsBusinessDay(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// AddBusinessDays moves t forward by n business days, keeping the time of day.
+func (c *Calendar) AddBusinessDays(t time.Time, n int) time.Time {
+	t = t.In(c.loc)
+	for n > 0 {
+		t = t.AddDate(0, 0, 1)
+		if c.IsBusinessDay(t) {
+			n--
+		}
+	}
+	return t
+}
+
+// At returns t's calendar day at the given clock time.
+func (c *Calendar) At(t time.Time, clock Clock) time.Time {
+	t = t.In(c.loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), clock.Hour, clock.Minute, 0, 0, c.loc)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,128 @@
+package eta
+
+import (
+	"testing"
+	"time"
+)
+
+// Friday 16 October 2026; Monday 19 October is a holiday.
+func testCalendar(t *testing.T) *Calendar {
+	t.Helper()
+	cal, err := NewCalendar("UTC", []string{"2026-10-19"})
+	if err != nil {
+		t.Fatalf("NewCalendar: %v", err)
+	}
+	return cal
+}
+
+func day(d, hour, minute int) time.Time {
+	return time.Date(2026, time.October, d, hour, minute, 0, 0, time.UTC)
+}
+
+func TestNewCalendarRejectsBadInput(t *testing.T) {
+	if _, err := NewCalendar("Mars/Olympus", nil); err == nil {
+		t.Error("unknown timezone accepted")
+	}
+	if _, err := NewCalendar("UTC", []string{"19/10/2026"}); err == nil {
+		t.Error("malformed holiday accepted")
+	}
+}
+
+func TestIsBusinessDay(t *testing.T) {
+	cal := testCalendar(t)
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"friday", day(16, 12, 0), true},
+		{"saturday", day(17, 12, 0), false},
+		{"sunday", day(18, 12, 0), false},
+		{"holiday", day(19, 12, 0), false},
+		{"tuesday", day(20, 12, 0), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cal.IsBusinessDay(tt.at); got != tt.want {
+				t.Errorf("IsBusinessDay(%v) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsBusinessDayUsesCalendarZone(t *testing.T) {
+	cal, err := NewCalendar("America/New_York", nil)
+	if err != nil {
+		t.Fatalf("NewCalendar: %v", err)
+	}
+	// Saturday 02:00 UTC is still Friday evening in New York
+	if !cal.IsBusinessDay(day(17, 2, 0)) {
+		t.Error("Friday evening in New York should be a business day")
+	}
+}
+
+func TestNextBusinessDay(t *testing.T) {
+	cal := testCalendar(t)
+	tests := []struct {
+		name string
+		at   time.Time
+		want time.Time
+	}{
+		{"thursday", day(15, 18, 30), day(16, 0, 0)},
+		{"friday skips weekend and holiday", day(16, 9, 0), day(20, 0, 0)},
+		{"saturday", day(17, 9, 0), day(20, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cal.NextBusinessDay(tt.at); !got.Equal(tt.want) {
+				t.Errorf("NextBusinessDay(%v) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	cal := testCalendar(t)
+	tests := []struct {
+		name string
+		at   time.Time
+		n    int
+		want time.Time
+	}{
+		{"zero", day(17, 10, 0), 0, day(17, 10, 0)},
+		{"within week", day(14, 10, 0), 2, day(16, 10, 0)},
+		{"across weekend and holiday", day(16, 10, 0), 1, day(20, 10, 0)},
+		{"from weekend", day(17, 10, 0), 2, day(21, 10, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cal.AddBusinessDays(tt.at, tt.n); !got.Equal(tt.want) {
+				t.Errorf("AddBusinessDays(%v, %d) = %v, want %v", tt.at, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Clock
+		wantErr bool
+	}{
+		{in: "15:00", want: Clock{Hour: 15}},
+		{in: "09:30", want: Clock{Hour: 9, Minute: 30}},
+		{in: "24:00", wantErr: true},
+		{in: "3pm", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseClock(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseClock(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseClock(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
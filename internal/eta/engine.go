@@ -0,0 +1,165 @@
+package eta
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// Clock is a time of day in the calendar's time zone.
+type Clock struct {
+	Hour   int
+	Minute int
+}
+
+func ParseClock(s string) (Clock, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return Clock{}, fmt.Errorf("invalid clock %q: %w", s, err)
+	}
+	return Clock{Hour: t.Hour(), Minute: t.Minute()}, nil
+}
+
+// Rule is the fallback transit rule for a service level, used when there is
+// not enough history for a lane.
+type Rule struct {
+	TransitDays int   // business days after the processing day
+	Cutoff      Clock // requests after the cutoff are processed the next business day
+	DeliverBy   Clock // end of the delivery window on the delivery day
+}
+
+// Lane identifies an origin hub/destination zone pair. Deliveries that do
+// not travel through the hub network have no origin.
+type Lane struct {
+	Origin      string
+	Destination string
+}
+
+// TransitHistory reports the median observed time from an event with the given
+// status to delivery, for deliveries of the service level completed on the lane
+// since the given time. Each delivery counts once.
+type TransitHistory interface {
+	TransitTime(ctx context.Context, lane Lane, serviceLevel, fromStatus string, since time.Time) (time.Duration, int, error)
+}
+
+type Request struct {
+	Lane         Lane
+	ServiceLevel string
+	Status       string
+	At           time.Time
+	ScheduledFor time.Time // set for scheduled deliveries; the ETA is pinned to it
+}
+
+type Options struct {
+	DefaultRule Rule
+	MinSamples  int           // lane history needed before it overrides the rules
+	Lookback    time.Duration // how far back to mine completed deliveries
+}
+
+type Engine struct {
+	calendar *Calendar
+	history  TransitHistory
+	rules    map[string]Rule
+	opts     Options
+}
+
+func NewEngine(calendar *Calendar, history TransitHistory, opts Options) *Engine {
+	return &Engine{
+		calendar: calendar,
+		history:  history,
+		rules:    make(map[string]Rule),
+		opts:     opts,
+	}
+}
+
+// SetRule registers the fallback rule for a service level.
+func (e *Engine) SetRule(serviceLevel string, rule Rule) {
+	e.rules[serviceLevel] = rule
+}
+
+func (e *Engine) Calendar() *Calendar {
+	return e.calendar
+}
+
+// Rule returns the rule for a service level, or the default rule.
+func (e *Engine) Rule(serviceLevel string) Rule {
+	if rule, ok := e.rules[serviceLevel]; ok {
+		return rule
+	}
+	return e.opts.DefaultRule
+}
+
+// Estimate computes the initial ETA for a new delivery.
+func (e *Engine) Estimate(ctx context.Context, req Request) (time.Time, error) {
+	if !req.ScheduledFor.IsZero() {
+		return req.ScheduledFor, nil
+	}
+
+	eta, ok, err := e.fromHistory(ctx, req)
+	if err != nil || ok {
+		return eta, err
+	}
+
+	rule := e.Rule(req.ServiceLevel)
+	return e.fromRule(rule, req.At, rule.TransitDays, rule.Cutoff), nil
+}
+
+// Revise recomputes the ETA after a status event. When neither history nor the
+// status says anything new, the previous estimate is kept as long as it is
+// still in the future.
+func (e *Engine) Revise(ctx context.Context, req Request, previous time.Time) (time.Time, error) {
+	rule := e.Rule(req.ServiceLevel)
+
+	// Scheduled deliveries keep their appointment until it has been missed
+	if !req.ScheduledFor.IsZero() && req.ScheduledFor.After(req.At) {
+		return req.ScheduledFor, nil
+	}
+
+	// Out for delivery means today, or the next business day if the window has passed
+	if req.Status == model.StatusOutForDelivery {
+		return e.fromRule(rule, req.At, 0, rule.DeliverBy), nil
+	}
+
+	eta, ok, err := e.fromHistory(ctx, req)
+	if err != nil || ok {
+		return eta, err
+	}
+
+	if previous.After(req.At) {
+		return previous, nil
+	}
+
+	// Estimate already missed: push to the next delivery window
+	return e.fromRule(rule, req.At, 0, rule.DeliverBy), nil
+}
+
+func (e *Engine) fromHistory(ctx context.Context, req Request) (time.Time, bool, error) {
+	if e.history == nil {
+		return time.Time{}, false, nil
+	}
+
+	transit, samples, err := e.history.TransitTime(ctx, req.Lane, req.ServiceLevel, req.Status, req.At.Add(-e.opts.Lookback))
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("error loading transit history: %w", err)
+	}
+	if samples < e.opts.MinSamples {
+		return time.Time{}, false, nil
+	}
+
+	eta := req.At.Add(transit).In(e.calendar.Location())
+	if !e.calendar.IsBusinessDay(eta) {
+		eta = e.calendar.At(e.calendar.NextBusinessDay(eta), Clock{Hour: eta.Hour(), Minute: eta.Minute()})
+	}
+	return eta, true, nil
+}
+
+func (e *Engine) fromRule(rule Rule, at time.Time, days int, cutoff Clock) time.Time {
+	start := at.In(e.calendar.Location())
+	if !e.calendar.IsBusinessDay(start) || !start.Before(e.calendar.At(start, cutoff)) {
+		start = e.calendar.NextBusinessDay(start)
+	}
+	day := e.calendar.AddBusinessDays(start, days)
+	return e.calendar.At(day, rule.DeliverBy)
+}
@@ -0,0 +1,161 @@
+package eta
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+type fixedHistory struct {
+	transit time.Duration
+	samples int
+}
+
+func (h fixedHistory) TransitTime(ctx context.Context, lane Lane, serviceLevel, fromStatus string, since time.Time) (time.Duration, int, error) {
+	return h.transit, h.samples, nil
+}
+
+// laneHistory only has history for the lanes it lists.
+type laneHistory map[Lane]fixedHistory
+
+func (h laneHistory) TransitTime(ctx context.Context, lane Lane, serviceLevel, fromStatus string, since time.Time) (time.Duration, int, error) {
+	return h[lane].TransitTime(ctx, lane, serviceLevel, fromStatus, since)
+}
+
+func testEngine(t *testing.T, history TransitHistory) *Engine {
+	t.Helper()
+	return NewEngine(testCalendar(t), history, Options{
+		DefaultRule: Rule{TransitDays: 2, Cutoff: Clock{Hour: 15}, DeliverBy: Clock{Hour: 20}},
+		MinSamples:  10,
+		Lookback:    30 * 24 * time.Hour,
+	})
+}
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		name    string
+		history TransitHistory
+		req     Request
+		want    time.Time
+	}{
+		{
+			name: "before cutoff",
+			req:  Request{At: day(14, 10, 0)},
+			want: day(16, 20, 0),
+		},
+		{
+			name: "after cutoff",
+			req:  Request{At: day(14, 16, 0)},
+			want: day(20, 20, 0),
+		},
+		{
+			name: "at cutoff",
+			req:  Request{At: day(14, 15, 0)},
+			want: day(20, 20, 0),
+		},
+		{
+			name: "weekend",
+			req:  Request{At: day(17, 10, 0)},
+			want: day(22, 20, 0),
+		},
+		{
+			name: "scheduled",
+			req:  Request{At: day(14, 10, 0), ScheduledFor: day(23, 12, 0)},
+			want: day(23, 12, 0),
+		},
+		{
+			name:    "history",
+			history: fixedHistory{transit: 26 * time.Hour, samples: 10},
+			req:     Request{At: day(14, 10, 0)},
+			want:    day(15, 12, 0),
+		},
+		{
+			name:    "history landing on a weekend",
+			history: fixedHistory{transit: 26 * time.Hour, samples: 10},
+			req:     Request{At: day(16, 10, 0)},
+			want:    day(20, 12, 0),
+		},
+		{
+			name:    "too little history",
+			history: fixedHistory{transit: time.Hour, samples: 9},
+			req:     Request{At: day(14, 10, 0)},
+			want:    day(16, 20, 0),
+		},
+		{
+			name:    "history of the lane",
+			history: laneHistory{{Origin: "hub-a", Destination: "z1"}: {transit: 26 * time.Hour, samples: 10}},
+			req:     Request{Lane: Lane{Origin: "hub-a", Destination: "z1"}, At: day(14, 10, 0)},
+			want:    day(15, 12, 0),
+		},
+		{
+			name:    "history from another origin",
+			history: laneHistory{{Origin: "hub-a", Destination: "z1"}: {transit: 26 * time.Hour, samples: 10}},
+			req:     Request{Lane: Lane{Origin: "hub-b", Destination: "z1"}, At: day(14, 10, 0)},
+			want:    day(16, 20, 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := testEngine(t, tt.history).Estimate(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("Estimate: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Estimate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevise(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      Request
+		previous time.Time
+		want     time.Time
+	}{
+		{
+			name:     "keeps a future estimate",
+			req:      Request{Status: model.StatusInTransit, At: day(14, 10, 0)},
+			previous: day(16, 20, 0),
+			want:     day(16, 20, 0),
+		},
+		{
+			name:     "missed estimate moves to the next window",
+			req:      Request{Status: model.StatusInTransit, At: day(16, 21, 0)},
+			previous: day(16, 20, 0),
+			want:     day(20, 20, 0),
+		},
+		{
+			name:     "out for delivery",
+			req:      Request{Status: model.StatusOutForDelivery, At: day(15, 9, 0)},
+			previous: day(16, 20, 0),
+			want:     day(15, 20, 0),
+		},
+		{
+			name:     "out for delivery after the window",
+			req:      Request{Status: model.StatusOutForDelivery, At: day(15, 21, 0)},
+			previous: day(16, 20, 0),
+			want:     day(16, 20, 0),
+		},
+		{
+			name:     "scheduled appointment",
+			req:      Request{Status: model.StatusOutForDelivery, At: day(15, 9, 0), ScheduledFor: day(21, 12, 0)},
+			previous: day(21, 12, 0),
+			want:     day(21, 12, 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := testEngine(t, nil).Revise(context.Background(), tt.req, tt.previous)
+			if err != nil {
+				t.Fatalf("Revise: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Revise = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package geo
+
+import (
+	"math"
+	"time"
+)
+
+const earthRadiusMeters = 6371008.8
+
+// Point is a WGS84 coordinate.
+type Point struct {
+	Lat float64 `json:"latitude"`
+	Lng float64 `json:"longitude"`
+}
+
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180
+}
+
+// Distance returns the great-circle distance between a and b in meters.
+func Distance(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat := lat2 - lat1
+	dLng := radians(b.Lng - a.Lng)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// TravelTime estimates how long it takes to cover the straight-line distance
+// between a and b at the given average speed, with a detour factor for roads.
+func TravelTime(a, b Point, speedKmh, detour float64) time.Duration {
+	if speedKmh <= 0 {
+		return 0
+	}
+	seconds := Distance(a, b) * detour / (speedKmh * 1000 / 3600)
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// Nearest returns the index of the point in candidates closest to p, or -1 if
+// there are none.
+func Nearest(p Point, candidates []Point) int {
+	best, bestDist := -1, math.MaxFloat64
+	for i, c := range candidates {
+		if d := Distance(p, c); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
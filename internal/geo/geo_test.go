@@ -0,0 +1,95 @@
+package geo
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Point
+		want float64 // meters
+		tol  float64
+	}{
+		{"same point", Point{51.5, -0.12}, Point{51.5, -0.12}, 0, 1e-6},
+		{"one degree of latitude", Point{0, 0}, Point{1, 0}, 111195, 1},
+		{"one degree of longitude at 60N", Point{60, 0}, Point{60, 1}, 55597, 5},
+		{"across the antimeridian", Point{0, 179.5}, Point{0, -179.5}, 111195, 1},
+		{"antipodes", Point{0, 0}, Point{0, 180}, math.Pi * earthRadiusMeters, 1},
+		{"london to new york", Point{51.5007, -0.1246}, Point{40.6892, -74.0445}, 5574800, 5000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Distance(tt.a, tt.b)
+			if math.Abs(got-tt.want) > tt.tol {
+				t.Errorf("Distance = %.1f, want %.1f ± %.1f", got, tt.want, tt.tol)
+			}
+			if back := Distance(tt.b, tt.a); math.Abs(back-got) > 1e-6 {
+				t.Errorf("Distance is not symmetric: %.3f and %.3f", got, back)
+			}
+		})
+	}
+}
+
+func TestTravelTime(t *testing.T) {
+	a, b := Point{0, 0}, Point{1, 0} // about 111.2 km
+	tests := []struct {
+		name     string
+		speedKmh float64
+		detour   float64
+		want     time.Duration
+	}{
+		{"straight line", 111.195, 1, time.Hour},
+		{"with detour", 111.195, 1.5, 90 * time.Minute},
+		{"no speed", 0, 1.3, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TravelTime(a, b, tt.speedKmh, tt.detour)
+			if diff := got - tt.want; diff < -time.Second || diff > time.Second {
+				t.Errorf("TravelTime = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNearest(t *testing.T) {
+	candidates := []Point{{10, 10}, {0.5, 0.5}, {-1, -1}}
+	tests := []struct {
+		name       string
+		p          Point
+		candidates []Point
+		want       int
+	}{
+		{"closest", Point{0, 0}, candidates, 1},
+		{"exact match", Point{-1, -1}, candidates, 2},
+		{"no candidates", Point{0, 0}, nil, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Nearest(tt.p, tt.candidates); got != tt.want {
+				t.Errorf("Nearest = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPointValid(t *testing.T) {
+	tests := []struct {
+		p    Point
+		want bool
+	}{
+		{Point{0, 0}, true},
+		{Point{90, 180}, true},
+		{Point{-90, -180}, true},
+		{Point{90.1, 0}, false},
+		{Point{0, -180.1}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.p.Valid(); got != tt.want {
+			t.Errorf("%+v.Valid() = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
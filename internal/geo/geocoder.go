@@ -0,0 +1,110 @@
+package geo
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// Geocoder resolves an address to a coordinate. It returns nil without an
+// error when the address cannot be located.
+type Geocoder interface {
+	Geocode(ctx context.Context, addr model.Address) (*Point, error)
+}
+
+// CentroidGeocoder is an offline geocoder that places an address at the
+// centroid of its postal code.
+type CentroidGeocoder struct {
+	centroids map[string]Point // keyed by country + "|" + compact postal code
+}
+
+func NewCentroidGeocoder() *CentroidGeocoder {
+	return &CentroidGeocoder{centroids: make(map[string]Point)}
+}
+
+// LoadCentroidsFile loads a CSV dataset with the columns
+// country,postal_code,latitude,longitude. An optional header row is skipped.
+func LoadCentroidsFile(path string) (*CentroidGeocoder, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	g := NewCentroidGeocoder()
+	if err := g.Load(f); err != nil {
+		return nil, fmt.Errorf("error loading %s: %w", path, err)
+	}
+	return g, nil
+}
+
+// Load adds the centroids read from r; see LoadCentroidsFile for the format.
+func (g *CentroidGeocoder) Load(r io.Reader) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		lat, latErr := strconv.ParseFloat(record[2], 64)
+		lng, lngErr := strconv.ParseFloat(record[3], 64)
+		if latErr != nil || lngErr != nil {
+			if line == 1 {
+				continue // header
+			}
+			return fmt.Errorf("line %d: invalid coordinates", line)
+		}
+
+		p := Point{Lat: lat, Lng: lng}
+		if !p.Valid() {
+			return fmt.Errorf("line %d: coordinates out of range", line)
+		}
+		g.centroids[centroidKey(record[0], record[1])] = p
+	}
+}
+
+func (g *CentroidGeocoder) Len() int {
+	return len(g.centroids)
+}
+
+// Geocode looks up the postal code, falling back to successively shorter
+// prefixes (ZIP+4 to ZIP, full UK postcode to outward code) down to three
+// characters.
+func (g *CentroidGeocoder) Geocode(ctx context.Context, addr model.Address) (*Point, error) {
+	key := centroidKey(addr.Country, addr.ZipCode)
+	country := key[:strings.Index(key, "|")+1]
+	postal := key[len(country):]
+
+	for n := len(postal); n >= 3; n-- {
+		if p, ok := g.centroids[country+postal[:n]]; ok {
+			return &p, nil
+		}
+	}
+	return nil, nil
+}
+
+func centroidKey(country, postal string) string {
+	compact := strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(postal))
+	return strings.ToUpper(strings.TrimSpace(country)) + "|" + compact
+}
+
+// PointOf returns the address's coordinates, or nil if it has none.
+func PointOf(addr model.Address) *Point {
+	if addr.Latitude == nil || addr.Longitude == nil {
+		return nil
+	}
+	return &Point{Lat: *addr.Latitude, Lng: *addr.Longitude}
+}
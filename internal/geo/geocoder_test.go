@@ -0,0 +1,69 @@
+package geo
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+const centroids = `country,postal_code,latitude,longitude
+US,94105,37.7898,-122.3942
+GB,SW1A,51.5010,-0.1416
+GB,SW1A 1AA,51.5014,-0.1419
+`
+
+func TestCentroidGeocoderGeocode(t *testing.T) {
+	g := NewCentroidGeocoder()
+	if err := g.Load(strings.NewReader(centroids)); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if g.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", g.Len())
+	}
+
+	tests := []struct {
+		name    string
+		country string
+		zip     string
+		want    *Point
+	}{
+		{"exact", "US", "94105", &Point{37.7898, -122.3942}},
+		{"zip+4 falls back to zip", "us", "94105-1234", &Point{37.7898, -122.3942}},
+		{"full postcode", "GB", "sw1a 1aa", &Point{51.5014, -0.1419}},
+		{"unit falls back to outward code", "GB", "SW1A 2AA", &Point{51.5010, -0.1416}},
+		{"other country", "CA", "94105", nil},
+		{"unknown", "US", "10001", nil},
+		{"too short", "US", "94", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := g.Geocode(context.Background(), model.Address{Country: tt.country, ZipCode: tt.zip})
+			if err != nil {
+				t.Fatalf("Geocode: %v", err)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("Geocode(%s %s) = %v, want %v", tt.country, tt.zip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCentroidGeocoderLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"bad coordinates after header", "US,94105,north,west\nUS,10001,x,y\n"},
+		{"out of range", "US,94105,91,0\n"},
+		{"wrong column count", "US,94105,37.7\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewCentroidGeocoder().Load(strings.NewReader(tt.in)); err == nil {
+				t.Error("Load accepted invalid data")
+			}
+		})
+	}
+}
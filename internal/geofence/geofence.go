@@ -0,0 +1,58 @@
+package geofence
+
+import (
+	"github.com/bharathbbg/delivery-service/internal/geo"
+)
+
+// Fence kinds
+const (
+	KindHub         = "HUB"
+	KindDestination = "DESTINATION"
+)
+
+type Fence struct {
+	ID     string    `json:"id"`
+	Kind   string    `json:"kind"`
+	RefID  string    `json:"ref_id"` // hub or delivery ID
+	Name   string    `json:"name"`
+	Center geo.Point `json:"center"`
+	Radius float64   `json:"radius"` // meters
+	Sticky bool      `json:"-"`      // once entered, exits are not reported
+}
+
+type Transition struct {
+	Fence   Fence
+	Entered bool // false means exited
+}
+
+// Tracker detects fence crossings with hysteresis: a courier enters a fence
+// inside Radius but only leaves it beyond Radius*ExitFactor, so jitter around
+// the boundary does not flap between enter and exit.
+type Tracker struct {
+	ExitFactor float64
+}
+
+// Evaluate updates inside (fence ID -> currently inside) for a new position
+// and returns the crossings. Fixes less accurate than a fence's radius are
+// ignored for that fence.
+func (t Tracker) Evaluate(inside map[string]bool, fences []Fence, p geo.Point, accuracy float64) []Transition {
+	var transitions []Transition
+
+	for _, f := range fences {
+		if accuracy > f.Radius {
+			continue
+		}
+
+		d := geo.Distance(p, f.Center)
+		switch {
+		case !inside[f.ID] && d <= f.Radius:
+			inside[f.ID] = true
+			transitions = append(transitions, Transition{Fence: f, Entered: true})
+		case inside[f.ID] && !f.Sticky && d > f.Radius*t.ExitFactor:
+			delete(inside, f.ID)
+			transitions = append(transitions, Transition{Fence: f, Entered: false})
+		}
+	}
+
+	return transitions
+}
@@ -0,0 +1,85 @@
+package geofence
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bharathbbg/delivery-service/internal/geo"
+)
+
+// north returns a point the given distance due north of the equator origin.
+func north(meters float64) geo.Point {
+	return geo.Point{Lat: meters / 111195.08}
+}
+
+func TestTrackerEvaluate(t *testing.T) {
+	hub := Fence{ID: "hub", Kind: KindHub, Center: geo.Point{}, Radius: 100}
+	door := Fence{ID: "door", Kind: KindDestination, Center: geo.Point{}, Radius: 100, Sticky: true}
+
+	// Each step is a fix and the crossings it should report: "+id" for an
+	// entry, "-id" for an exit
+	tests := []struct {
+		name     string
+		fences   []Fence
+		steps    []float64 // meters north of the fence center
+		accuracy float64
+		want     [][]string
+	}{
+		{
+			name:   "enter and exit",
+			fences: []Fence{hub},
+			steps:  []float64{300, 90, 50, 160},
+			want:   [][]string{nil, {"+hub"}, nil, {"-hub"}},
+		},
+		{
+			name:   "jitter around the boundary",
+			fences: []Fence{hub},
+			steps:  []float64{99, 110, 98, 140, 101},
+			want:   [][]string{{"+hub"}, nil, nil, nil, nil},
+		},
+		{
+			name:   "exit needs exit factor",
+			fences: []Fence{hub},
+			steps:  []float64{50, 149, 151},
+			want:   [][]string{{"+hub"}, nil, {"-hub"}},
+		},
+		{
+			name:   "sticky fences never exit",
+			fences: []Fence{door},
+			steps:  []float64{50, 1000, 20},
+			want:   [][]string{{"+door"}, nil, nil},
+		},
+		{
+			name:     "inaccurate fixes are ignored",
+			fences:   []Fence{hub},
+			steps:    []float64{50, 1000},
+			accuracy: 150,
+			want:     [][]string{nil, nil},
+		},
+		{
+			name:   "several fences",
+			fences: []Fence{hub, door},
+			steps:  []float64{50, 500},
+			want:   [][]string{{"+hub", "+door"}, {"-hub"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := Tracker{ExitFactor: 1.5}
+			inside := make(map[string]bool)
+			for i, meters := range tt.steps {
+				var got []string
+				for _, tr := range tracker.Evaluate(inside, tt.fences, north(meters), tt.accuracy) {
+					sign := "-"
+					if tr.Entered {
+						sign = "+"
+					}
+					got = append(got, sign+tr.Fence.ID)
+				}
+				if !reflect.DeepEqual(got, tt.want[i]) {
+					t.Errorf("step %d at %.0fm: crossings %v, want %v", i, meters, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
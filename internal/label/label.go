@@ -0,0 +1,104 @@
+// Package label renders shipping labels as PDF or ZPL.
+package label
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// Formats
+const (
+	FormatPDF = "pdf"
+	FormatZPL = "zpl"
+)
+
+// Party is the sender or recipient printed on a label.
+type Party struct {
+	Name    string
+	Address model.Address
+}
+
+// Label is one printed label. A delivery with several parcels gets a label per
+// parcel, numbered Piece of Pieces.
+type Label struct {
+	TrackingNumber string
+	OrderID        string
+	ServiceLevel   string
+	RouteCode      string
+	From           Party
+	To             Party
+	Piece          int
+	Pieces         int
+	WeightKg       float64
+	PrintedAt      time.Time
+}
+
+// Render writes the labels in format, one page (or ZPL label) each.
+func Render(w io.Writer, format string, labels []Label) error {
+	switch format {
+	case FormatPDF:
+		return RenderPDF(w, labels)
+	case FormatZPL:
+		return RenderZPL(w, labels)
+	}
+	return fmt.Errorf("unsupported label format %q", format)
+}
+
+func ContentType(format string) string {
+	if format == FormatZPL {
+		return "application/zpl"
+	}
+	return "application/pdf"
+}
+
+func IsFormat(format string) bool {
+	return format == FormatPDF || format == FormatZPL
+}
+
+// addressLines formats an address for printing.
+func addressLines(addr model.Address) []string {
+	var lines []string
+	if addr.Street != "" {
+		lines = append(lines, addr.Street)
+	}
+	cityLine := strings.TrimSpace(strings.Join(nonEmpty(addr.City, addr.State, addr.ZipCode), " "))
+	if cityLine != "" {
+		lines = append(lines, cityLine)
+	}
+	if addr.Country != "" {
+		lines = append(lines, addr.Country)
+	}
+	return lines
+}
+
+// details is the line of small print at the bottom of a label.
+func (l Label) details() string {
+	parts := []string{}
+	if l.Pieces > 0 {
+		parts = append(parts, fmt.Sprintf("Piece %d of %d", l.Piece, l.Pieces))
+	}
+	if l.WeightKg > 0 {
+		parts = append(parts, fmt.Sprintf("%.2f kg", l.WeightKg))
+	}
+	if l.OrderID != "" {
+		parts = append(parts, "Order "+l.OrderID)
+	}
+	if !l.PrintedAt.IsZero() {
+		parts = append(parts, l.PrintedAt.Format("2006-01-02"))
+	}
+	return strings.Join(parts, "  |  ")
+}
+
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+package label
+
+import (
+	"io"
+
+	"github.com/bharathbbg/delivery-service/internal/pdf"
+)
+
+// 4x6 inch page in PDF points
+const (
+	pageWidth  = 288.0
+	pageHeight = 432.0
+	margin     = 14.0
+)
+
+// RenderPDF writes a PDF with one 4x6 inch page per label.
+func RenderPDF(w io.Writer, labels []Label) error {
+	doc := pdf.NewDocument()
+	for _, l := range labels {
+		content, err := pageContent(l)
+		if err != nil {
+			return err
+		}
+		doc.AddPage(pageWidth, pageHeight, content)
+	}
+	return doc.Write(w)
+}
+
+func pageContent(l Label) (*pdf.Content, error) {
+	c := &pdf.Content{}
+	regular, bold := pdf.FontRegular, pdf.FontBold
+
+	// Sender
+	y := pageHeight - margin - 8
+	c.Text(bold, 8, margin, y, "FROM: "+l.From.Name)
+	for _, line := range addressLines(l.From.Address) {
+		y -= 10
+		c.Text(regular, 8, margin, y, line)
+	}
+
+	// QR code in the top right corner
+	if err := c.QR(l.TrackingNumber, pageWidth-margin-72, pageHeight-margin-72, 72); err != nil {
+		return nil, err
+	}
+	rule(c, pageHeight-100)
+
+	// Recipient
+	y = pageHeight - 118
+	c.Text(bold, 10, margin, y, "TO:")
+	y -= 16
+	c.Text(bold, 14, margin, y, l.To.Name)
+	for _, line := range addressLines(l.To.Address) {
+		y -= 16
+		c.Text(regular, 14, margin, y, line)
+	}
+	rule(c, 200)
+
+	// Service level and route code
+	c.Text(bold, 24, margin, 168, l.ServiceLevel)
+	c.Text(bold, 14, margin, 148, "ROUTE "+l.RouteCode)
+	rule(c, 138)
+
+	// Tracking barcode with its interpretation line
+	if err := c.Code128(l.TrackingNumber, margin+6, 58, pageWidth-2*margin-12, 66); err != nil {
+		return nil, err
+	}
+	c.Text(bold, 11, margin+6, 44, l.TrackingNumber)
+	c.Text(regular, 7, margin, margin, l.details())
+
+	return c, nil
+}
+
+// rule draws a horizontal line across the label at y.
+func rule(c *pdf.Content, y float64) {
+	c.Rect(margin, y, pageWidth-2*margin, 1)
+}
@@ -0,0 +1,60 @@
+package label
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RenderZPL writes 4x6 inch labels for 203 dpi Zebra printers. Barcodes are
+// drawn by the printer.
+func RenderZPL(w io.Writer, labels []Label) error {
+	bw := bufio.NewWriter(w)
+	for _, l := range labels {
+		writeZPL(bw, l)
+	}
+	return bw.Flush()
+}
+
+func writeZPL(w *bufio.Writer, l Label) {
+	fmt.Fprint(w, "^XA^CI28^PW812^LL1218\n")
+
+	// Sender
+	y := 30
+	fmt.Fprintf(w, "^FO30,%d^A0N,24,24%s\n", y, zplField("FROM: "+l.From.Name))
+	for _, line := range addressLines(l.From.Address) {
+		y += 28
+		fmt.Fprintf(w, "^FO30,%d^A0N,24,24%s\n", y, zplField(line))
+	}
+	fmt.Fprintf(w, "^FO560,20^BQN,2,6%s\n", zplField("QA,"+l.TrackingNumber))
+	fmt.Fprint(w, "^FO20,250^GB772,3,3^FS\n")
+
+	// Recipient
+	y = 280
+	fmt.Fprintf(w, "^FO30,%d^A0N,30,30%s\n", y, zplField("TO:"))
+	y += 40
+	fmt.Fprintf(w, "^FO30,%d^A0N,44,44%s\n", y, zplField(l.To.Name))
+	for _, line := range addressLines(l.To.Address) {
+		y += 50
+		fmt.Fprintf(w, "^FO30,%d^A0N,44,44%s\n", y, zplField(line))
+	}
+	fmt.Fprint(w, "^FO20,620^GB772,3,3^FS\n")
+
+	// Service level and route code
+	fmt.Fprintf(w, "^FO30,650^A0N,70,70%s\n", zplField(l.ServiceLevel))
+	fmt.Fprintf(w, "^FO30,740^A0N,40,40%s\n", zplField("ROUTE "+l.RouteCode))
+	fmt.Fprint(w, "^FO20,800^GB772,3,3^FS\n")
+
+	// Tracking barcode with its interpretation line
+	fmt.Fprintf(w, "^FO60,840^BY3^BCN,180,Y,N,N%s\n", zplField(l.TrackingNumber))
+	fmt.Fprintf(w, "^FO30,1140^A0N,24,24%s\n", zplField(l.details()))
+	fmt.Fprint(w, "^XZ\n")
+}
+
+// zplField wraps data in a hex-escaped field so that control characters in
+// addresses cannot break the label.
+func zplField(data string) string {
+	escaped := strings.NewReplacer("_", "_5F", "^", "_5E", "~", "_7E").Replace(data)
+	return "^FH^FD" + escaped + "^FS"
+}
@@ -0,0 +1,38 @@
+package manifest
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+var csvHeader = []string{
+	"manifest_id", "date", "courier_id", "route_id", "sequence", "delivery_id", "tracking_number",
+	"order_id", "status", "city", "zip_code", "parcels", "weight_kg", "cod_due",
+	"cod_currency",
+}
+
+// RenderCSV writes one row per delivery. The manifest's identity is repeated
+// on every row so that exports from several manifests can be concatenated.
+func RenderCSV(w io.Writer, m *model.Manifest) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, item := range m.Items {
+		err := out.Write([]string{
+			m.ID, m.Date, m.CourierID, m.RouteID,
+			strconv.Itoa(item.Sequence), item.DeliveryID, item.TrackingNumber, item.OrderID,
+			item.Status, item.City, item.ZipCode, strconv.Itoa(item.Parcels),
+			strconv.FormatFloat(item.WeightKg, 'f', 3, 64), amount(item.CODDueCents),
+			item.CODCurrency,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,144 @@
+package manifest
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/pdf"
+)
+
+// A4 portrait in PDF points
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	margin     = 40.0
+	rowHeight  = 14.0
+
+	rowsPerPage     = 42
+	rowsOnLastPage  = 34 // leaves room for the totals and signatures
+	tableTop        = pageHeight - 142
+	footerClearance = 24
+)
+
+// column is one column of the item table: its heading, where it starts and
+// how many characters fit in it.
+type column struct {
+	title string
+	x     float64
+	width int
+	value func(*model.ManifestItem) string
+}
+
+var columns = []column{
+	{"#", margin, 4, func(i *model.ManifestItem) string { return strconv.Itoa(i.Sequence) }},
+	{"TRACKING", margin + 26, 20, func(i *model.ManifestItem) string { return i.TrackingNumber }},
+	{"ORDER", margin + 146, 16, func(i *model.ManifestItem) string { return i.OrderID }},
+	{"STATUS", margin + 246, 18, func(i *model.ManifestItem) string { return i.Status }},
+	{"CITY", margin + 346, 14, func(i *model.ManifestItem) string { return i.City }},
+	{"PCS", margin + 426, 4, func(i *model.ManifestItem) string { return strconv.Itoa(i.Parcels) }},
+	{"KG", margin + 456, 8, func(i *model.ManifestItem) string { return strconv.FormatFloat(i.WeightKg, 'f', 2, 64) }},
+	{"COD", margin + 498, 10, func(i *model.ManifestItem) string { return amount(i.CODDueCents) }},
+}
+
+// RenderPDF writes an A4 manifest: a table of deliveries over as many pages as
+// needed, then the totals, the signature and lines for the courier and the
+// supervisor to sign the printed copy.
+func RenderPDF(w io.Writer, m *model.Manifest) error {
+	pages := paginate(m.Items)
+	doc := pdf.NewDocument()
+	for i, items := range pages {
+		c := &pdf.Content{}
+		header(c, m, i+1, len(pages))
+		y := table(c, items)
+		if i == len(pages)-1 {
+			footer(c, m, y-footerClearance)
+		}
+		doc.AddPage(pageWidth, pageHeight, c)
+	}
+	return doc.Write(w)
+}
+
+// paginate splits items into pages, making sure the last page has room for
+// the footer. There is always at least one page.
+func paginate(items []*model.ManifestItem) [][]*model.ManifestItem {
+	var pages [][]*model.ManifestItem
+	for len(items) > rowsOnLastPage {
+		n := rowsPerPage
+		if len(items) < n {
+			n = len(items)
+		}
+		pages = append(pages, items[:n])
+		items = items[n:]
+	}
+	return append(pages, items)
+}
+
+func header(c *pdf.Content, m *model.Manifest, page, pages int) {
+	regular, bold := pdf.FontRegular, pdf.FontBold
+	y := pageHeight - margin - 18
+	c.Text(bold, 18, margin, y, "DELIVERY MANIFEST")
+	c.Text(regular, 9, pageWidth-margin-70, y, fmt.Sprintf("Page %d of %d", page, pages))
+
+	y -= 22
+	c.Text(regular, 10, margin, y, "Manifest: "+m.ID)
+	c.Text(regular, 10, margin+300, y, "Date: "+m.Date)
+	y -= 14
+	c.Text(regular, 10, margin, y, "Courier: "+m.CourierID)
+	c.Text(regular, 10, margin+300, y, "Status: "+m.Status)
+	if m.RouteID != "" {
+		y -= 14
+		c.Text(regular, 10, margin, y, "Route: "+m.RouteID)
+	}
+	c.Rect(margin, tableTop+rowHeight, pageWidth-2*margin, 1)
+}
+
+// table draws the column headings and rows, returning the y below the last
+// row.
+func table(c *pdf.Content, items []*model.ManifestItem) float64 {
+	y := tableTop
+	for _, col := range columns {
+		c.Text(pdf.FontBold, 8, col.x, y, col.title)
+	}
+	c.Rect(margin, y-4, pageWidth-2*margin, 0.5)
+
+	for _, item := range items {
+		y -= rowHeight
+		for _, col := range columns {
+			c.Text(pdf.FontRegular, 8, col.x, y, fit(col.value(item), col.width))
+		}
+	}
+	return y
+}
+
+func footer(c *pdf.Content, m *model.Manifest, y float64) {
+	regular, bold := pdf.FontRegular, pdf.FontBold
+	c.Rect(margin, y+12, pageWidth-2*margin, 1)
+	c.Text(bold, 10, margin, y, fmt.Sprintf("TOTAL  %d deliveries   %d parcels   %.2f kg   COD due %s %s",
+		m.DeliveryCount, m.ParcelCount, m.WeightKg, amount(m.CODDueCents), m.CODCurrency))
+
+	y -= 18
+	if m.Status == model.ManifestClosed && m.ClosedAt != nil {
+		c.Text(regular, 9, margin, y, fmt.Sprintf("Closed by %s at %s", m.ClosedBy, m.ClosedAt.UTC().Format("2006-01-02 15:04 MST")))
+		y -= 12
+		c.Text(regular, 7, margin, y, "Signature: "+m.Signature)
+	} else {
+		c.Text(bold, 9, margin, y, "DRAFT - this manifest is still open and may change")
+	}
+
+	y -= 40
+	c.Rect(margin, y, 200, 0.5)
+	c.Rect(pageWidth-margin-200, y, 200, 0.5)
+	c.Text(regular, 8, margin, y-10, "Courier")
+	c.Text(regular, 8, pageWidth-margin-200, y-10, "Supervisor")
+}
+
+// fit shortens s to at most n characters so it stays inside its column.
+func fit(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "~"
+}
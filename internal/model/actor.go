@@ -0,0 +1,16 @@
+package model
+
+// Actor roles
+const (
+	RoleCourier = "COURIER"
+	RoleSupport = "SUPPORT"
+	RoleAdmin   = "ADMIN"
+	RoleSystem  = "SYSTEM"
+)
+
+// Actor identifies who is making a request. The API gateway authenticates
+// callers and forwards their identity; this service only authorizes.
+type Actor struct {
+	ID   string `json:"id"`
+	Role string `json:"role"`
+}
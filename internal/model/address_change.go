@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+)
+
+// RedirectableStatuses are the statuses a delivery's destination can change
+// in. Once a courier is out with the parcel it is too late.
+var RedirectableStatuses = []string{StatusPending, StatusInTransit, StatusFailedAttempt}
+
+// AddressChange is one entry of a delivery's address history.
+type AddressChange struct {
+	ID            string    `json:"id" db:"id"`
+	DeliveryID    string    `json:"delivery_id" db:"delivery_id"`
+	OldAddress    Address   `json:"old_address" db:"old_address"`
+	NewAddress    Address   `json:"new_address" db:"new_address"`
+	OldZoneID     string    `json:"old_zone_id,omitempty" db:"old_zone_id"`
+	NewZoneID     string    `json:"new_zone_id,omitempty" db:"new_zone_id"`
+	Reason        string    `json:"reason,omitempty" db:"reason"`
+	PickupPointID string    `json:"pickup_point_id,omitempty" db:"pickup_point_id"`
+	ChangedBy     string    `json:"changed_by" db:"changed_by"`
+	ChangedAt     time.Time `json:"changed_at" db:"changed_at"`
+
+	LastMileHubID string `json:"-"` // hub serving the new address, if it changed
+}
+
+type UpdateAddressRequest struct {
+	DeliveryID string  `json:"-"`
+	Actor      Actor   `json:"-"`
+	Address    Address `json:"address" binding:"required"`
+	Reason     string  `json:"reason"`
+}
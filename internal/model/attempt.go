@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+)
+
+// Failed attempt reason codes
+const (
+	AttemptNoAccess        = "NO_ACCESS"
+	AttemptRecipientAbsent = "RECIPIENT_ABSENT"
+	AttemptRefused         = "REFUSED"
+	AttemptAddressNotFound = "ADDRESS_NOT_FOUND"
+)
+
+func IsAttemptReason(code string) bool {
+	switch code {
+	case AttemptNoAccess, AttemptRecipientAbsent, AttemptRefused, AttemptAddressNotFound:
+		return true
+	}
+	return false
+}
+
+type FailedAttemptRequest struct {
+	DeliveryID string   `json:"-"`
+	Actor      Actor    `json:"-"`
+	ReasonCode string   `json:"reason_code" binding:"required"`
+	Note       string   `json:"note"`
+	Latitude   *float64 `json:"latitude,omitempty"`
+	Longitude  *float64 `json:"longitude,omitempty"`
+}
+
+// DeliveryAttempt is one failed attempt taken from the event trail.
+type DeliveryAttempt struct {
+	Number     int       `json:"number"`
+	ReasonCode string    `json:"reason_code"`
+	Note       string    `json:"note,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// AttemptHistory lists the failed attempts in events, oldest first.
+func AttemptHistory(events []*DeliveryEvent) []DeliveryAttempt {
+	var attempts []DeliveryAttempt
+	for _, e := range events {
+		if e.Status != StatusFailedAttempt {
+			continue
+		}
+		attempts = append(attempts, DeliveryAttempt{
+			Number:     len(attempts) + 1,
+			ReasonCode: e.ReasonCode,
+			Note:       e.Description,
+			Timestamp:  e.Timestamp,
+		})
+	}
+	return attempts
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+)
+
+// Cancellation reason codes
+const (
+	CancelCustomerRequest = "CUSTOMER_REQUEST"
+	CancelOrderCancelled  = "ORDER_CANCELLED"
+	CancelAddressIssue    = "ADDRESS_ISSUE"
+	CancelDuplicate       = "DUPLICATE"
+	CancelFraud           = "FRAUD"
+	CancelOther           = "OTHER"
+)
+
+func IsCancelReason(code string) bool {
+	switch code {
+	case CancelCustomerRequest, CancelOrderCancelled, CancelAddressIssue, CancelDuplicate, CancelFraud, CancelOther:
+		return true
+	}
+	return false
+}
+
+// CancellableStatuses are the statuses a delivery can be cancelled from. Once
+// a courier is out with the parcel it has to be delivered or returned instead.
+var CancellableStatuses = []string{StatusPending, StatusInTransit, StatusFailedAttempt}
+
+type CancelDeliveryRequest struct {
+	DeliveryID string `json:"-"`
+	Actor      Actor  `json:"-"`
+	ReasonCode string `json:"reason_code" binding:"required"`
+	Note       string `json:"note"`
+}
+
+// Cancellation records why and by whom a delivery was cancelled.
+type Cancellation struct {
+	ReasonCode  string    `json:"reason_code" db:"cancel_reason"`
+	Note        string    `json:"note,omitempty" db:"cancel_note"`
+	CancelledBy string    `json:"cancelled_by" db:"cancelled_by"`
+	CancelledAt time.Time `json:"cancelled_at" db:"cancelled_at"`
+}
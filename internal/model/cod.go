@@ -0,0 +1,132 @@
+package model
+
+import (
+	"time"
+)
+
+// Ways a recipient can pay on delivery
+const (
+	PaymentCash   = "CASH"
+	PaymentCard   = "CARD"
+	PaymentMobile = "MOBILE" // mobile wallet or instant bank transfer
+)
+
+// Cash ledger entry types
+const (
+	LedgerCollection = "COLLECTION" // cash taken from a recipient
+	LedgerDeposit    = "DEPOSIT"    // cash handed in by the courier
+)
+
+// Reconciliation statuses
+const (
+	ReconciliationBalanced    = "BALANCED"
+	ReconciliationDiscrepancy = "DISCREPANCY"
+)
+
+// Discrepancy kinds
+const (
+	DiscrepancyMissingCollection      = "MISSING_COLLECTION"      // delivered but nothing collected
+	DiscrepancyAmountMismatch         = "AMOUNT_MISMATCH"         // collected a different amount than due
+	DiscrepancyUnmanifestedCollection = "UNMANIFESTED_COLLECTION" // collected for a delivery on no closed manifest
+	DiscrepancyCashNotDeposited       = "CASH_NOT_DEPOSITED"      // cash collected and deposited differ
+)
+
+// CODPayment is what the courier (or pickup counter) took from the recipient.
+type CODPayment struct {
+	AmountCents int64  `json:"amount_cents"`
+	Currency    string `json:"currency,omitempty"` // defaults to the delivery's COD currency
+	Method      string `json:"method"`
+	Reference   string `json:"reference,omitempty"` // card slip or transfer reference
+}
+
+// CODCollection records the payment taken when a COD delivery was delivered.
+type CODCollection struct {
+	ID          string    `json:"id" db:"id"`
+	DeliveryID  string    `json:"delivery_id" db:"delivery_id"`
+	CourierID   string    `json:"courier_id,omitempty" db:"courier_id"` // empty when paid at a pickup point
+	DueCents    int64     `json:"due_cents" db:"due_cents"`
+	AmountCents int64     `json:"amount_cents" db:"amount_cents"`
+	Currency    string    `json:"currency" db:"currency"`
+	Method      string    `json:"method" db:"method"`
+	Reference   string    `json:"reference,omitempty" db:"reference"`
+	CollectedAt time.Time `json:"collected_at" db:"collected_at"`
+}
+
+// LedgerEntry is a movement of cash held by a courier. Collections are
+// positive, deposits negative.
+type LedgerEntry struct {
+	ID          string    `json:"id" db:"id"`
+	CourierID   string    `json:"courier_id" db:"courier_id"`
+	Type        string    `json:"type" db:"entry_type"`
+	DeliveryID  string    `json:"delivery_id,omitempty" db:"delivery_id"`
+	AmountCents int64     `json:"amount_cents" db:"amount_cents"`
+	Currency    string    `json:"currency" db:"currency"`
+	Reference   string    `json:"reference,omitempty" db:"reference"`
+	RecordedBy  string    `json:"recorded_by" db:"recorded_by"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// CashBalance is the cash a courier holds in one currency.
+type CashBalance struct {
+	Currency    string `json:"currency"`
+	AmountCents int64  `json:"amount_cents"`
+}
+
+type CashLedger struct {
+	CourierID string         `json:"courier_id"`
+	Balances  []CashBalance  `json:"balances"`
+	Entries   []*LedgerEntry `json:"entries"`
+}
+
+type DepositRequest struct {
+	CourierID   string `json:"-"`
+	Actor       Actor  `json:"-"`
+	AmountCents int64  `json:"amount_cents"`
+	Currency    string `json:"currency"`
+	Reference   string `json:"reference,omitempty"`
+}
+
+// CODReconciliation compares, for one courier, date and currency, the COD due
+// on the day's closed manifests with what was collected and deposited.
+type CODReconciliation struct {
+	ID                 string            `json:"id" db:"id"`
+	CourierID          string            `json:"courier_id" db:"courier_id"`
+	Date               string            `json:"date" db:"reconciliation_date"` // YYYY-MM-DD
+	Currency           string            `json:"currency" db:"currency"`
+	Status             string            `json:"status" db:"status"`
+	DueCents           int64             `json:"due_cents" db:"due_cents"`
+	CollectedCents     int64             `json:"collected_cents" db:"collected_cents"`
+	CashCollectedCents int64             `json:"cash_collected_cents" db:"cash_collected_cents"`
+	DepositedCents     int64             `json:"deposited_cents" db:"deposited_cents"`
+	ManifestIDs        []string          `json:"manifest_ids"`
+	Discrepancies      []*CODDiscrepancy `json:"discrepancies"`
+	ReconciledBy       string            `json:"reconciled_by" db:"reconciled_by"`
+	ReconciledAt       time.Time         `json:"reconciled_at" db:"reconciled_at"`
+}
+
+type CODDiscrepancy struct {
+	ID               string `json:"id" db:"id"`
+	ReconciliationID string `json:"reconciliation_id" db:"reconciliation_id"`
+	CourierID        string `json:"courier_id" db:"courier_id"`
+	Date             string `json:"date" db:"reconciliation_date"`
+	Currency         string `json:"currency" db:"currency"`
+	Kind             string `json:"kind" db:"kind"`
+	DeliveryID       string `json:"delivery_id,omitempty" db:"delivery_id"`
+	ManifestID       string `json:"manifest_id,omitempty" db:"manifest_id"`
+	ExpectedCents    int64  `json:"expected_cents" db:"expected_cents"`
+	ActualCents      int64  `json:"actual_cents" db:"actual_cents"`
+}
+
+type ReconcileRequest struct {
+	CourierID string `json:"-"`
+	Actor     Actor  `json:"-"`
+	Date      string `json:"date,omitempty"` // defaults to today
+}
+
+// DiscrepancyFilter narrows a discrepancy report. Dates are YYYY-MM-DD and
+// inclusive.
+type DiscrepancyFilter struct {
+	CourierID string
+	From      string
+	To        string
+}
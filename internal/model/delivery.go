@@ -4,17 +4,46 @@ import (
 	"time"
 )
 
+// Delivery types
+const (
+	DeliveryTypeOutbound = "OUTBOUND"
+	DeliveryTypeReturn   = "RETURN" // collected from the customer and brought back to a warehouse
+)
+
 type Delivery struct {
-	ID                   string         `json:"id" db:"id"`
-	OrderID              string         `json:"order_id" db:"order_id"`
-	ShippingAddress      Address        `json:"shipping_address"`
-	CourierID            string         `json:"courier_id" db:"courier_id"`
-	Status               string         `json:"status" db:"status"`
-	TrackingNumber       string         `json:"tracking_number" db:"tracking_number"`
+	ID                    string        `json:"id" db:"id"`
+	OrderID               string        `json:"order_id" db:"order_id"`
+	Type                  string        `json:"type" db:"delivery_type"`
+	ParentDeliveryID      string        `json:"parent_delivery_id,omitempty" db:"parent_delivery_id"`
+	ReturnReason          string        `json:"return_reason,omitempty" db:"return_reason"`
+	ReturnTo              string        `json:"return_to,omitempty" db:"return_to"`
+	ShippingAddress       Address       `json:"shipping_address"`
+	CourierID             string        `json:"courier_id" db:"courier_id"`
+	Status                string        `json:"status" db:"status"`
+	TrackingNumber        string        `json:"tracking_number" db:"tracking_number"`
+	ServiceLevel          string        `json:"service_level" db:"service_level"`
+	ZoneID                string        `json:"zone_id,omitempty" db:"zone_id"`
+	ScheduledFor          *time.Time    `json:"scheduled_for,omitempty" db:"scheduled_for"`
+	SlotReservationID     string        `json:"slot_reservation_id,omitempty" db:"slot_reservation_id"`
+	TimeWindow            *TimeWindow   `json:"time_window,omitempty"`
+	RequiresVerification  bool          `json:"requires_verification" db:"requires_verification"`
+	AttemptCount          int           `json:"attempt_count" db:"attempt_count"`
+	NextAttemptAt         *time.Time    `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	Parcels               []*Parcel     `json:"parcels,omitempty"`
+	Cancellation          *Cancellation `json:"cancellation,omitempty"`
+	PickupPointID         string        `json:"pickup_point_id,omitempty" db:"pickup_point_id"`
+	PickupExpiresAt       *time.Time    `json:"pickup_expires_at,omitempty" db:"pickup_expires_at"`
+	CurrentHubID          string        `json:"current_hub_id,omitempty" db:"current_hub_id"` // hub the delivery is on site at
+	Legs                  []*TransitLeg `json:"legs,omitempty"`
+	ManifestID            string        `json:"manifest_id,omitempty" db:"manifest_id"`           // closed manifest locking the courier assignment
+	CODAmountCents        int64         `json:"cod_amount_cents,omitempty" db:"cod_amount_cents"` // to collect from the recipient on delivery
+	CODCurrency           string        `json:"cod_currency,omitempty" db:"cod_currency"`
+	Carrier               string        `json:"carrier,omitempty" db:"carrier"` // third-party carrier making the delivery
+	CarrierTrackingNumber string        `json:"carrier_tracking_number,omitempty" db:"carrier_tracking_number"`
 	EstimatedDeliveryTime time.Time     `json:"estimated_delivery_time" db:"estimated_delivery_time"`
-	ActualDeliveryTime   *time.Time     `json:"actual_delivery_time,omitempty" db:"actual_delivery_time"`
-	CreatedAt           time.Time       `json:"created_at" db:"created_at"`
-	UpdatedAt           time.Time       `json:"updated_at" db:"updated_at"`
+	ActualDeliveryTime    *time.Time    `json:"actual_delivery_time,omitempty" db:"actual_delivery_time"`
+	CreatedAt             time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time     `json:"updated_at" db:"updated_at"`
 }
 
 type DeliveryEvent struct {
@@ -23,26 +52,65 @@ type DeliveryEvent struct {
 	Status      string    `json:"status" db:"status"`
 	Location    string    `json:"location" db:"location"`
 	Description string    `json:"description" db:"description"`
+	Latitude    *float64  `json:"latitude,omitempty" db:"latitude"`
+	Longitude   *float64  `json:"longitude,omitempty" db:"longitude"`
+	ReasonCode  string    `json:"reason_code,omitempty" db:"reason_code"`
+	ParcelID    string    `json:"parcel_id,omitempty" db:"parcel_id"`
+	HubID       string    `json:"hub_id,omitempty" db:"hub_id"`
+	Carrier     string    `json:"carrier,omitempty" db:"carrier"` // set on carrier tracking updates
 	Timestamp   time.Time `json:"timestamp" db:"timestamp"`
 }
 
 type Address struct {
-	Street  string `json:"street" db:"street"`
-	City    string `json:"city" db:"city"`
-	State   string `json:"state" db:"state"`
-	Country string `json:"country" db:"country"`
-	ZipCode string `json:"zip_code" db:"zip_code"`
+	Street    string   `json:"street" db:"street"`
+	City      string   `json:"city" db:"city"`
+	State     string   `json:"state" db:"state"`
+	Country   string   `json:"country" db:"country"`
+	ZipCode   string   `json:"zip_code" db:"zip_code"`
+	Latitude  *float64 `json:"latitude,omitempty" db:"latitude"`
+	Longitude *float64 `json:"longitude,omitempty" db:"longitude"`
 }
 
 // Request/Response models
 type CreateDeliveryRequest struct {
-	OrderID         string  `json:"order_id" binding:"required"`
-	ShippingAddress Address `json:"shipping_address" binding:"required"`
+	OrderID              string     `json:"order_id" binding:"required"`
+	ShippingAddress      Address    `json:"shipping_address" binding:"required"`
+	ServiceLevel         string     `json:"service_level"`
+	ScheduledFor         *time.Time `json:"scheduled_for,omitempty"`
+	SlotReservationID    string     `json:"slot_reservation_id,omitempty"`
+	RequiresVerification bool       `json:"requires_verification,omitempty"` // recipient must confirm a one-time PIN
+	Parcels              []*Parcel  `json:"parcels,omitempty"`
+	PickupPointID        string     `json:"pickup_point_id,omitempty"`  // deliver to a pickup point instead of the address
+	CODAmountCents       int64      `json:"cod_amount_cents,omitempty"` // cash on delivery
+	CODCurrency          string     `json:"cod_currency,omitempty"`
+
+	// Set when creating a return for an existing delivery
+	Type             string `json:"-"`
+	ParentDeliveryID string `json:"-"`
+	ReturnReason     string `json:"-"`
+	ReturnTo         string `json:"-"`
+}
+
+type CreateReturnRequest struct {
+	DeliveryID        string     `json:"-"`
+	Actor             Actor      `json:"-"`
+	Reason            string     `json:"reason" binding:"required"`
+	SlotReservationID string     `json:"slot_reservation_id,omitempty"` // pickup window
+	PickupDate        *time.Time `json:"pickup_date,omitempty"`
 }
 
 type UpdateDeliveryRequest struct {
-	ID          string `json:"-"`
-	Status      string `json:"status" binding:"required"`
-	Location    string `json:"location"`
-	Description string `json:"description"`
-}
\ No newline at end of file
+	ID          string      `json:"-"`
+	Status      string      `json:"status" binding:"required"`
+	Location    string      `json:"location"`
+	Description string      `json:"description"`
+	Latitude    *float64    `json:"latitude,omitempty"`
+	Longitude   *float64    `json:"longitude,omitempty"`
+	PIN         string      `json:"pin,omitempty"` // one-time PIN for deliveries that require verification
+	COD         *CODPayment `json:"cod,omitempty"` // payment taken when a COD delivery is delivered
+
+	// Set by the service from COD for the repository to record
+	Collection *CODCollection `json:"-"`
+	// Set by the service when the DELIVERED status comes with a proof
+	Proof *ProofOfDelivery `json:"-"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// Domain event types
+const (
+	DomainEventDeliveryCancelled = "DeliveryCancelled"
+)
+
+// DomainEvent is published for other services to react to, for example to
+// refund or restock after a cancellation.
+type DomainEvent struct {
+	Type       string      `json:"type"`
+	DeliveryID string      `json:"delivery_id"`
+	OrderID    string      `json:"order_id"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Actor      Actor       `json:"actor"`
+	Data       interface{} `json:"data,omitempty"`
+}
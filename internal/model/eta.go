@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+type ETARevision struct {
+	ID                    string    `json:"id" db:"id"`
+	DeliveryID            string    `json:"delivery_id" db:"delivery_id"`
+	EstimatedDeliveryTime time.Time `json:"estimated_delivery_time" db:"estimated_delivery_time"`
+	Status                string    `json:"status" db:"status"`
+	CreatedAt             time.Time `json:"created_at" db:"created_at"`
+}
+
+// ETAAccuracyReport compares the first and last ETA revisions of deliveries
+// completed in a period against their actual delivery times.
+type ETAAccuracyReport struct {
+	From              time.Time `json:"from"`
+	To                time.Time `json:"to"`
+	Deliveries        int       `json:"deliveries"`
+	InitialMAEHours   float64   `json:"initial_mae_hours"`
+	FinalMAEHours     float64   `json:"final_mae_hours"`
+	InitialOnTimeRate float64   `json:"initial_on_time_rate"`
+	FinalOnTimeRate   float64   `json:"final_on_time_rate"`
+}
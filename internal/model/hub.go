@@ -0,0 +1,137 @@
+package model
+
+import (
+	"time"
+)
+
+// Hub kinds. A delivery travels origin hub → sort hub → last-mile hub.
+const (
+	HubOrigin   = "ORIGIN"
+	HubSort     = "SORT"
+	HubLastMile = "LAST_MILE"
+)
+
+// Hub scan types
+const (
+	ScanArrived  = "ARRIVED"
+	ScanSorted   = "SORTED"
+	ScanDeparted = "DEPARTED"
+)
+
+// Transit leg statuses
+const (
+	LegPlanned    = "PLANNED"
+	LegInProgress = "IN_PROGRESS"
+	LegCompleted  = "COMPLETED"
+)
+
+// Hub is a warehouse or sortation center in the transit network. Last-mile
+// hubs list the zones they deliver to.
+type Hub struct {
+	ID        string    `json:"id" db:"id"`
+	Code      string    `json:"code" db:"code"`
+	Name      string    `json:"name" db:"name"`
+	Kind      string    `json:"kind" db:"kind"`
+	Address   Address   `json:"address"`
+	ZoneIDs   []string  `json:"zone_ids,omitempty" db:"zone_ids"`
+	Active    bool      `json:"active" db:"active"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// TransitLeg is one hop of a delivery between two hubs.
+type TransitLeg struct {
+	ID         string     `json:"id" db:"id"`
+	DeliveryID string     `json:"delivery_id" db:"delivery_id"`
+	Sequence   int        `json:"sequence" db:"sequence"`
+	FromHubID  string     `json:"from_hub_id" db:"from_hub_id"`
+	ToHubID    string     `json:"to_hub_id" db:"to_hub_id"`
+	Status     string     `json:"status" db:"status"`
+	DepartedAt *time.Time `json:"departed_at,omitempty" db:"departed_at"`
+	ArrivedAt  *time.Time `json:"arrived_at,omitempty" db:"arrived_at"`
+}
+
+// HubScan is a parcel scanned at a hub. TrackingNumber may be a delivery's or
+// one of its parcels'.
+type HubScan struct {
+	HubID          string    `json:"hub_id"`
+	Actor          Actor     `json:"-"`
+	TrackingNumber string    `json:"tracking_number" binding:"required"`
+	ScanType       string    `json:"scan_type" binding:"required"`
+	Timestamp      time.Time `json:"timestamp"` // defaults to now
+}
+
+// Scan result statuses. Duplicates and out-of-order scans are accepted:
+// duplicates are not recorded again, and out-of-order scans are added to the
+// history without moving the delivery.
+const (
+	ScanAccepted   = "ACCEPTED"
+	ScanDuplicate  = "DUPLICATE"
+	ScanOutOfOrder = "OUT_OF_ORDER"
+	ScanRejected   = "REJECTED"
+)
+
+type BulkScanRequest struct {
+	Actor Actor     `json:"-"`
+	Scans []HubScan `json:"scans" binding:"required"`
+}
+
+type ScanResult struct {
+	Index          int    `json:"index"`
+	TrackingNumber string `json:"tracking_number"`
+	DeliveryID     string `json:"delivery_id,omitempty"`
+	Result         string `json:"result"`
+	Error          string `json:"error,omitempty"`
+}
+
+type BulkScanResponse struct {
+	Accepted   int          `json:"accepted"`
+	Duplicates int          `json:"duplicates"`
+	OutOfOrder int          `json:"out_of_order"`
+	Rejected   int          `json:"rejected"`
+	Results    []ScanResult `json:"results"`
+}
+
+// ScanTarget is what a scanned tracking number refers to.
+type ScanTarget struct {
+	DeliveryID string
+	ParcelID   string // set for parcel tracking numbers
+	Status     string // the delivery's status
+}
+
+// HubInventory lists the deliveries currently on site at a hub.
+type HubInventory struct {
+	Hub         *Hub        `json:"hub"`
+	Deliveries  []*Delivery `json:"deliveries"`
+	ParcelCount int         `json:"parcel_count"`
+}
+
+// CanScanAtHub reports whether a delivery with status can be handled at a
+// hub. Deliveries come back to a hub after a failed attempt or when a courier
+// returns them undelivered.
+func CanScanAtHub(status string) bool {
+	switch status {
+	case StatusPending, StatusPickedUp, StatusInTransit, StatusOutForDelivery, StatusFailedAttempt:
+		return true
+	}
+	return false
+}
+
+// ScanEventStatus is the delivery event recorded for a scan type.
+func ScanEventStatus(scanType string) string {
+	switch scanType {
+	case ScanSorted:
+		return EventSortedAtHub
+	case ScanDeparted:
+		return EventDepartedHub
+	}
+	return EventArrivedAtHub
+}
+
+func IsScanType(scanType string) bool {
+	switch scanType {
+	case ScanArrived, ScanSorted, ScanDeparted:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+)
+
+// CourierLocation is a single GPS ping from a courier's device.
+type CourierLocation struct {
+	CourierID  string    `json:"courier_id" db:"courier_id"`
+	Latitude   float64   `json:"latitude" db:"latitude"`
+	Longitude  float64   `json:"longitude" db:"longitude"`
+	Accuracy   float64   `json:"accuracy,omitempty" db:"accuracy"` // meters
+	Speed      float64   `json:"speed,omitempty" db:"speed"`       // meters per second
+	Heading    float64   `json:"heading,omitempty" db:"heading"`   // degrees from north
+	RecordedAt time.Time `json:"recorded_at" db:"recorded_at"`
+}
+
+type IngestLocationsRequest struct {
+	Pings []CourierLocation `json:"pings" binding:"required"`
+	Actor Actor             `json:"-"`
+}
+
+type RejectedPing struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+type IngestLocationsResponse struct {
+	Accepted int            `json:"accepted"`
+	Stored   int            `json:"stored"` // pings kept in the downsampled history
+	Rejected []RejectedPing `json:"rejected,omitempty"`
+}
+
+type DeliveryLocation struct {
+	DeliveryID string           `json:"delivery_id"`
+	CourierID  string           `json:"courier_id"`
+	Location   *CourierLocation `json:"location"`
+}
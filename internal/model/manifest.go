@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+)
+
+// Manifest statuses
+const (
+	ManifestOpen   = "OPEN"
+	ManifestClosed = "CLOSED" // signed and immutable; its deliveries cannot be reassigned
+)
+
+// Manifest is the end-of-day record of the deliveries a courier (or one of
+// their routes) handled on a date.
+type Manifest struct {
+	ID            string          `json:"id" db:"id"`
+	CourierID     string          `json:"courier_id" db:"courier_id"`
+	RouteID       string          `json:"route_id,omitempty" db:"route_id"`
+	Date          string          `json:"date" db:"manifest_date"` // YYYY-MM-DD
+	Status        string          `json:"status" db:"status"`
+	Items         []*ManifestItem `json:"items"`
+	DeliveryCount int             `json:"delivery_count" db:"delivery_count"`
+	ParcelCount   int             `json:"parcel_count" db:"parcel_count"`
+	WeightKg      float64         `json:"weight_kg" db:"weight_kg"`
+	CODDueCents   int64           `json:"cod_due_cents" db:"cod_due_cents"`
+	CODCurrency   string          `json:"cod_currency,omitempty" db:"cod_currency"`
+	Signature     string          `json:"signature,omitempty" db:"signature"`
+	Verified      bool            `json:"verified"` // signature matches the stored contents
+	CreatedBy     string          `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	ClosedBy      string          `json:"closed_by,omitempty" db:"closed_by"`
+	ClosedAt      *time.Time      `json:"closed_at,omitempty" db:"closed_at"`
+}
+
+// ManifestItem is one delivery on a manifest as it stood when the manifest
+// was built.
+type ManifestItem struct {
+	ManifestID     string  `json:"-" db:"manifest_id"`
+	Sequence       int     `json:"sequence" db:"sequence"`
+	DeliveryID     string  `json:"delivery_id" db:"delivery_id"`
+	TrackingNumber string  `json:"tracking_number" db:"tracking_number"`
+	OrderID        string  `json:"order_id" db:"order_id"`
+	Status         string  `json:"status" db:"status"`
+	City           string  `json:"city" db:"city"`
+	ZipCode        string  `json:"zip_code" db:"zip_code"`
+	Parcels        int     `json:"parcels" db:"parcels"`
+	WeightKg       float64 `json:"weight_kg" db:"weight_kg"`
+	CODDueCents    int64   `json:"cod_due_cents" db:"cod_due_cents"` // COD to collect for the item
+	CODCurrency    string  `json:"cod_currency,omitempty" db:"cod_currency"`
+}
+
+type CreateManifestRequest struct {
+	Actor     Actor  `json:"-"`
+	CourierID string `json:"courier_id"`
+	RouteID   string `json:"route_id,omitempty"` // manifest one route instead of the courier's whole day
+	Date      string `json:"date,omitempty"`     // defaults to today
+}
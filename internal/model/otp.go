@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+)
+
+// DeliveryOTP is the one-time PIN issued to the recipient of a delivery that
+// requires verification. Only a hash of the PIN is stored.
+type DeliveryOTP struct {
+	DeliveryID  string     `json:"delivery_id" db:"delivery_id"`
+	PINHash     string     `json:"-" db:"pin_hash"`
+	Attempts    int        `json:"attempts" db:"attempts"`
+	LockedUntil *time.Time `json:"locked_until,omitempty" db:"locked_until"`
+	ExpiresAt   time.Time  `json:"expires_at" db:"expires_at"`
+	VerifiedAt  *time.Time `json:"verified_at,omitempty" db:"verified_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+type VerifyOTPRequest struct {
+	DeliveryID string `json:"-"`
+	Actor      Actor  `json:"-"`
+	PIN        string `json:"pin" binding:"required"`
+}
+
+type VerifyOTPResponse struct {
+	Verified     bool       `json:"verified"`
+	AttemptsLeft int        `json:"attempts_left"`
+	LockedUntil  *time.Time `json:"locked_until,omitempty"`
+}
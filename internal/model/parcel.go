@@ -0,0 +1,102 @@
+package model
+
+import (
+	"time"
+)
+
+// Parcel is one box of a delivery. Parcels move through the same statuses as
+// deliveries, and the delivery's status is derived from them.
+type Parcel struct {
+	ID                 string    `json:"id" db:"id"`
+	DeliveryID         string    `json:"delivery_id" db:"delivery_id"`
+	TrackingNumber     string    `json:"tracking_number" db:"tracking_number"`
+	Status             string    `json:"status" db:"status"`
+	WeightKg           float64   `json:"weight_kg" db:"weight_kg"`
+	LengthCm           float64   `json:"length_cm" db:"length_cm"`
+	WidthCm            float64   `json:"width_cm" db:"width_cm"`
+	HeightCm           float64   `json:"height_cm" db:"height_cm"`
+	DeclaredValueCents int64     `json:"declared_value_cents" db:"declared_value_cents"`
+	Currency           string    `json:"currency,omitempty" db:"currency"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type UpdateParcelRequest struct {
+	DeliveryID  string      `json:"-"`
+	ParcelID    string      `json:"-"`
+	Actor       Actor       `json:"-"`
+	Status      string      `json:"status" binding:"required"`
+	Location    string      `json:"location"`
+	Description string      `json:"description"`
+	Latitude    *float64    `json:"latitude,omitempty"`
+	Longitude   *float64    `json:"longitude,omitempty"`
+	PIN         string      `json:"pin,omitempty"` // needed when the last parcel of a verified delivery is delivered
+	COD         *CODPayment `json:"cod,omitempty"` // needed when the last parcel of a COD delivery is delivered
+}
+
+// parcelProgress orders the statuses a parcel passes through on the way out.
+var parcelProgress = map[string]int{
+	StatusPending:            0,
+	StatusInTransit:          1,
+	StatusOutForDelivery:     2,
+	StatusFailedAttempt:      2,
+	StatusAvailableForPickup: 3,
+	StatusDelivered:          4,
+}
+
+// DeriveStatus computes a delivery's status from its parcels. A delivery is
+// DELIVERED once every parcel is, PARTIALLY_DELIVERED while only some are,
+// and otherwise follows its least advanced parcel.
+func DeriveStatus(parcels []*Parcel) string {
+	if len(parcels) == 0 {
+		return ""
+	}
+
+	delivered, returned := 0, 0
+	least := ""
+	for _, p := range parcels {
+		switch p.Status {
+		case StatusDelivered:
+			delivered++
+		case StatusReturnToSender:
+			returned++
+		}
+		if rank, ok := parcelProgress[p.Status]; ok {
+			if least == "" || rank < parcelProgress[least] {
+				least = p.Status
+			}
+		}
+	}
+
+	switch {
+	case delivered == len(parcels):
+		return StatusDelivered
+	case returned == len(parcels):
+		return StatusReturnToSender
+	case delivered > 0:
+		return StatusPartiallyDelivered
+	case least == "":
+		return parcels[0].Status
+	}
+	return least
+}
+
+// ParcelsBehind lists the parcel statuses a delivery-level move to status
+// should carry forward. Parcels are never moved backwards.
+func ParcelsBehind(status string) []string {
+	if status == StatusReturnToSender {
+		return []string{StatusPending, StatusInTransit, StatusOutForDelivery, StatusFailedAttempt, StatusAvailableForPickup}
+	}
+
+	rank, ok := parcelProgress[status]
+	if !ok {
+		return nil
+	}
+	var behind []string
+	for s, r := range parcelProgress {
+		if r < rank {
+			behind = append(behind, s)
+		}
+	}
+	return behind
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+)
+
+// Pickup point kinds
+const (
+	PickupLocker  = "LOCKER"
+	PickupCounter = "COUNTER"
+)
+
+// PickupPoint is a locker bank or staffed counter where recipients collect
+// parcels themselves.
+type PickupPoint struct {
+	ID        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Kind      string    `json:"kind" db:"kind"`
+	Address   Address   `json:"address"`
+	ZoneID    string    `json:"zone_id" db:"zone_id"`
+	Capacity  int       `json:"capacity" db:"capacity"`
+	HoldDays  int       `json:"hold_days" db:"hold_days"` // days a parcel is held before it is returned
+	Active    bool      `json:"active" db:"active"`
+	Occupied  int       `json:"occupied"` // deliveries currently routed to the point
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type RedirectRequest struct {
+	DeliveryID    string `json:"-"`
+	Actor         Actor  `json:"-"`
+	PickupPointID string `json:"pickup_point_id" binding:"required"`
+}
+
+type CollectRequest struct {
+	DeliveryID    string      `json:"-"`
+	Actor         Actor       `json:"-"`
+	PickupCode    string      `json:"pickup_code" binding:"required"`
+	RecipientName string      `json:"recipient_name"`
+	COD           *CODPayment `json:"cod,omitempty"` // payment taken at the counter for COD deliveries
+}
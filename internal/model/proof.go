@@ -0,0 +1,53 @@
+package model
+
+import (
+	"io"
+	"time"
+)
+
+// OTP verification results recorded with a proof of delivery
+const (
+	OTPNotRequired = "NOT_REQUIRED"
+	OTPVerified    = "VERIFIED"
+)
+
+// Proof attachment kinds
+const (
+	AttachmentSignature = "signature"
+	AttachmentPhoto     = "photo"
+)
+
+type ProofOfDelivery struct {
+	ID                   string    `json:"id" db:"id"`
+	DeliveryID           string    `json:"delivery_id" db:"delivery_id"`
+	RecipientName        string    `json:"recipient_name" db:"recipient_name"`
+	SignatureKey         string    `json:"-" db:"signature_key"`
+	SignatureContentType string    `json:"signature_content_type,omitempty" db:"signature_content_type"`
+	PhotoKey             string    `json:"-" db:"photo_key"`
+	PhotoContentType     string    `json:"photo_content_type,omitempty" db:"photo_content_type"`
+	Latitude             *float64  `json:"latitude,omitempty" db:"latitude"`
+	Longitude            *float64  `json:"longitude,omitempty" db:"longitude"`
+	OTPResult            string    `json:"otp_result" db:"otp_result"`
+	CapturedBy           string    `json:"captured_by" db:"captured_by"`
+	CapturedAt           time.Time `json:"captured_at" db:"captured_at"`
+	HasSignature         bool      `json:"has_signature"`
+	HasPhoto             bool      `json:"has_photo"`
+}
+
+// ProofAttachment is an uploaded image streamed into the blob store.
+type ProofAttachment struct {
+	ContentType string
+	Body        io.Reader
+}
+
+type SubmitProofRequest struct {
+	DeliveryID    string
+	Actor         Actor
+	RecipientName string
+	Latitude      *float64
+	Longitude     *float64
+	PIN           string      // required for deliveries that need verification
+	COD           *CODPayment // required for COD deliveries
+	Signature     *ProofAttachment
+	Photo         *ProofAttachment
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+)
+
+// Surcharge codes
+const (
+	SurchargeFuel       = "FUEL"
+	SurchargeRemoteArea = "REMOTE_AREA"
+)
+
+// QuoteRequest asks for the price of a shipment before it exists, e.g. at
+// checkout.
+type QuoteRequest struct {
+	Origin       Address    `json:"origin" binding:"required"`
+	Destination  Address    `json:"destination" binding:"required"`
+	Parcels      []*Parcel  `json:"parcels" binding:"required"`
+	ServiceLevel string     `json:"service_level,omitempty"` // quote every offered service level when empty
+	ScheduledFor *time.Time `json:"scheduled_for,omitempty"` // needed to quote SCHEDULED
+}
+
+type Surcharge struct {
+	Code        string `json:"code"`
+	AmountCents int64  `json:"amount_cents"`
+}
+
+// QuoteOption is the price and ETA of one service level.
+type QuoteOption struct {
+	ServiceLevel          string       `json:"service_level"`
+	RateZone              string       `json:"rate_zone"`
+	BillableWeightKg      float64      `json:"billable_weight_kg"` // greater of actual and dimensional weight, summed over parcels
+	BaseCents             int64        `json:"base_cents"`
+	Surcharges            []*Surcharge `json:"surcharges"`
+	TotalCents            int64        `json:"total_cents"`
+	Currency              string       `json:"currency"`
+	EstimatedDeliveryTime time.Time    `json:"estimated_delivery_time"`
+}
+
+type Quote struct {
+	RateCardVersion string         `json:"rate_card_version"`
+	OriginZone      string         `json:"origin_zone,omitempty"` // unset when the origin is outside all zones
+	DestinationZone string         `json:"destination_zone"`
+	Options         []*QuoteOption `json:"options"` // cheapest first
+	QuotedAt        time.Time      `json:"quoted_at"`
+}
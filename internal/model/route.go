@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+)
+
+type Route struct {
+	ID             string       `json:"id" db:"id"`
+	CourierID      string       `json:"courier_id" db:"courier_id"`
+	StartLatitude  float64      `json:"start_latitude" db:"start_latitude"`
+	StartLongitude float64      `json:"start_longitude" db:"start_longitude"`
+	StartAt        time.Time    `json:"start_at" db:"start_at"`
+	DistanceMeters float64      `json:"distance_meters" db:"distance_meters"`
+	Stops          []*RouteStop `json:"stops"`
+	Unassigned     []string     `json:"unassigned,omitempty"` // delivery IDs that did not fit
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+}
+
+type RouteStop struct {
+	RouteID        string    `json:"-" db:"route_id"`
+	DeliveryID     string    `json:"delivery_id" db:"delivery_id"`
+	Sequence       int       `json:"sequence" db:"sequence"`
+	PlannedArrival time.Time `json:"planned_arrival" db:"planned_arrival"`
+	DistanceMeters float64   `json:"distance_meters" db:"distance_meters"`
+}
+
+type PlanRouteRequest struct {
+	CourierID      string    `json:"-"`
+	Actor          Actor     `json:"-"`
+	StartLatitude  float64   `json:"start_latitude" binding:"required"`
+	StartLongitude float64   `json:"start_longitude" binding:"required"`
+	StartAt        time.Time `json:"start_at"`
+}
+
+type AssignCourierRequest struct {
+	DeliveryID string `json:"-"`
+	Actor      Actor  `json:"-"`
+	CourierID  string `json:"courier_id" binding:"required"`
+}
@@ -0,0 +1,9 @@
+package model
+
+// Service levels offered at delivery creation
+const (
+	ServiceLevelStandard  = "STANDARD"
+	ServiceLevelExpress   = "EXPRESS"
+	ServiceLevelSameDay   = "SAME_DAY"
+	ServiceLevelScheduled = "SCHEDULED"
+)
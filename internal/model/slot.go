@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+)
+
+// Slot reservation statuses
+const (
+	ReservationHeld      = "HELD"
+	ReservationConfirmed = "CONFIRMED"
+	ReservationReleased  = "RELEASED"
+)
+
+type TimeWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type DeliverySlot struct {
+	ID        string    `json:"id" db:"id"`
+	Zone      string    `json:"zone" db:"zone"`
+	StartsAt  time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt    time.Time `json:"ends_at" db:"ends_at"`
+	Capacity  int       `json:"capacity" db:"capacity"`
+	Available int       `json:"available"`
+}
+
+type SlotReservation struct {
+	ID         string        `json:"id" db:"id"`
+	SlotID     string        `json:"slot_id" db:"slot_id"`
+	DeliveryID string        `json:"delivery_id,omitempty" db:"delivery_id"`
+	Status     string        `json:"status" db:"status"`
+	HeldBy     string        `json:"-" db:"held_by"`
+	ExpiresAt  time.Time     `json:"expires_at" db:"expires_at"`
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+	Slot       *DeliverySlot `json:"slot,omitempty"`
+}
+
+type ListSlotsRequest struct {
+	Address Address   `json:"address" binding:"required"`
+	From    time.Time `json:"from"`
+	Days    int       `json:"days"`
+}
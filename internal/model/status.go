@@ -0,0 +1,79 @@
+package model
+
+// Delivery statuses
+const (
+	StatusPending            = "PENDING"
+	StatusPickedUp           = "PICKED_UP" // returns only: collected from the customer
+	StatusInTransit          = "IN_TRANSIT"
+	StatusOutForDelivery     = "OUT_FOR_DELIVERY"
+	StatusDelivered          = "DELIVERED"
+	StatusPartiallyDelivered = "PARTIALLY_DELIVERED"  // some parcels delivered
+	StatusAvailableForPickup = "AVAILABLE_FOR_PICKUP" // held at a pickup point
+	StatusFailedAttempt      = "FAILED_ATTEMPT"
+	StatusReturnToSender     = "RETURN_TO_SENDER"
+	StatusCancelled          = "CANCELLED"
+)
+
+// Informational event types. They are recorded in delivery_events but do not
+// change the delivery's status.
+const (
+	EventNearby         = "NEARBY"
+	EventArrivedAtHub   = "ARRIVED_AT_HUB"
+	EventSortedAtHub    = "SORTED_AT_HUB"
+	EventDepartedHub    = "DEPARTED_HUB"
+	EventAddressChanged = "ADDRESS_CHANGED"
+	EventCarrierUpdate  = "CARRIER_UPDATE" // carrier update without a matching status
+)
+
+// TerminalStatuses are statuses after which a delivery no longer moves.
+var TerminalStatuses = []string{StatusDelivered, StatusReturnToSender, StatusCancelled}
+
+// statusTransitions are the statuses UpdateDelivery may move a delivery to
+// from each status. Failed attempts, pickup holds and cancellations have
+// their own operations, PARTIALLY_DELIVERED is derived from parcels, and
+// terminal statuses have no way out.
+var statusTransitions = map[string][]string{
+	StatusPending:            {StatusPickedUp, StatusInTransit, StatusOutForDelivery, StatusReturnToSender},
+	StatusPickedUp:           {StatusInTransit, StatusDelivered},
+	StatusInTransit:          {StatusInTransit, StatusOutForDelivery, StatusReturnToSender},
+	StatusOutForDelivery:     {StatusInTransit, StatusDelivered, StatusReturnToSender},
+	StatusFailedAttempt:      {StatusInTransit, StatusOutForDelivery, StatusReturnToSender},
+	StatusPartiallyDelivered: {StatusDelivered, StatusReturnToSender},
+	StatusAvailableForPickup: {StatusDelivered, StatusReturnToSender},
+}
+
+// CanTransition reports whether UpdateDelivery may move a delivery of
+// deliveryType from one status to another. Informational events leave the
+// status alone and are always allowed. Returns are delivered at the
+// warehouse straight from transit.
+func CanTransition(deliveryType, from, to string) bool {
+	if IsInformational(to) {
+		return true
+	}
+	if deliveryType == DeliveryTypeReturn && from == StatusInTransit && to == StatusDelivered {
+		return true
+	}
+	for _, s := range statusTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+func IsInformational(status string) bool {
+	switch status {
+	case EventNearby, EventArrivedAtHub, EventSortedAtHub, EventDepartedHub, EventAddressChanged, EventCarrierUpdate:
+		return true
+	}
+	return false
+}
+
+func IsTerminal(status string) bool {
+	for _, s := range TerminalStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+package model
+
+// TrackingResponse is the public view of a delivery and its history. For a
+// return, Outbound is the original delivery; for an outbound delivery,
+// Returns are its return legs. Parcel is set when a parcel tracking number was
+// looked up.
+type TrackingResponse struct {
+	Delivery *Delivery         `json:"delivery"`
+	Parcel   *Parcel           `json:"parcel,omitempty"`
+	Events   []*DeliveryEvent  `json:"events"`
+	Attempts []DeliveryAttempt `json:"attempts,omitempty"`
+	Outbound *TrackingLeg      `json:"outbound,omitempty"`
+	Returns  []*TrackingLeg    `json:"returns,omitempty"`
+}
+
+type TrackingLeg struct {
+	Delivery *Delivery        `json:"delivery"`
+	Events   []*DeliveryEvent `json:"events"`
+}
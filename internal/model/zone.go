@@ -0,0 +1,29 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Zone is a serviceable area, defined by postal codes, postal code prefixes
+// and/or a GeoJSON Polygon or MultiPolygon boundary.
+type Zone struct {
+	ID             string          `json:"id" db:"id"`
+	Name           string          `json:"name" db:"name"`
+	Country        string          `json:"country" db:"country"`
+	PostalCodes    []string        `json:"postal_codes,omitempty" db:"postal_codes"`
+	PostalPrefixes []string        `json:"postal_prefixes,omitempty" db:"postal_prefixes"`
+	Boundary       json.RawMessage `json:"boundary,omitempty" db:"boundary"`
+	Active         bool            `json:"active" db:"active"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+type ServiceabilityRequest struct {
+	Address Address `json:"address" binding:"required"`
+}
+
+type ServiceabilityResponse struct {
+	Serviceable bool  `json:"serviceable"`
+	Zone        *Zone `json:"zone,omitempty"`
+}
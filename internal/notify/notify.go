@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Message kinds
+const (
+	KindDeliveryOTP = "DELIVERY_OTP"
+	KindPickupReady = "PICKUP_READY"
+)
+
+// Message is a customer notification. The notification service resolves the
+// recipient's contact details from the order.
+type Message struct {
+	Kind       string            `json:"kind"`
+	OrderID    string            `json:"order_id"`
+	DeliveryID string            `json:"delivery_id"`
+	Data       map[string]string `json:"data,omitempty"`
+}
+
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogNotifier writes messages to the log without sensitive data. It is used
+// when no notification service is configured.
+type LogNotifier struct{}
+
+func (LogNotifier) Send(ctx context.Context, msg Message) error {
+	log.Printf("notification %s for delivery %s (order %s)", msg.Kind, msg.DeliveryID, msg.OrderID)
+	return nil
+}
+
+// HTTPNotifier posts messages to the notification service.
+type HTTPNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPNotifier(baseURL string) *HTTPNotifier {
+	return &HTTPNotifier{
+		url:    baseURL + "/notifications",
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (n *HTTPNotifier) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notification service returned %s", resp.Status)
+	}
+	return nil
+}
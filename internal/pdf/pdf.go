@@ -0,0 +1,161 @@
+// Package pdf writes small PDF documents drawn with the standard Helvetica
+// fonts and filled rectangles, which is all labels and manifests need.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/qr"
+)
+
+// Fonts every document provides
+const (
+	FontRegular = "F1"
+	FontBold    = "F2"
+)
+
+// Document collects numbered objects and writes them with a cross-reference
+// table.
+type Document struct {
+	objects []string
+	pages   []string
+}
+
+func NewDocument() *Document {
+	d := &Document{}
+	d.add("<< /Type /Catalog /Pages 2 0 R >>")
+	d.add("") // page tree, filled in once the pages are known
+	d.add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	d.add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	return d
+}
+
+// AddPage appends a width x height point page drawn by c.
+func (d *Document) AddPage(width, height float64, c *Content) {
+	content := c.String()
+	stream := d.add(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	page := d.add(fmt.Sprintf(
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %g %g] /Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+		width, height, FontRegular, FontBold, stream,
+	))
+	d.pages = append(d.pages, fmt.Sprintf("%d 0 R", page))
+}
+
+// add appends an object and returns its object number.
+func (d *Document) add(obj string) int {
+	d.objects = append(d.objects, obj)
+	return len(d.objects)
+}
+
+func (d *Document) Write(w io.Writer) error {
+	d.objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(d.pages, " "), len(d.pages))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(d.objects))
+	for i, obj := range d.objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(d.objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(d.objects)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Content builds a page content stream. Coordinates are in points from the
+// bottom left corner of the page.
+type Content struct {
+	strings.Builder
+}
+
+func (c *Content) Text(font string, size, x, y float64, s string) {
+	fmt.Fprintf(c, "BT /%s %g Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escape(s))
+}
+
+func (c *Content) Rect(x, y, w, h float64) {
+	fmt.Fprintf(c, "%.2f %.2f %.2f %.2f re f\n", x, y, w, h)
+}
+
+// Code128 draws the barcode for data scaled to width, one rectangle per bar.
+func (c *Content) Code128(data string, x, y, width, height float64) error {
+	bc, err := code128.Encode(data)
+	if err != nil {
+		return fmt.Errorf("error encoding barcode: %w", err)
+	}
+	modules := bc.Bounds().Dx()
+	scale := width / float64(modules)
+
+	for m := 0; m < modules; {
+		if !dark(bc, m, 0) {
+			m++
+			continue
+		}
+		start := m
+		for m < modules && dark(bc, m, 0) {
+			m++
+		}
+		c.Rect(x+float64(start)*scale, y, float64(m-start)*scale, height)
+	}
+	return nil
+}
+
+// QR draws a QR code for data in a size x size square.
+func (c *Content) QR(data string, x, y, size float64) error {
+	code, err := qr.Encode(data, qr.M, qr.Auto)
+	if err != nil {
+		return fmt.Errorf("error encoding QR code: %w", err)
+	}
+	modules := code.Bounds().Dx()
+	scale := size / float64(modules)
+
+	for row := 0; row < modules; row++ {
+		for col := 0; col < modules; col++ {
+			if dark(code, col, row) {
+				// PDF y grows upwards, image rows grow downwards
+				c.Rect(x+float64(col)*scale, y+size-float64(row+1)*scale, scale, scale)
+			}
+		}
+	}
+	return nil
+}
+
+func dark(img image.Image, x, y int) bool {
+	b := img.Bounds()
+	return color.GrayModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.Gray).Y < 128
+}
+
+// escape escapes s for a literal string in the WinAnsi-encoded standard
+// fonts, replacing characters they cannot show.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20:
+			b.WriteByte(' ')
+		case r < 0x80:
+			b.WriteRune(r)
+		case r >= 0xA0 && r <= 0xFF:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package rate
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Book holds every version of the rate card.
+type Book struct {
+	cards []*Card // by EffectiveFrom, oldest first
+}
+
+// NewBook validates the cards and orders them by effective date. Two cards
+// cannot take effect on the same day.
+func NewBook(cards []*Card) (*Book, error) {
+	sorted := make([]*Card, 0, len(cards))
+	for _, c := range cards {
+		if err := c.Validate(); err != nil {
+			return nil, fmt.Errorf("rate card %q: %w", c.Version, err)
+		}
+		sorted = append(sorted, c)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].EffectiveFrom < sorted[j].EffectiveFrom
+	})
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].EffectiveFrom == sorted[i-1].EffectiveFrom {
+			return nil, fmt.Errorf("rate cards %q and %q both take effect on %s",
+				sorted[i-1].Version, sorted[i].Version, sorted[i].EffectiveFrom)
+		}
+	}
+	return &Book{cards: sorted}, nil
+}
+
+// At returns the card in effect on a YYYY-MM-DD date, or nil if none has
+// taken effect yet.
+func (b *Book) At(date string) *Card {
+	for i := len(b.cards) - 1; i >= 0; i-- {
+		if b.cards[i].EffectiveFrom <= date {
+			return b.cards[i]
+		}
+	}
+	return nil
+}
+
+func (b *Book) Len() int {
+	return len(b.cards)
+}
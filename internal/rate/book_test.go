@@ -0,0 +1,47 @@
+package rate
+
+import "testing"
+
+func cardFrom(version, effectiveFrom string) *Card {
+	c := testCard()
+	c.Version, c.EffectiveFrom = version, effectiveFrom
+	return c
+}
+
+func TestBookAt(t *testing.T) {
+	book, err := NewBook([]*Card{cardFrom("b", "2026-11-01"), cardFrom("a", "2026-06-01"), cardFrom("c", "2027-01-01")})
+	if err != nil {
+		t.Fatalf("NewBook: %v", err)
+	}
+
+	tests := []struct {
+		date string
+		want string // "" for no card
+	}{
+		{"2026-05-31", ""},
+		{"2026-06-01", "a"},
+		{"2026-10-31", "a"},
+		{"2026-11-01", "b"},
+		{"2030-01-01", "c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			got := ""
+			if c := book.At(tt.date); c != nil {
+				got = c.Version
+			}
+			if got != tt.want {
+				t.Errorf("At(%s) = %q, want %q", tt.date, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewBookRejects(t *testing.T) {
+	if _, err := NewBook([]*Card{cardFrom("a", "2026-11-01"), cardFrom("b", "2026-11-01")}); err == nil {
+		t.Error("two cards on the same day accepted")
+	}
+	if _, err := NewBook([]*Card{cardFrom("", "2026-11-01")}); err == nil {
+		t.Error("invalid card accepted")
+	}
+}
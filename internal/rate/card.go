@@ -0,0 +1,204 @@
+// Package rate prices shipments from versioned rate cards.
+package rate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// Wildcard matches any zone in a card's zone matrix, including an origin
+// outside all zones.
+const Wildcard = "*"
+
+var (
+	ErrNoRate     = errors.New("no rate for this service level and lane")
+	ErrOverweight = errors.New("parcel exceeds the maximum weight on the rate card")
+)
+
+// Break prices a parcel weighing up to MaxKg.
+type Break struct {
+	MaxKg float64 `yaml:"max_kg"`
+	Cents int64   `yaml:"cents"`
+}
+
+// Rate is the weight break table of a service level in a rate zone.
+type Rate struct {
+	ServiceLevel string  `yaml:"service_level"`
+	RateZone     string  `yaml:"rate_zone"`
+	Breaks       []Break `yaml:"breaks"`         // ascending by MaxKg
+	ExtraKgCents int64   `yaml:"extra_kg_cents"` // per started kg above the last break; 0 caps weight at the last break
+}
+
+// Card is one version of the price list. It takes effect at the start of
+// EffectiveFrom, in the service's timezone, and stays in effect until a card
+// with a later date does.
+type Card struct {
+	Version              string                       `yaml:"version"`
+	EffectiveFrom        string                       `yaml:"effective_from"` // YYYY-MM-DD
+	Currency             string                       `yaml:"currency"`
+	DimDivisor           float64                      `yaml:"dim_divisor"` // cm³ per kg; 0 disables dimensional weight
+	FuelSurchargePercent float64                      `yaml:"fuel_surcharge_percent"`
+	RemoteSurchargeCents int64                        `yaml:"remote_surcharge_cents"` // per shipment
+	RemoteZones          []string                     `yaml:"remote_zones"`
+	Zones                map[string]map[string]string `yaml:"zones"` // origin zone -> destination zone -> rate zone
+	Rates                []Rate                       `yaml:"rates"`
+}
+
+// Validate checks a card is complete and normalizes its currency.
+func (c *Card) Validate() error {
+	if c.Version == "" {
+		return errors.New("version is required")
+	}
+	if _, err := time.Parse("2006-01-02", c.EffectiveFrom); err != nil {
+		return errors.New("effective_from must be YYYY-MM-DD")
+	}
+	c.Currency = strings.ToUpper(c.Currency)
+	if len(c.Currency) != 3 {
+		return errors.New("currency is required")
+	}
+	if c.DimDivisor < 0 || c.FuelSurchargePercent < 0 || c.RemoteSurchargeCents < 0 {
+		return errors.New("dim_divisor and surcharges cannot be negative")
+	}
+	if len(c.Zones) == 0 {
+		return errors.New("zones are required")
+	}
+	if len(c.Rates) == 0 {
+		return errors.New("rates are required")
+	}
+
+	seen := make(map[string]bool)
+	for _, r := range c.Rates {
+		key := r.ServiceLevel + "|" + r.RateZone
+		if r.ServiceLevel == "" || r.RateZone == "" {
+			return errors.New("rates need a service_level and a rate_zone")
+		}
+		if seen[key] {
+			return fmt.Errorf("duplicate rate for %s in %s", r.ServiceLevel, r.RateZone)
+		}
+		seen[key] = true
+
+		if len(r.Breaks) == 0 {
+			return fmt.Errorf("rate for %s in %s has no weight breaks", r.ServiceLevel, r.RateZone)
+		}
+		if r.ExtraKgCents < 0 {
+			return fmt.Errorf("rate for %s in %s has a negative extra_kg_cents", r.ServiceLevel, r.RateZone)
+		}
+		for i, b := range r.Breaks {
+			if b.MaxKg <= 0 || b.Cents < 0 || (i > 0 && b.MaxKg <= r.Breaks[i-1].MaxKg) {
+				return fmt.Errorf("rate for %s in %s needs ascending positive weight breaks", r.ServiceLevel, r.RateZone)
+			}
+		}
+	}
+	return nil
+}
+
+// RateZone looks up the lane in the zone matrix. Exact zones win over the
+// wildcard, and the destination is matched before the origin is widened.
+func (c *Card) RateZone(origin, destination string) (string, bool) {
+	for _, o := range []string{origin, Wildcard} {
+		row, ok := c.Zones[o]
+		if !ok {
+			continue
+		}
+		for _, d := range []string{destination, Wildcard} {
+			if zone, ok := row[d]; ok {
+				return zone, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (c *Card) rate(serviceLevel, rateZone string) *Rate {
+	for i := range c.Rates {
+		if c.Rates[i].ServiceLevel == serviceLevel && c.Rates[i].RateZone == rateZone {
+			return &c.Rates[i]
+		}
+	}
+	return nil
+}
+
+// Price prices a shipment of parcels between two zones, by name. Each parcel
+// is charged at the greater of its actual and dimensional weight; the fuel
+// surcharge is a percentage of the base price and the remote area surcharge
+// is charged once per shipment.
+func (c *Card) Price(serviceLevel, origin, destination string, parcels []*model.Parcel) (*model.QuoteOption, error) {
+	rateZone, ok := c.RateZone(origin, destination)
+	if !ok {
+		return nil, ErrNoRate
+	}
+	rate := c.rate(serviceLevel, rateZone)
+	if rate == nil {
+		return nil, ErrNoRate
+	}
+
+	option := &model.QuoteOption{
+		ServiceLevel: serviceLevel,
+		RateZone:     rateZone,
+		Surcharges:   []*model.Surcharge{},
+		Currency:     c.Currency,
+	}
+	for _, p := range parcels {
+		weight := c.billableWeight(p)
+		cents, err := rate.price(weight)
+		if err != nil {
+			return nil, err
+		}
+		option.BillableWeightKg += weight
+		option.BaseCents += cents
+	}
+	option.BillableWeightKg = math.Round(option.BillableWeightKg*1000) / 1000
+
+	if c.FuelSurchargePercent > 0 {
+		option.Surcharges = append(option.Surcharges, &model.Surcharge{
+			Code:        model.SurchargeFuel,
+			AmountCents: int64(math.Round(float64(option.BaseCents) * c.FuelSurchargePercent / 100)),
+		})
+	}
+	if c.RemoteSurchargeCents > 0 && c.isRemote(destination) {
+		option.Surcharges = append(option.Surcharges, &model.Surcharge{
+			Code:        model.SurchargeRemoteArea,
+			AmountCents: c.RemoteSurchargeCents,
+		})
+	}
+
+	option.TotalCents = option.BaseCents
+	for _, s := range option.Surcharges {
+		option.TotalCents += s.AmountCents
+	}
+	return option, nil
+}
+
+func (c *Card) billableWeight(p *model.Parcel) float64 {
+	if c.DimDivisor == 0 {
+		return p.WeightKg
+	}
+	return math.Max(p.WeightKg, p.LengthCm*p.WidthCm*p.HeightCm/c.DimDivisor)
+}
+
+func (c *Card) isRemote(zone string) bool {
+	for _, z := range c.RemoteZones {
+		if z == zone {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rate) price(weight float64) (int64, error) {
+	for _, b := range r.Breaks {
+		if weight <= b.MaxKg {
+			return b.Cents, nil
+		}
+	}
+	if r.ExtraKgCents == 0 {
+		return 0, ErrOverweight
+	}
+	last := r.Breaks[len(r.Breaks)-1]
+	return last.Cents + int64(math.Ceil(weight-last.MaxKg))*r.ExtraKgCents, nil
+}
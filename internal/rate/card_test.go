@@ -0,0 +1,216 @@
+package rate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func testCard() *Card {
+	return &Card{
+		Version:              "2026-11",
+		EffectiveFrom:        "2026-11-01",
+		Currency:             "usd",
+		DimDivisor:           5000,
+		FuelSurchargePercent: 8.5,
+		RemoteSurchargeCents: 450,
+		RemoteZones:          []string{"Islands"},
+		Zones: map[string]map[string]string{
+			"Downtown": {"Downtown": "LOCAL", Wildcard: "REGIONAL"},
+			Wildcard:   {Wildcard: "NATIONAL", "Islands": "REMOTE"},
+		},
+		Rates: []Rate{
+			{ServiceLevel: "STANDARD", RateZone: "LOCAL", Breaks: []Break{{1, 499}, {5, 799}}, ExtraKgCents: 120},
+			{ServiceLevel: "STANDARD", RateZone: "REGIONAL", Breaks: []Break{{1, 899}}},
+			{ServiceLevel: "STANDARD", RateZone: "REMOTE", Breaks: []Break{{30, 1500}}},
+		},
+	}
+}
+
+func TestRatePrice(t *testing.T) {
+	local := testCard().Rates[0]
+	capped := testCard().Rates[1]
+
+	tests := []struct {
+		name    string
+		rate    Rate
+		weight  float64
+		want    int64
+		wantErr error
+	}{
+		{name: "first break", rate: local, weight: 0.4, want: 499},
+		{name: "break is inclusive", rate: local, weight: 1, want: 499},
+		{name: "next break", rate: local, weight: 1.01, want: 799},
+		{name: "last break", rate: local, weight: 5, want: 799},
+		{name: "started kilogram above", rate: local, weight: 5.2, want: 799 + 120},
+		{name: "several kilograms above", rate: local, weight: 8, want: 799 + 3*120},
+		{name: "capped at the last break", rate: capped, weight: 1.5, wantErr: ErrOverweight},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rate.price(tt.weight)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("price(%v) error = %v, want %v", tt.weight, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("price(%v) = %d, want %d", tt.weight, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBillableWeight(t *testing.T) {
+	tests := []struct {
+		name       string
+		dimDivisor float64
+		parcel     model.Parcel
+		want       float64
+	}{
+		{"actual weight", 5000, model.Parcel{WeightKg: 2, LengthCm: 10, WidthCm: 10, HeightCm: 10}, 2},
+		{"dimensional weight", 5000, model.Parcel{WeightKg: 2, LengthCm: 50, WidthCm: 40, HeightCm: 30}, 12},
+		{"no dimensions", 5000, model.Parcel{WeightKg: 0.5}, 0.5},
+		{"dimensional weight disabled", 0, model.Parcel{WeightKg: 2, LengthCm: 50, WidthCm: 40, HeightCm: 30}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Card{DimDivisor: tt.dimDivisor}
+			if got := c.billableWeight(&tt.parcel); got != tt.want {
+				t.Errorf("billableWeight = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCardRateZone(t *testing.T) {
+	c := testCard()
+	tests := []struct {
+		origin, destination string
+		want                string
+	}{
+		{"Downtown", "Downtown", "LOCAL"},
+		{"Downtown", "Uptown", "REGIONAL"},
+		{"Downtown", "Islands", "REGIONAL"}, // the destination is widened before the origin
+		{"Uptown", "Islands", "REMOTE"},
+		{"Uptown", "Downtown", "NATIONAL"},
+		{"", "Downtown", "NATIONAL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.origin+"->"+tt.destination, func(t *testing.T) {
+			got, ok := c.RateZone(tt.origin, tt.destination)
+			if !ok || got != tt.want {
+				t.Errorf("RateZone(%q, %q) = %q, %v, want %q", tt.origin, tt.destination, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestCardPrice(t *testing.T) {
+	c := testCard()
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	light := &model.Parcel{WeightKg: 0.8}
+	bulky := &model.Parcel{WeightKg: 1, LengthCm: 50, WidthCm: 40, HeightCm: 30} // 12 kg dimensional
+
+	tests := []struct {
+		name        string
+		level       string
+		origin      string
+		destination string
+		parcels     []*model.Parcel
+		base        int64
+		weight      float64
+		surcharges  map[string]int64
+		wantErr     error
+	}{
+		{
+			name: "one parcel", level: "STANDARD", origin: "Downtown", destination: "Downtown",
+			parcels: []*model.Parcel{light}, base: 499, weight: 0.8,
+			surcharges: map[string]int64{model.SurchargeFuel: 42},
+		},
+		{
+			name: "parcels priced separately", level: "STANDARD", origin: "Downtown", destination: "Downtown",
+			parcels: []*model.Parcel{light, bulky}, base: 499 + 799 + 7*120, weight: 12.8,
+			surcharges: map[string]int64{model.SurchargeFuel: 182},
+		},
+		{
+			name: "remote destination", level: "STANDARD", origin: "Uptown", destination: "Islands",
+			parcels: []*model.Parcel{light}, base: 1500, weight: 0.8,
+			surcharges: map[string]int64{model.SurchargeFuel: 128, model.SurchargeRemoteArea: 450},
+		},
+		{
+			name: "overweight", level: "STANDARD", origin: "Downtown", destination: "Uptown",
+			parcels: []*model.Parcel{bulky}, wantErr: ErrOverweight,
+		},
+		{
+			name: "service level not offered", level: "EXPRESS", origin: "Downtown", destination: "Downtown",
+			parcels: []*model.Parcel{light}, wantErr: ErrNoRate,
+		},
+		{
+			name: "lane without a rate", level: "STANDARD", origin: "Uptown", destination: "Downtown",
+			parcels: []*model.Parcel{light}, wantErr: ErrNoRate,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.Price(tt.level, tt.origin, tt.destination, tt.parcels)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Price error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.BaseCents != tt.base || got.BillableWeightKg != tt.weight || got.Currency != "USD" {
+				t.Errorf("Price = base %d, weight %v, currency %s, want base %d, weight %v, currency USD",
+					got.BaseCents, got.BillableWeightKg, got.Currency, tt.base, tt.weight)
+			}
+
+			total := got.BaseCents
+			surcharges := make(map[string]int64)
+			for _, s := range got.Surcharges {
+				surcharges[s.Code] = s.AmountCents
+				total += s.AmountCents
+			}
+			if len(surcharges) != len(tt.surcharges) {
+				t.Errorf("surcharges = %v, want %v", surcharges, tt.surcharges)
+			}
+			for code, want := range tt.surcharges {
+				if surcharges[code] != want {
+					t.Errorf("%s surcharge = %d, want %d", code, surcharges[code], want)
+				}
+			}
+			if got.TotalCents != total {
+				t.Errorf("TotalCents = %d, want %d", got.TotalCents, total)
+			}
+		})
+	}
+}
+
+func TestCardValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Card)
+	}{
+		{"no version", func(c *Card) { c.Version = "" }},
+		{"bad date", func(c *Card) { c.EffectiveFrom = "1 Nov 2026" }},
+		{"no currency", func(c *Card) { c.Currency = "" }},
+		{"negative surcharge", func(c *Card) { c.RemoteSurchargeCents = -1 }},
+		{"no zones", func(c *Card) { c.Zones = nil }},
+		{"no rates", func(c *Card) { c.Rates = nil }},
+		{"duplicate rate", func(c *Card) { c.Rates = append(c.Rates, c.Rates[0]) }},
+		{"no breaks", func(c *Card) { c.Rates[0].Breaks = nil }},
+		{"descending breaks", func(c *Card) { c.Rates[0].Breaks = []Break{{5, 799}, {1, 499}} }},
+		{"zero break", func(c *Card) { c.Rates[0].Breaks = []Break{{0, 499}} }},
+		{"negative extra kg", func(c *Card) { c.Rates[0].ExtraKgCents = -5 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := testCard()
+			tt.modify(c)
+			if err := c.Validate(); err == nil {
+				t.Error("Validate accepted an invalid card")
+			}
+		})
+	}
+}
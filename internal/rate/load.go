@@ -0,0 +1,186 @@
+package rate
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadPath loads a rate card file, or every .yaml, .yml and .csv card in a
+// directory, into a book.
+func LoadPath(path string) (*Book, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		files = files[:0]
+		for _, entry := range entries {
+			if !entry.IsDir() && isCardFile(entry.Name()) {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	var cards []*Card
+	for _, file := range files {
+		card, err := LoadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return NewBook(cards)
+}
+
+// LoadFile loads one rate card, as YAML or CSV depending on its extension.
+func LoadFile(path string) (*Card, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var card *Card
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		card, err = ParseCSV(f)
+	} else {
+		card, err = ParseYAML(f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading %s: %w", path, err)
+	}
+	return card, nil
+}
+
+func isCardFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".csv":
+		return true
+	}
+	return false
+}
+
+// ParseYAML reads a card laid out like Card's yaml tags:
+//
+//	version: 2026-11
+//	effective_from: 2026-11-01
+//	currency: USD
+//	dim_divisor: 5000
+//	fuel_surcharge_percent: 8.5
+//	remote_surcharge_cents: 450
+//	remote_zones: [Islands]
+//	zones:
+//	  Downtown: {Downtown: LOCAL, "*": REGIONAL}
+//	rates:
+//	  - service_level: STANDARD
+//	    rate_zone: LOCAL
+//	    breaks: [{max_kg: 1, cents: 499}, {max_kg: 5, cents: 799}]
+//	    extra_kg_cents: 120
+func ParseYAML(r io.Reader) (*Card, error) {
+	var card Card
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&card); err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// csvRowFields is the number of fields in each kind of CSV row.
+var csvRowFields = map[string]int{
+	"card": 4, "dim_divisor": 2, "fuel_surcharge_percent": 2, "remote_surcharge_cents": 2,
+	"remote_zone": 2, "zone": 4, "break": 5, "extra_kg": 4,
+}
+
+// ParseCSV reads a card from rows keyed by their first column, so that it
+// can be kept in a spreadsheet. Lines starting with # are comments.
+//
+//	card,<version>,<effective_from>,<currency>
+//	dim_divisor,<cm³ per kg>
+//	fuel_surcharge_percent,<percent>
+//	remote_surcharge_cents,<cents>
+//	remote_zone,<zone>
+//	zone,<origin zone>,<destination zone>,<rate zone>
+//	break,<service level>,<rate zone>,<max kg>,<cents>
+//	extra_kg,<service level>,<rate zone>,<cents>
+func ParseCSV(r io.Reader) (*Card, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	card := &Card{Zones: make(map[string]map[string]string)}
+	rates := make(map[string]int) // index into card.Rates by service level and rate zone
+	rateFor := func(serviceLevel, rateZone string) *Rate {
+		key := serviceLevel + "|" + rateZone
+		if i, ok := rates[key]; ok {
+			return &card.Rates[i]
+		}
+		rates[key] = len(card.Rates)
+		card.Rates = append(card.Rates, Rate{ServiceLevel: serviceLevel, RateZone: rateZone})
+		return &card.Rates[len(card.Rates)-1]
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return card, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		want, ok := csvRowFields[record[0]]
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown row %q", line, record[0])
+		}
+		if len(record) != want {
+			return nil, fmt.Errorf("line %d: %s needs %d fields", line, record[0], want)
+		}
+
+		var parseErr error
+		switch record[0] {
+		case "card":
+			card.Version, card.EffectiveFrom, card.Currency = record[1], record[2], record[3]
+		case "dim_divisor":
+			card.DimDivisor, parseErr = strconv.ParseFloat(record[1], 64)
+		case "fuel_surcharge_percent":
+			card.FuelSurchargePercent, parseErr = strconv.ParseFloat(record[1], 64)
+		case "remote_surcharge_cents":
+			card.RemoteSurchargeCents, parseErr = strconv.ParseInt(record[1], 10, 64)
+		case "remote_zone":
+			card.RemoteZones = append(card.RemoteZones, record[1])
+		case "zone":
+			if card.Zones[record[1]] == nil {
+				card.Zones[record[1]] = make(map[string]string)
+			}
+			card.Zones[record[1]][record[2]] = record[3]
+		case "break":
+			var b Break
+			if b.MaxKg, parseErr = strconv.ParseFloat(record[3], 64); parseErr == nil {
+				b.Cents, parseErr = strconv.ParseInt(record[4], 10, 64)
+			}
+			rate := rateFor(record[1], record[2])
+			rate.Breaks = append(rate.Breaks, b)
+		case "extra_kg":
+			rateFor(record[1], record[2]).ExtraKgCents, parseErr = strconv.ParseInt(record[3], 10, 64)
+		}
+		if parseErr != nil {
+			return nil, fmt.Errorf("line %d: invalid %s", line, record[0])
+		}
+	}
+}
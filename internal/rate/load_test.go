@@ -0,0 +1,75 @@
+package rate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCSVMatchesYAML(t *testing.T) {
+	const yamlCard = `
+version: 2026-11
+effective_from: 2026-11-01
+currency: usd
+dim_divisor: 5000
+fuel_surcharge_percent: 8.5
+remote_surcharge_cents: 450
+remote_zones: [Islands]
+zones:
+  Downtown: {Downtown: LOCAL, "*": REGIONAL}
+rates:
+  - service_level: STANDARD
+    rate_zone: LOCAL
+    breaks: [{max_kg: 1, cents: 499}, {max_kg: 5, cents: 799}]
+    extra_kg_cents: 120
+`
+	const csvCard = `# Standard rates
+card,2026-11,2026-11-01,usd
+dim_divisor,5000
+fuel_surcharge_percent,8.5
+remote_surcharge_cents,450
+remote_zone,Islands
+zone,Downtown,Downtown,LOCAL
+zone,Downtown,*,REGIONAL
+break,STANDARD,LOCAL,1,499
+break,STANDARD,LOCAL,5,799
+extra_kg,STANDARD,LOCAL,120
+`
+	fromYAML, err := ParseYAML(strings.NewReader(yamlCard))
+	if err != nil {
+		t.Fatalf("ParseYAML: %v", err)
+	}
+	fromCSV, err := ParseCSV(strings.NewReader(csvCard))
+	if err != nil {
+		t.Fatalf("ParseCSV: %v", err)
+	}
+	for _, c := range []*Card{fromYAML, fromCSV} {
+		if err := c.Validate(); err != nil {
+			t.Fatalf("Validate: %v", err)
+		}
+	}
+	if !reflect.DeepEqual(fromYAML, fromCSV) {
+		t.Errorf("CSV card %+v differs from YAML card %+v", fromCSV, fromYAML)
+	}
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"unknown row", "surcharge,5\n"},
+		{"wrong field count", "break,STANDARD,LOCAL,1\n"},
+		{"bad number", "break,STANDARD,LOCAL,one,499\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseCSV(strings.NewReader(tt.in)); err == nil {
+				t.Error("ParseCSV accepted invalid input")
+			}
+		})
+	}
+	if _, err := ParseYAML(strings.NewReader("version: x\nbogus: 1\n")); err == nil {
+		t.Error("ParseYAML accepted an unknown field")
+	}
+}
@@ -3,14 +3,22 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	_ "github.com/lib/pq" // side-effect import: registers "postgres" driver for database/sql
+	"github.com/lib/pq" // also registers the "postgres" driver for database/sql
 	"github.com/bharathbbg/delivery-service/internal/config"
 	"github.com/bharathbbg/delivery-service/internal/model"
 	"github.com/google/uuid"
 	"time"
 )
 
+// ErrTrackingNumberTaken means another delivery already has the tracking
+// number; the caller should draw a new one and try again.
+var ErrTrackingNumberTaken = errors.New("tracking number is already in use")
+
+// ErrReturnExists means the delivery being returned already has a return.
+var ErrReturnExists = errors.New("a return already exists for this delivery")
+
 type PostgresRepository struct {
 	db *sql.DB
 }
@@ -35,98 +43,152 @@ func (r *PostgresRepository) Close() error {
 	return r.db.Close()
 }
 
+// CreateDelivery stores a new delivery under the tracking number already set
+// on it.
 func (r *PostgresRepository) CreateDelivery(ctx context.Context, delivery *model.Delivery) (*model.Delivery, error) {
-	// Generate tracking number and other necessary fields
+	// Generate ID and other necessary fields
 	delivery.ID = uuid.New().String()
-	delivery.TrackingNumber = fmt.Sprintf("TRK-%s", uuid.New().String()[:8])
+	if delivery.Type == "" {
+		delivery.Type = model.DeliveryTypeOutbound
+	}
 	delivery.Status = "PENDING"
 	delivery.CreatedAt = time.Now()
 	delivery.UpdatedAt = time.Now()
-	delivery.EstimatedDeliveryTime = time.Now().Add(72 * time.Hour) // Default: 3 days from now
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Make sure the pickup point has room before routing to it
+	if delivery.PickupPointID != "" {
+		if err := claimPickupSpace(ctx, tx, delivery.PickupPointID); err != nil {
+			return nil, err
+		}
+	}
 
 	// SQL for inserting delivery
 	query := `
 		INSERT INTO deliveries (
 			id, order_id, status, tracking_number, courier_id, 
-			estimated_delivery_time, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
+			estimated_delivery_time, created_at, updated_at,
+			service_level, scheduled_for, slot_reservation_id, zone_id,
+			requires_verification, delivery_type, parent_delivery_id, return_reason, return_to,
+			pickup_point_id, current_hub_id, cod_amount_cents, cod_currency, carrier, carrier_tracking_number
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23) 
 		RETURNING id`
 
 	// Execute the query
-	err := r.db.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		delivery.ID, delivery.OrderID, delivery.Status, delivery.TrackingNumber,
 		delivery.CourierID, delivery.EstimatedDeliveryTime, delivery.CreatedAt, delivery.UpdatedAt,
+		delivery.ServiceLevel, delivery.ScheduledFor, nullString(delivery.SlotReservationID),
+		nullString(delivery.ZoneID), delivery.RequiresVerification,
+		delivery.Type, nullString(delivery.ParentDeliveryID), nullString(delivery.ReturnReason),
+		nullString(delivery.ReturnTo), nullString(delivery.PickupPointID), nullString(delivery.CurrentHubID),
+		delivery.CODAmountCents, nullString(delivery.CODCurrency),
+		nullString(delivery.Carrier), nullString(delivery.CarrierTrackingNumber),
 	).Scan(&delivery.ID)
 
 	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "deliveries_tracking_number_key" {
+			return nil, ErrTrackingNumberTaken
+		}
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "delivery_return_parent_idx" {
+			return nil, ErrReturnExists
+		}
 		return nil, fmt.Errorf("error creating delivery: %w", err)
 	}
 
 	// Insert shipping address in a separate table
 	addressQuery := `
 		INSERT INTO delivery_addresses (
-			delivery_id, street, city, state, country, zip_code
-		) VALUES ($1, $2, $3, $4, $5, $6)`
+			delivery_id, street, city, state, country, zip_code, latitude, longitude
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
 	
-	_, err = r.db.ExecContext(
+	_, err = tx.ExecContext(
 		ctx,
 		addressQuery,
 		delivery.ID, delivery.ShippingAddress.Street, delivery.ShippingAddress.City,
 		delivery.ShippingAddress.State, delivery.ShippingAddress.Country, delivery.ShippingAddress.ZipCode,
+		delivery.ShippingAddress.Latitude, delivery.ShippingAddress.Longitude,
 	)
 
 	if err != nil {
 		return nil, fmt.Errorf("error creating delivery address: %w", err)
 	}
 
-	// Create initial delivery event
+	// Insert the delivery's parcels
+	for i, parcel := range delivery.Parcels {
+		parcel.DeliveryID = delivery.ID
+		if err := insertParcel(ctx, tx, parcel, delivery.TrackingNumber, i+1); err != nil {
+			return nil, err
+		}
+	}
+
+	// Plan the delivery's route through the hub network
+	if err := insertTransitLegs(ctx, tx, delivery.ID, delivery.Legs); err != nil {
+		return nil, err
+	}
+
+	// Create initial delivery event at the hub the delivery starts from
 	eventQuery := `
 		INSERT INTO delivery_events (
-			id, delivery_id, status, location, description, timestamp
-		) VALUES ($1, $2, $3, $4, $5, $6)`
+			id, delivery_id, status, location, description, hub_id, timestamp
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)`
 
 	eventID := uuid.New().String()
-	description := "Delivery created and pending processing"
+	location, description := "Origin hub", "Delivery created and pending processing"
+	if delivery.Type == model.DeliveryTypeReturn {
+		location, description = "Customer address", "Return authorized and awaiting pickup"
+	}
+	if delivery.CurrentHubID != "" {
+		if err := tx.QueryRowContext(ctx, `SELECT name FROM hubs WHERE id = $1`, delivery.CurrentHubID).Scan(&location); err != nil {
+			return nil, fmt.Errorf("error loading origin hub: %w", err)
+		}
+	}
 	
-	_, err = r.db.ExecContext(
+	_, err = tx.ExecContext(
 		ctx,
 		eventQuery,
 		eventID, delivery.ID, delivery.Status, 
-		"Warehouse", description, time.Now(),
+		location, description, nullString(delivery.CurrentHubID), time.Now(),
 	)
 
 	if err != nil {
 		return nil, fmt.Errorf("error creating delivery event: %w", err)
 	}
 
+	// Record the initial estimate as the first ETA revision
+	err = insertETARevision(ctx, tx, delivery.ID, delivery.EstimatedDeliveryTime, delivery.Status, delivery.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bind the reserved time slot to the delivery
+	if delivery.SlotReservationID != "" {
+		if err := confirmSlotReservation(ctx, tx, delivery.SlotReservationID, delivery.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return delivery, nil
 }
 
 func (r *PostgresRepository) GetDelivery(ctx context.Context, id string) (*model.Delivery, error) {
-	query := `
-		SELECT 
-			d.id, d.order_id, d.status, d.tracking_number, d.courier_id,
-			d.estimated_delivery_time, d.actual_delivery_time, d.created_at, d.updated_at,
-			a.street, a.city, a.state, a.country, a.zip_code
-		FROM 
-			deliveries d
-		JOIN 
-			delivery_addresses a ON d.id = a.delivery_id
+	query := deliverySelect + `
 		WHERE 
 			d.id = $1`
 
-	var delivery model.Delivery
-	var street, city, state, country, zipCode sql.NullString
-	var actualDeliveryTime sql.NullTime
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&delivery.ID, &delivery.OrderID, &delivery.Status, &delivery.TrackingNumber, &delivery.CourierID,
-		&delivery.EstimatedDeliveryTime, &actualDeliveryTime, &delivery.CreatedAt, &delivery.UpdatedAt,
-		&street, &city, &state, &country, &zipCode,
-	)
-
+	delivery, err := scanDelivery(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // No delivery found
@@ -134,40 +196,59 @@ func (r *PostgresRepository) GetDelivery(ctx context.Context, id string) (*model
 		return nil, err
 	}
 
-	// Handle nullable fields
-	if actualDeliveryTime.Valid {
-		actualTime := actualDeliveryTime.Time
-		delivery.ActualDeliveryTime = &actualTime
+	delivery.Parcels, err = r.ListParcels(ctx, delivery.ID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Set address fields
-	delivery.ShippingAddress = model.Address{
-		Street:  street.String,
-		City:    city.String,
-		State:   state.String,
-		Country: country.String,
-		ZipCode: zipCode.String,
+	delivery.Legs, err = r.ListTransitLegs(ctx, delivery.ID)
+	if err != nil {
+		return nil, err
 	}
 
-	return &delivery, nil
+	return delivery, nil
 }
 
-func (r *PostgresRepository) UpdateDelivery(ctx context.Context, req *model.UpdateDeliveryRequest) (*model.Delivery, error) {
+// UpdateDelivery moves a delivery to req.Status and records the event in one
+// transaction. It returns nil if the delivery does not exist.
+//
+// check, if set, is called with the delivery once it is locked and before
+// anything is written; an error from it leaves the delivery as it was.
+func (r *PostgresRepository) UpdateDelivery(ctx context.Context, req *model.UpdateDeliveryRequest, check func(current *model.Delivery) error) (*model.Delivery, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	// Update delivery status
+	var status string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM deliveries WHERE id = $1 FOR UPDATE`, req.ID).Scan(&status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No delivery found
+		}
+		return nil, err
+	}
+	if check != nil {
+		// The row is locked, so what is read here holds until commit
+		current, err := r.GetDelivery(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := check(current); err != nil {
+			return nil, err
+		}
+	}
+
+	// Update delivery status; informational events leave it unchanged
 	query := `
 		UPDATE deliveries 
-		SET status = $2, updated_at = $3 
+		SET status = CASE WHEN $4 THEN status ELSE $2 END, updated_at = $3 
 		WHERE id = $1`
 
 	now := time.Now()
 	
-	_, err = tx.ExecContext(ctx, query, req.ID, req.Status, now)
+	_, err = tx.ExecContext(ctx, query, req.ID, req.Status, now, model.IsInformational(req.Status))
 	if err != nil {
 		return nil, err
 	}
@@ -176,20 +257,31 @@ func (r *PostgresRepository) UpdateDelivery(ctx context.Context, req *model.Upda
 	eventID := uuid.New().String()
 	eventQuery := `
 		INSERT INTO delivery_events (
-			id, delivery_id, status, location, description, timestamp
-		) VALUES ($1, $2, $3, $4, $5, $6)`
+			id, delivery_id, status, location, description, latitude, longitude, timestamp
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
 	
 	_, err = tx.ExecContext(
 		ctx,
 		eventQuery,
-		eventID, req.ID, req.Status, req.Location, req.Description, now,
+		eventID, req.ID, req.Status, req.Location, req.Description, req.Latitude, req.Longitude, now,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	// Carry the move forward to parcels that are behind it
+	if behind := model.ParcelsBehind(req.Status); len(behind) > 0 {
+		_, err = tx.ExecContext(ctx,
+			`UPDATE parcels SET status = $2, updated_at = $3 WHERE delivery_id = $1 AND status = ANY($4)`,
+			req.ID, req.Status, now, pq.Array(behind),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// If delivery is completed, update actual delivery time
-	if req.Status == "DELIVERED" {
+	if req.Status == model.StatusDelivered {
 		completedQuery := `
 			UPDATE deliveries 
 			SET actual_delivery_time = $2 
@@ -199,6 +291,21 @@ func (r *PostgresRepository) UpdateDelivery(ctx context.Context, req *model.Upda
 		if err != nil {
 			return nil, err
 		}
+
+		// Record the cash on delivery payment taken at the door
+		if req.Collection != nil {
+			req.Collection.CollectedAt = now
+			if err := insertCODCollection(ctx, tx, req.Collection); err != nil {
+				return nil, err
+			}
+		}
+
+		// Record the proof captured at the door
+		if req.Proof != nil {
+			if err := insertProof(ctx, tx, req.Proof); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if err = tx.Commit(); err != nil {
@@ -228,15 +335,7 @@ func (r *PostgresRepository) ListDeliveries(ctx context.Context, orderID string,
 	offset := (page - 1) * pageSize
 	
 	// Query for paginated results
-	query := `
-		SELECT 
-			d.id, d.order_id, d.status, d.tracking_number, d.courier_id,
-			d.estimated_delivery_time, d.actual_delivery_time, d.created_at, d.updated_at,
-			a.street, a.city, a.state, a.country, a.zip_code
-		FROM 
-			deliveries d
-		JOIN 
-			delivery_addresses a ON d.id = a.delivery_id
+	query := deliverySelect + `
 		WHERE 
 			($1 = '' OR d.order_id = $1)
 		ORDER BY 
@@ -252,69 +351,86 @@ func (r *PostgresRepository) ListDeliveries(ctx context.Context, orderID string,
 	var deliveries []*model.Delivery
 
 	for rows.Next() {
-		var delivery model.Delivery
-		var street, city, state, country, zipCode sql.NullString
-		var actualDeliveryTime sql.NullTime
-
-		err := rows.Scan(
-			&delivery.ID, &delivery.OrderID, &delivery.Status, &delivery.TrackingNumber, &delivery.CourierID,
-			&delivery.EstimatedDeliveryTime, &actualDeliveryTime, &delivery.CreatedAt, &delivery.UpdatedAt,
-			&street, &city, &state, &country, &zipCode,
-		)
-
+		delivery, err := scanDelivery(rows)
 		if err != nil {
 			return nil, 0, err
 		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+// ListCourierDeliveries returns the deliveries assigned to a courier that have
+// not reached a terminal status.
+func (r *PostgresRepository) ListCourierDeliveries(ctx context.Context, courierID string) ([]*model.Delivery, error) {
+	query := deliverySelect + `
+		WHERE 
+			d.courier_id = $1 AND d.status <> ALL($2)
+		ORDER BY 
+			d.created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, courierID, pq.Array(model.TerminalStatuses))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-		// Handle nullable fields
-		if actualDeliveryTime.Valid {
-			actualTime := actualDeliveryTime.Time
-			delivery.ActualDeliveryTime = &actualTime
+	var deliveries []*model.Delivery
+
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
 		}
+		deliveries = append(deliveries, delivery)
+	}
 
-		// Set address fields
-		delivery.ShippingAddress = model.Address{
-			Street:  street.String,
-			City:    city.String,
-			State:   state.String,
-			Country: country.String,
-			ZipCode: zipCode.String,
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// GetDeliveryByTracking looks a delivery up by its own tracking number.
+func (r *PostgresRepository) GetDeliveryByTracking(ctx context.Context, trackingNumber string) (*model.Delivery, error) {
+	query := deliverySelect + `
+		WHERE 
+			d.tracking_number = $1`
+
+	delivery, err := scanDelivery(r.db.QueryRowContext(ctx, query, trackingNumber))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No delivery found
 		}
+		return nil, err
+	}
 
-		deliveries = append(deliveries, &delivery)
+	delivery.Parcels, err = r.ListParcels(ctx, delivery.ID)
+	if err != nil {
+		return nil, err
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, 0, err
+	delivery.Legs, err = r.ListTransitLegs(ctx, delivery.ID)
+	if err != nil {
+		return nil, err
 	}
 
-	return deliveries, total, nil
+	return delivery, nil
 }
 
 func (r *PostgresRepository) TrackDelivery(ctx context.Context, trackingNumber string) (*model.Delivery, []*model.DeliveryEvent, error) {
 	// First get the delivery
-	query := `
-		SELECT 
-			d.id, d.order_id, d.status, d.tracking_number, d.courier_id,
-			d.estimated_delivery_time, d.actual_delivery_time, d.created_at, d.updated_at,
-			a.street, a.city, a.state, a.country, a.zip_code
-		FROM 
-			deliveries d
-		JOIN 
-			delivery_addresses a ON d.id = a.delivery_id
+	query := deliverySelect + `
 		WHERE 
 			d.tracking_number = $1`
 
-	var delivery model.Delivery
-	var street, city, state, country, zipCode sql.NullString
-	var actualDeliveryTime sql.NullTime
-
-	err := r.db.QueryRowContext(ctx, query, trackingNumber).Scan(
-		&delivery.ID, &delivery.OrderID, &delivery.Status, &delivery.TrackingNumber, &delivery.CourierID,
-		&delivery.EstimatedDeliveryTime, &actualDeliveryTime, &delivery.CreatedAt, &delivery.UpdatedAt,
-		&street, &city, &state, &country, &zipCode,
-	)
-
+	delivery, err := scanDelivery(r.db.QueryRowContext(ctx, query, trackingNumber))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil, nil // No delivery found
@@ -322,25 +438,29 @@ func (r *PostgresRepository) TrackDelivery(ctx context.Context, trackingNumber s
 		return nil, nil, err
 	}
 
-	// Handle nullable fields
-	if actualDeliveryTime.Valid {
-		actualTime := actualDeliveryTime.Time
-		delivery.ActualDeliveryTime = &actualTime
+	delivery.Parcels, err = r.ListParcels(ctx, delivery.ID)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Set address fields
-	delivery.ShippingAddress = model.Address{
-		Street:  street.String,
-		City:    city.String,
-		State:   state.String,
-		Country: country.String,
-		ZipCode: zipCode.String,
+	delivery.Legs, err = r.ListTransitLegs(ctx, delivery.ID)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Now get the delivery events
+	events, err := r.ListDeliveryEvents(ctx, delivery.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return delivery, events, nil
+}
+
+func (r *PostgresRepository) ListDeliveryEvents(ctx context.Context, deliveryID string) ([]*model.DeliveryEvent, error) {
 	eventsQuery := `
 		SELECT 
-			id, delivery_id, status, location, description, timestamp
+			id, delivery_id, parcel_id, status, location, description, latitude, longitude, reason_code, hub_id, carrier, timestamp
 		FROM 
 			delivery_events
 		WHERE 
@@ -348,9 +468,9 @@ func (r *PostgresRepository) TrackDelivery(ctx context.Context, trackingNumber s
 		ORDER BY 
 			timestamp ASC`
 
-	rows, err := r.db.QueryContext(ctx, eventsQuery, delivery.ID)
+	rows, err := r.db.QueryContext(ctx, eventsQuery, deliveryID)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -358,19 +478,138 @@ func (r *PostgresRepository) TrackDelivery(ctx context.Context, trackingNumber s
 
 	for rows.Next() {
 		var event model.DeliveryEvent
+		var parcelID, reasonCode, hubID, carrier sql.NullString
 		err := rows.Scan(
-			&event.ID, &event.DeliveryID, &event.Status, 
-			&event.Location, &event.Description, &event.Timestamp,
+			&event.ID, &event.DeliveryID, &parcelID, &event.Status, 
+			&event.Location, &event.Description, &event.Latitude, &event.Longitude, &reasonCode, &hubID, &carrier, &event.Timestamp,
 		)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
+		event.ParcelID = parcelID.String
+		event.ReasonCode = reasonCode.String
+		event.HubID = hubID.String
+		event.Carrier = carrier.String
 		events = append(events, &event)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	return &delivery, events, nil
+	return events, nil
+}
+
+// deliverySelect selects a delivery joined with its shipping address, in the
+// column order expected by scanDelivery.
+const deliverySelect = `
+		SELECT 
+			d.id, d.order_id, d.delivery_type, d.parent_delivery_id, d.return_reason, d.return_to,
+			d.status, d.tracking_number, d.courier_id,
+			d.estimated_delivery_time, d.actual_delivery_time, d.created_at, d.updated_at,
+			d.service_level, d.scheduled_for, d.slot_reservation_id, s.starts_at, s.ends_at, d.zone_id,
+			d.requires_verification, d.attempt_count, d.next_attempt_at,
+			d.cancel_reason, d.cancel_note, d.cancelled_by, d.cancelled_at,
+			d.pickup_point_id, d.pickup_expires_at, d.current_hub_id, d.manifest_id,
+			d.cod_amount_cents, d.cod_currency, d.carrier, d.carrier_tracking_number,
+			a.street, a.city, a.state, a.country, a.zip_code, a.latitude, a.longitude
+		FROM 
+			deliveries d
+		JOIN 
+			delivery_addresses a ON d.id = a.delivery_id
+		LEFT JOIN 
+			slot_reservations sr ON sr.id = d.slot_reservation_id
+		LEFT JOIN 
+			delivery_slots s ON s.id = sr.slot_id`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDelivery(row rowScanner) (*model.Delivery, error) {
+	var delivery model.Delivery
+	var street, city, state, country, zipCode sql.NullString
+	var latitude, longitude sql.NullFloat64
+	var actualDeliveryTime, scheduledFor, slotStart, slotEnd, nextAttemptAt sql.NullTime
+	var slotReservationID, zoneID sql.NullString
+	var parentDeliveryID, returnReason, returnTo sql.NullString
+	var cancelReason, cancelNote, cancelledBy sql.NullString
+	var cancelledAt sql.NullTime
+	var pickupPointID, currentHubID, manifestID, codCurrency sql.NullString
+	var carrier, carrierTrackingNumber sql.NullString
+	var pickupExpiresAt sql.NullTime
+
+	err := row.Scan(
+		&delivery.ID, &delivery.OrderID, &delivery.Type, &parentDeliveryID, &returnReason, &returnTo,
+		&delivery.Status, &delivery.TrackingNumber, &delivery.CourierID,
+		&delivery.EstimatedDeliveryTime, &actualDeliveryTime, &delivery.CreatedAt, &delivery.UpdatedAt,
+		&delivery.ServiceLevel, &scheduledFor, &slotReservationID, &slotStart, &slotEnd, &zoneID,
+		&delivery.RequiresVerification, &delivery.AttemptCount, &nextAttemptAt,
+		&cancelReason, &cancelNote, &cancelledBy, &cancelledAt,
+		&pickupPointID, &pickupExpiresAt, &currentHubID, &manifestID,
+		&delivery.CODAmountCents, &codCurrency, &carrier, &carrierTrackingNumber,
+		&street, &city, &state, &country, &zipCode, &latitude, &longitude,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Handle nullable fields
+	if actualDeliveryTime.Valid {
+		actualTime := actualDeliveryTime.Time
+		delivery.ActualDeliveryTime = &actualTime
+	}
+	if scheduledFor.Valid {
+		scheduledTime := scheduledFor.Time
+		delivery.ScheduledFor = &scheduledTime
+	}
+	if nextAttemptAt.Valid {
+		nextAttempt := nextAttemptAt.Time
+		delivery.NextAttemptAt = &nextAttempt
+	}
+	if cancelledAt.Valid {
+		delivery.Cancellation = &model.Cancellation{
+			ReasonCode:  cancelReason.String,
+			Note:        cancelNote.String,
+			CancelledBy: cancelledBy.String,
+			CancelledAt: cancelledAt.Time,
+		}
+	}
+	if pickupExpiresAt.Valid {
+		pickupExpires := pickupExpiresAt.Time
+		delivery.PickupExpiresAt = &pickupExpires
+	}
+	delivery.PickupPointID = pickupPointID.String
+	delivery.CurrentHubID = currentHubID.String
+	delivery.ManifestID = manifestID.String
+	delivery.CODCurrency = codCurrency.String
+	delivery.Carrier = carrier.String
+	delivery.CarrierTrackingNumber = carrierTrackingNumber.String
+	delivery.ParentDeliveryID = parentDeliveryID.String
+	delivery.ReturnReason = returnReason.String
+	delivery.ReturnTo = returnTo.String
+	delivery.SlotReservationID = slotReservationID.String
+	delivery.ZoneID = zoneID.String
+	if slotStart.Valid && slotEnd.Valid {
+		delivery.TimeWindow = &model.TimeWindow{Start: slotStart.Time, End: slotEnd.Time}
+	}
+
+	// Set address fields
+	delivery.ShippingAddress = model.Address{
+		Street:  street.String,
+		City:    city.String,
+		State:   state.String,
+		Country: country.String,
+		ZipCode: zipCode.String,
+	}
+	if latitude.Valid && longitude.Valid {
+		delivery.ShippingAddress.Latitude = &latitude.Float64
+		delivery.ShippingAddress.Longitude = &longitude.Float64
+	}
+
+	return &delivery, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
 }
\ No newline at end of file
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var ErrNotRedirectable = errors.New("address can only be changed before the delivery is out for delivery")
+
+// UpdateDeliveryAddress replaces a delivery's shipping address, zone and
+// estimate, and records the change in the address history and event trail.
+// It fails with ErrNotRedirectable if the delivery has left the status the
+// change was checked against.
+func (r *PostgresRepository) UpdateDeliveryAddress(ctx context.Context, change *model.AddressChange, estimate time.Time, status string) (*model.Delivery, error) {
+	oldAddress, err := json.Marshal(change.OldAddress)
+	if err != nil {
+		return nil, err
+	}
+	newAddress, err := json.Marshal(change.NewAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	change.ID = uuid.New().String()
+	change.ChangedAt = time.Now()
+	addr := change.NewAddress
+
+	// The estimate was revised for the status the delivery had when checked
+	result, err := tx.ExecContext(ctx, `
+		UPDATE deliveries 
+		SET zone_id = $2, estimated_delivery_time = $3, pickup_point_id = $4, updated_at = $5 
+		WHERE id = $1 AND status = $6 AND status = ANY($7)`,
+		change.DeliveryID, nullString(change.NewZoneID), estimate, nullString(change.PickupPointID), change.ChangedAt,
+		status, pq.Array(model.RedirectableStatuses),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error updating delivery: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, ErrNotRedirectable
+	}
+
+	// Redirecting to a pickup point needs room there
+	if change.PickupPointID != "" {
+		if err := claimPickupSpace(ctx, tx, change.PickupPointID); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE delivery_addresses 
+		SET street = $2, city = $3, state = $4, country = $5, zip_code = $6, latitude = $7, longitude = $8 
+		WHERE delivery_id = $1`,
+		change.DeliveryID, addr.Street, addr.City, addr.State, addr.Country, addr.ZipCode,
+		addr.Latitude, addr.Longitude,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error updating delivery address: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO delivery_address_history (
+			id, delivery_id, old_address, new_address, old_zone_id, new_zone_id, reason, pickup_point_id,
+			changed_by, changed_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		change.ID, change.DeliveryID, string(oldAddress), string(newAddress),
+		nullString(change.OldZoneID), nullString(change.NewZoneID), nullString(change.Reason),
+		nullString(change.PickupPointID), change.ChangedBy, change.ChangedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error recording address history: %w", err)
+	}
+
+	description := "Delivery address changed"
+	if change.PickupPointID != "" {
+		description = "Delivery redirected to a pickup point"
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO delivery_events (
+			id, delivery_id, status, location, description, latitude, longitude, timestamp
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		uuid.New().String(), change.DeliveryID, model.EventAddressChanged, addr.City,
+		description, addr.Latitude, addr.Longitude, change.ChangedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating delivery event: %w", err)
+	}
+
+	// Send the delivery to the hub serving its new address
+	if change.LastMileHubID != "" {
+		if err := retargetFinalLeg(ctx, tx, change.DeliveryID, change.LastMileHubID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := insertETARevision(ctx, tx, change.DeliveryID, estimate, status, change.ChangedAt); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r.GetDelivery(ctx, change.DeliveryID)
+}
+
+func (r *PostgresRepository) ListAddressHistory(ctx context.Context, deliveryID string) ([]*model.AddressChange, error) {
+	query := `
+		SELECT 
+			id, delivery_id, old_address, new_address, old_zone_id, new_zone_id, reason, pickup_point_id,
+			changed_by, changed_at
+		FROM 
+			delivery_address_history
+		WHERE 
+			delivery_id = $1
+		ORDER BY 
+			changed_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []*model.AddressChange
+	for rows.Next() {
+		var change model.AddressChange
+		var oldAddress, newAddress []byte
+		var oldZoneID, newZoneID, reason, pickupPointID sql.NullString
+
+		err := rows.Scan(
+			&change.ID, &change.DeliveryID, &oldAddress, &newAddress,
+			&oldZoneID, &newZoneID, &reason, &pickupPointID, &change.ChangedBy, &change.ChangedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(oldAddress, &change.OldAddress); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(newAddress, &change.NewAddress); err != nil {
+			return nil, err
+		}
+		change.OldZoneID = oldZoneID.String
+		change.NewZoneID = newZoneID.String
+		change.Reason = reason.String
+		change.PickupPointID = pickupPointID.String
+
+		changes = append(changes, &change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
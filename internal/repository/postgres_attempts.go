@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/google/uuid"
+)
+
+// RecordFailedAttempt moves a delivery to FAILED_ATTEMPT, increments its
+// attempt counter and records the reason in the event trail.
+func (r *PostgresRepository) RecordFailedAttempt(ctx context.Context, req *model.FailedAttemptRequest) (*model.Delivery, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE deliveries 
+		SET status = $2, attempt_count = attempt_count + 1, next_attempt_at = NULL, updated_at = $3 
+		WHERE id = $1`,
+		req.DeliveryID, model.StatusFailedAttempt, now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error recording failed attempt: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO delivery_events (
+			id, delivery_id, status, location, description, latitude, longitude, reason_code, timestamp
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		uuid.New().String(), req.DeliveryID, model.StatusFailedAttempt, "Recipient address",
+		req.Note, req.Latitude, req.Longitude, req.ReasonCode, now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating delivery event: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r.GetDelivery(ctx, req.DeliveryID)
+}
+
+// ScheduleReattempt sets when the next attempt will be made, which also
+// becomes the delivery's estimated delivery time.
+func (r *PostgresRepository) ScheduleReattempt(ctx context.Context, deliveryID string, at time.Time, status string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE deliveries 
+		SET next_attempt_at = $2, estimated_delivery_time = $2, updated_at = $3 
+		WHERE id = $1`,
+		deliveryID, at, now,
+	)
+	if err != nil {
+		return fmt.Errorf("error scheduling reattempt: %w", err)
+	}
+
+	if err := insertETARevision(ctx, tx, deliveryID, at, status, now); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var ErrNotCancellable = errors.New("delivery can no longer be cancelled")
+
+// CancelDelivery cancels a delivery that is in one of the cancellable
+// statuses. It unassigns the courier, releases the reserved slot and cancels
+// undelivered parcels in the same transaction. A delivery listed on a
+// manifest keeps its courier, as the manifest records who held it. It
+// returns nil if the delivery does not exist.
+//
+// confirm, if set, runs once the delivery is locked and known to be
+// cancellable, before anything is written; an error from it leaves the
+// delivery as it was.
+func (r *PostgresRepository) CancelDelivery(ctx context.Context, req *model.CancelDeliveryRequest, cancellable []string, confirm func() error) (*model.Delivery, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	var slotReservationID sql.NullString
+	err = tx.QueryRowContext(ctx,
+		`SELECT status, slot_reservation_id FROM deliveries WHERE id = $1 FOR UPDATE`,
+		req.DeliveryID,
+	).Scan(&status, &slotReservationID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No delivery found
+		}
+		return nil, err
+	}
+
+	allowed := false
+	for _, s := range cancellable {
+		if s == status {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return nil, ErrNotCancellable
+	}
+	if confirm != nil {
+		if err := confirm(); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE deliveries 
+		SET status = $2, courier_id = CASE WHEN manifest_id IS NULL THEN '' ELSE courier_id END,
+			next_attempt_at = NULL, slot_reservation_id = NULL,
+			cancel_reason = $3, cancel_note = $4, cancelled_by = $5, cancelled_at = $6, updated_at = $6 
+		WHERE id = $1`,
+		req.DeliveryID, model.StatusCancelled, req.ReasonCode, nullString(req.Note), req.Actor.ID, now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error cancelling delivery: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO delivery_events (
+			id, delivery_id, status, location, description, reason_code, timestamp
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		uuid.New().String(), req.DeliveryID, model.StatusCancelled, "",
+		req.Note, req.ReasonCode, now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating delivery event: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE parcels SET status = $2, updated_at = $3 WHERE delivery_id = $1 AND status <> ALL($4)`,
+		req.DeliveryID, model.StatusCancelled, now, pq.Array(model.TerminalStatuses),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error cancelling parcels: %w", err)
+	}
+
+	if slotReservationID.Valid {
+		_, err = tx.ExecContext(ctx,
+			`UPDATE slot_reservations SET status = $2 WHERE id = $1 AND status IN ('HELD', 'CONFIRMED')`,
+			slotReservationID.String, model.ReservationReleased,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error releasing slot reservation: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r.GetDelivery(ctx, req.DeliveryID)
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// CarrierEventWrite is a normalized carrier tracking update. CarrierEventID
+// is the carrier's own ID for it, so fetching the same update twice records
+// it once.
+type CarrierEventWrite struct {
+	Event          *model.DeliveryEvent
+	CarrierEventID string
+}
+
+// ListCarrierDeliveries returns the deliveries handed to carriers that have
+// not reached a terminal status.
+func (r *PostgresRepository) ListCarrierDeliveries(ctx context.Context) ([]*model.Delivery, error) {
+	query := deliverySelect + `
+		WHERE 
+			d.carrier IS NOT NULL AND d.carrier_tracking_number IS NOT NULL AND d.status <> ALL($1)
+		ORDER BY 
+			d.created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(model.TerminalStatuses))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*model.Delivery
+
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// RecordCarrierEvents adds carrier updates to a delivery's events, oldest
+// first, and moves the delivery to the status of each update not seen before.
+// Updates older than the latest status the carrier already reported arrived
+// out of order and, like deliveries that reached a terminal status, only gain
+// history. It returns how many updates were new.
+func (r *PostgresRepository) RecordCarrierEvents(ctx context.Context, deliveryID string, writes []CarrierEventWrite) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Serialize syncs of the same delivery so they agree on the latest update
+	var id string
+	err = tx.QueryRowContext(ctx, `SELECT id FROM deliveries WHERE id = $1 FOR UPDATE`, deliveryID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil // No delivery found
+		}
+		return 0, err
+	}
+	latest, err := latestCarrierStatusTime(ctx, tx, deliveryID)
+	if err != nil {
+		return 0, err
+	}
+
+	recorded := 0
+	for _, w := range writes {
+		e := w.Event
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO delivery_events (
+				id, delivery_id, status, location, description, carrier, carrier_event_id, timestamp
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (delivery_id, carrier_event_id) WHERE carrier_event_id IS NOT NULL DO NOTHING`,
+			uuid.New().String(), deliveryID, e.Status, e.Location, e.Description, e.Carrier,
+			w.CarrierEventID, e.Timestamp,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("error creating delivery event: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			continue // Already recorded
+		}
+		recorded++
+
+		if model.IsInformational(e.Status) || e.Timestamp.Before(latest) {
+			continue
+		}
+		latest = e.Timestamp
+
+		result, err = tx.ExecContext(ctx, `
+			UPDATE deliveries 
+			SET status = $2, updated_at = NOW(),
+				actual_delivery_time = CASE WHEN $2 = $4 THEN $5 ELSE actual_delivery_time END
+			WHERE id = $1 AND status <> ALL($3)`,
+			deliveryID, e.Status, pq.Array(model.TerminalStatuses), model.StatusDelivered, e.Timestamp,
+		)
+		if err != nil {
+			return 0, err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			continue
+		}
+		_, err = tx.ExecContext(ctx,
+			`UPDATE parcels SET status = $2, updated_at = NOW() WHERE delivery_id = $1 AND status = ANY($3)`,
+			deliveryID, e.Status, pq.Array(model.ParcelsBehind(e.Status)),
+		)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return recorded, nil
+}
+
+// latestCarrierStatusTime returns when the latest carrier update that moved,
+// or could have moved, the delivery's status happened.
+func latestCarrierStatusTime(ctx context.Context, tx *sql.Tx, deliveryID string) (time.Time, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT status, timestamp FROM delivery_events WHERE delivery_id = $1 AND carrier_event_id IS NOT NULL`,
+		deliveryID,
+	)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer rows.Close()
+
+	var latest time.Time
+	for rows.Next() {
+		var status string
+		var at time.Time
+		if err := rows.Scan(&status, &at); err != nil {
+			return time.Time{}, err
+		}
+		if !model.IsInformational(status) && at.After(latest) {
+			latest = at
+		}
+	}
+	return latest, rows.Err()
+}
@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// insertCODCollection records a COD payment. Cash taken by a courier is also
+// booked to their cash ledger.
+func insertCODCollection(ctx context.Context, db execer, collection *model.CODCollection) error {
+	collection.ID = uuid.New().String()
+
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO cod_collections (
+			id, delivery_id, courier_id, due_cents, amount_cents, currency, method, reference, collected_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		collection.ID, collection.DeliveryID, nullString(collection.CourierID), collection.DueCents,
+		collection.AmountCents, collection.Currency, collection.Method, nullString(collection.Reference),
+		collection.CollectedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("error recording COD collection: %w", err)
+	}
+
+	if collection.Method != model.PaymentCash || collection.CourierID == "" {
+		return nil
+	}
+	return insertLedgerEntry(ctx, db, &model.LedgerEntry{
+		CourierID:   collection.CourierID,
+		Type:        model.LedgerCollection,
+		DeliveryID:  collection.DeliveryID,
+		AmountCents: collection.AmountCents,
+		Currency:    collection.Currency,
+		Reference:   collection.Reference,
+		RecordedBy:  collection.CourierID,
+		CreatedAt:   collection.CollectedAt,
+	})
+}
+
+func insertLedgerEntry(ctx context.Context, db execer, entry *model.LedgerEntry) error {
+	entry.ID = uuid.New().String()
+
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO courier_cash_ledger (
+			id, courier_id, entry_type, delivery_id, amount_cents, currency, reference, recorded_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		entry.ID, entry.CourierID, entry.Type, nullString(entry.DeliveryID), entry.AmountCents,
+		entry.Currency, nullString(entry.Reference), entry.RecordedBy, entry.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("error creating ledger entry: %w", err)
+	}
+	return nil
+}
+
+// CreateLedgerEntry books a cash movement that is not a collection, such as
+// a deposit.
+func (r *PostgresRepository) CreateLedgerEntry(ctx context.Context, entry *model.LedgerEntry) (*model.LedgerEntry, error) {
+	entry.CreatedAt = time.Now()
+	if err := insertLedgerEntry(ctx, r.db, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+const codCollectionSelect = `
+		SELECT 
+			id, delivery_id, courier_id, due_cents, amount_cents, currency, method, reference, collected_at
+		FROM 
+			cod_collections`
+
+func (r *PostgresRepository) GetCODCollection(ctx context.Context, deliveryID string) (*model.CODCollection, error) {
+	query := codCollectionSelect + `
+		WHERE 
+			delivery_id = $1`
+
+	collection, err := scanCODCollection(r.db.QueryRowContext(ctx, query, deliveryID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No collection found
+		}
+		return nil, err
+	}
+	return collection, nil
+}
+
+// ListCODCollections returns what a courier collected in [from, to), along
+// with any collections for the given deliveries whenever they were made.
+func (r *PostgresRepository) ListCODCollections(ctx context.Context, courierID string, from, to time.Time, deliveryIDs []string) ([]*model.CODCollection, error) {
+	query := codCollectionSelect + `
+		WHERE 
+			(courier_id = $1 AND collected_at >= $2 AND collected_at < $3) OR delivery_id = ANY($4)
+		ORDER BY 
+			collected_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, courierID, from, to, pq.Array(deliveryIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var collections []*model.CODCollection
+
+	for rows.Next() {
+		collection, err := scanCODCollection(rows)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, collection)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return collections, nil
+}
+
+func scanCODCollection(row rowScanner) (*model.CODCollection, error) {
+	var collection model.CODCollection
+	var courierID, reference sql.NullString
+
+	err := row.Scan(
+		&collection.ID, &collection.DeliveryID, &courierID, &collection.DueCents, &collection.AmountCents,
+		&collection.Currency, &collection.Method, &reference, &collection.CollectedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	collection.CourierID = courierID.String
+	collection.Reference = reference.String
+	return &collection, nil
+}
+
+// ListLedgerEntries returns a courier's cash movements in [from, to), oldest
+// first.
+func (r *PostgresRepository) ListLedgerEntries(ctx context.Context, courierID string, from, to time.Time) ([]*model.LedgerEntry, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT 
+			id, courier_id, entry_type, delivery_id, amount_cents, currency, reference, recorded_by, created_at
+		FROM 
+			courier_cash_ledger
+		WHERE 
+			courier_id = $1 AND created_at >= $2 AND created_at < $3
+		ORDER BY 
+			created_at ASC`,
+		courierID, from, to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*model.LedgerEntry
+
+	for rows.Next() {
+		var entry model.LedgerEntry
+		var deliveryID, reference sql.NullString
+		err := rows.Scan(
+			&entry.ID, &entry.CourierID, &entry.Type, &deliveryID, &entry.AmountCents,
+			&entry.Currency, &reference, &entry.RecordedBy, &entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entry.DeliveryID = deliveryID.String
+		entry.Reference = reference.String
+		entries = append(entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// CashBalances returns the cash a courier currently holds, per currency.
+func (r *PostgresRepository) CashBalances(ctx context.Context, courierID string) ([]model.CashBalance, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT 
+			currency, SUM(amount_cents)
+		FROM 
+			courier_cash_ledger
+		WHERE 
+			courier_id = $1
+		GROUP BY 
+			currency
+		ORDER BY 
+			currency ASC`,
+		courierID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	balances := []model.CashBalance{}
+
+	for rows.Next() {
+		var balance model.CashBalance
+		if err := rows.Scan(&balance.Currency, &balance.AmountCents); err != nil {
+			return nil, err
+		}
+		balances = append(balances, balance)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return balances, nil
+}
+
+// SaveReconciliations replaces a courier's reconciliations for a date with
+// the given ones, one per currency.
+func (r *PostgresRepository) SaveReconciliations(ctx context.Context, courierID, date string, reconciliations []*model.CODReconciliation) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM cod_reconciliations WHERE courier_id = $1 AND reconciliation_date = $2`,
+		courierID, date,
+	)
+	if err != nil {
+		return fmt.Errorf("error replacing reconciliation: %w", err)
+	}
+
+	discrepancyQuery := `
+		INSERT INTO cod_discrepancies (
+			id, reconciliation_id, kind, delivery_id, manifest_id, expected_cents, actual_cents
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	for _, rec := range reconciliations {
+		rec.ID = uuid.New().String()
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO cod_reconciliations (
+				id, courier_id, reconciliation_date, currency, status, due_cents, collected_cents,
+				cash_collected_cents, deposited_cents, manifest_ids, reconciled_by, reconciled_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+			rec.ID, rec.CourierID, rec.Date, rec.Currency, rec.Status, rec.DueCents, rec.CollectedCents,
+			rec.CashCollectedCents, rec.DepositedCents, pq.Array(rec.ManifestIDs), rec.ReconciledBy,
+			rec.ReconciledAt,
+		)
+		if err != nil {
+			return fmt.Errorf("error creating reconciliation: %w", err)
+		}
+
+		for _, d := range rec.Discrepancies {
+			d.ID = uuid.New().String()
+			d.ReconciliationID = rec.ID
+			_, err = tx.ExecContext(ctx, discrepancyQuery,
+				d.ID, d.ReconciliationID, d.Kind, nullString(d.DeliveryID), nullString(d.ManifestID),
+				d.ExpectedCents, d.ActualCents,
+			)
+			if err != nil {
+				return fmt.Errorf("error creating discrepancy: %w", err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListDiscrepancies returns the discrepancies found by the latest
+// reconciliation of each courier and date in the filter.
+func (r *PostgresRepository) ListDiscrepancies(ctx context.Context, filter model.DiscrepancyFilter) ([]*model.CODDiscrepancy, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT 
+			d.id, d.reconciliation_id, c.courier_id, c.reconciliation_date, c.currency, d.kind,
+			d.delivery_id, d.manifest_id, d.expected_cents, d.actual_cents
+		FROM 
+			cod_discrepancies d
+		JOIN 
+			cod_reconciliations c ON c.id = d.reconciliation_id
+		WHERE 
+			($1 = '' OR c.courier_id = $1)
+			AND c.reconciliation_date >= $2::date AND c.reconciliation_date <= $3::date
+		ORDER BY 
+			c.reconciliation_date ASC, c.courier_id ASC, d.kind ASC`,
+		filter.CourierID, filter.From, filter.To,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	discrepancies := []*model.CODDiscrepancy{}
+
+	for rows.Next() {
+		var d model.CODDiscrepancy
+		var date time.Time
+		var deliveryID, manifestID sql.NullString
+		err := rows.Scan(
+			&d.ID, &d.ReconciliationID, &d.CourierID, &date, &d.Currency, &d.Kind,
+			&deliveryID, &manifestID, &d.ExpectedCents, &d.ActualCents,
+		)
+		if err != nil {
+			return nil, err
+		}
+		d.Date = date.Format("2006-01-02")
+		d.DeliveryID = deliveryID.String
+		d.ManifestID = manifestID.String
+		discrepancies = append(discrepancies, &d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return discrepancies, nil
+}
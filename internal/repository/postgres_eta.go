@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/eta"
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/google/uuid"
+)
+
+// TransitTime implements eta.TransitHistory by mining delivery_events for the
+// median time between an event with fromStatus and the actual delivery. A
+// delivery that reached fromStatus more than once is measured from the last
+// time. The lane's origin is the hub of the delivery's first transit leg.
+func (r *PostgresRepository) TransitTime(ctx context.Context, lane eta.Lane, serviceLevel, fromStatus string, since time.Time) (time.Duration, int, error) {
+	query := `
+		SELECT 
+			COUNT(*),
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (
+				ORDER BY EXTRACT(EPOCH FROM (t.delivered_at - t.reached_at))
+			), 0)
+		FROM (
+			SELECT 
+				d.actual_delivery_time AS delivered_at, MAX(e.timestamp) AS reached_at
+			FROM 
+				delivery_events e
+			JOIN 
+				deliveries d ON d.id = e.delivery_id
+			LEFT JOIN 
+				transit_legs l ON l.delivery_id = d.id AND l.sequence = 1
+			WHERE 
+				e.status = $1
+				AND e.timestamp <= d.actual_delivery_time
+				AND d.actual_delivery_time IS NOT NULL
+				AND d.actual_delivery_time >= $2
+				AND COALESCE(l.from_hub_id, '') = $3
+				AND d.zone_id = $4
+				AND d.service_level = $5
+			GROUP BY 
+				d.id, d.actual_delivery_time
+		) t`
+
+	var samples int
+	var seconds float64
+	err := r.db.QueryRowContext(ctx, query, fromStatus, since, lane.Origin, lane.Destination, serviceLevel).Scan(&samples, &seconds)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return time.Duration(seconds * float64(time.Second)), samples, nil
+}
+
+// RecordETARevision stores a new estimate on the delivery and appends it to the
+// delivery's revision history.
+func (r *PostgresRepository) RecordETARevision(ctx context.Context, deliveryID string, estimate time.Time, status string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE deliveries SET estimated_delivery_time = $2, updated_at = $3 WHERE id = $1`,
+		deliveryID, estimate, now,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating delivery estimate: %w", err)
+	}
+
+	if err := insertETARevision(ctx, tx, deliveryID, estimate, status, now); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostgresRepository) ListETARevisions(ctx context.Context, deliveryID string) ([]*model.ETARevision, error) {
+	query := `
+		SELECT 
+			id, delivery_id, estimated_delivery_time, status, created_at
+		FROM 
+			eta_revisions
+		WHERE 
+			delivery_id = $1
+		ORDER BY 
+			created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*model.ETARevision
+
+	for rows.Next() {
+		var revision model.ETARevision
+		err := rows.Scan(
+			&revision.ID, &revision.DeliveryID, &revision.EstimatedDeliveryTime,
+			&revision.Status, &revision.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// ETAAccuracy reports how close the first and last estimates were for
+// deliveries completed between from and to.
+func (r *PostgresRepository) ETAAccuracy(ctx context.Context, from, to time.Time) (*model.ETAAccuracyReport, error) {
+	query := `
+		WITH completed AS (
+			SELECT id, actual_delivery_time
+			FROM deliveries
+			WHERE actual_delivery_time >= $1 AND actual_delivery_time < $2
+		),
+		initial AS (
+			SELECT DISTINCT ON (r.delivery_id) r.delivery_id, r.estimated_delivery_time
+			FROM eta_revisions r JOIN completed c ON c.id = r.delivery_id
+			ORDER BY r.delivery_id, r.created_at ASC
+		),
+		final AS (
+			SELECT DISTINCT ON (r.delivery_id) r.delivery_id, r.estimated_delivery_time
+			FROM eta_revisions r JOIN completed c ON c.id = r.delivery_id
+			WHERE r.created_at <= c.actual_delivery_time
+			ORDER BY r.delivery_id, r.created_at DESC
+		)
+		SELECT 
+			COUNT(*),
+			COALESCE(AVG(ABS(EXTRACT(EPOCH FROM (c.actual_delivery_time - i.estimated_delivery_time)))) / 3600, 0),
+			COALESCE(AVG(ABS(EXTRACT(EPOCH FROM (c.actual_delivery_time - f.estimated_delivery_time)))) / 3600, 0),
+			COALESCE(AVG(CASE WHEN c.actual_delivery_time <= i.estimated_delivery_time THEN 1.0 ELSE 0.0 END), 0),
+			COALESCE(AVG(CASE WHEN c.actual_delivery_time <= f.estimated_delivery_time THEN 1.0 ELSE 0.0 END), 0)
+		FROM 
+			completed c
+		JOIN 
+			initial i ON i.delivery_id = c.id
+		JOIN 
+			final f ON f.delivery_id = c.id`
+
+	report := &model.ETAAccuracyReport{From: from, To: to}
+	err := r.db.QueryRowContext(ctx, query, from, to).Scan(
+		&report.Deliveries, &report.InitialMAEHours, &report.FinalMAEHours,
+		&report.InitialOnTimeRate, &report.FinalOnTimeRate,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func insertETARevision(ctx context.Context, db execer, deliveryID string, estimate time.Time, status string, at time.Time) error {
+	query := `
+		INSERT INTO eta_revisions (
+			id, delivery_id, estimated_delivery_time, status, created_at
+		) VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := db.ExecContext(ctx, query, uuid.New().String(), deliveryID, estimate, status, at)
+	if err != nil {
+		return fmt.Errorf("error recording eta revision: %w", err)
+	}
+	return nil
+}
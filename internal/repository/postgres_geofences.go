@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/geofence"
+	"github.com/google/uuid"
+)
+
+func (r *PostgresRepository) CreateGeofence(ctx context.Context, fence *geofence.Fence) (*geofence.Fence, error) {
+	fence.ID = uuid.New().String()
+
+	query := `
+		INSERT INTO geofences (
+			id, kind, ref_id, name, latitude, longitude, radius, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		fence.ID, fence.Kind, fence.RefID, fence.Name,
+		fence.Center.Lat, fence.Center.Lng, fence.Radius, time.Now(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating geofence: %w", err)
+	}
+
+	return fence, nil
+}
+
+func (r *PostgresRepository) ListGeofences(ctx context.Context, kind string) ([]geofence.Fence, error) {
+	query := `
+		SELECT 
+			id, kind, ref_id, name, latitude, longitude, radius
+		FROM 
+			geofences
+		WHERE 
+			kind = $1
+		ORDER BY 
+			name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, kind)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fences []geofence.Fence
+
+	for rows.Next() {
+		var f geofence.Fence
+		err := rows.Scan(&f.ID, &f.Kind, &f.RefID, &f.Name, &f.Center.Lat, &f.Center.Lng, &f.Radius)
+		if err != nil {
+			return nil, err
+		}
+		fences = append(fences, f)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return fences, nil
+}
@@ -0,0 +1,491 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrScanStale reports a scan for a delivery whose status changed after the
+// scan was checked, e.g. one cancelled or delivered in the meantime.
+var ErrScanStale = errors.New("delivery status changed while the scan was recorded")
+
+const hubSelect = `
+		SELECT 
+			id, code, name, kind, street, city, state, country, zip_code, latitude, longitude,
+			zone_ids, active, created_at, updated_at
+		FROM 
+			hubs`
+
+func (r *PostgresRepository) CreateHub(ctx context.Context, hub *model.Hub) (*model.Hub, error) {
+	hub.ID = uuid.New().String()
+	hub.CreatedAt = time.Now()
+	hub.UpdatedAt = hub.CreatedAt
+
+	query := `
+		INSERT INTO hubs (
+			id, code, name, kind, street, city, state, country, zip_code, latitude, longitude,
+			zone_ids, active, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
+
+	addr := hub.Address
+	_, err := r.db.ExecContext(ctx, query,
+		hub.ID, hub.Code, hub.Name, hub.Kind, addr.Street, addr.City, addr.State, addr.Country, addr.ZipCode,
+		addr.Latitude, addr.Longitude, pq.Array(hub.ZoneIDs), hub.Active, hub.CreatedAt, hub.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating hub: %w", err)
+	}
+
+	return hub, nil
+}
+
+func (r *PostgresRepository) UpdateHub(ctx context.Context, hub *model.Hub) (*model.Hub, error) {
+	hub.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE hubs 
+		SET code = $2, name = $3, kind = $4, street = $5, city = $6, state = $7, country = $8, 
+			zip_code = $9, latitude = $10, longitude = $11, zone_ids = $12, active = $13, updated_at = $14
+		WHERE id = $1`
+
+	addr := hub.Address
+	result, err := r.db.ExecContext(ctx, query,
+		hub.ID, hub.Code, hub.Name, hub.Kind, addr.Street, addr.City, addr.State, addr.Country, addr.ZipCode,
+		addr.Latitude, addr.Longitude, pq.Array(hub.ZoneIDs), hub.Active, hub.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error updating hub: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, nil // No hub found
+	}
+
+	return r.GetHub(ctx, hub.ID)
+}
+
+func (r *PostgresRepository) GetHub(ctx context.Context, id string) (*model.Hub, error) {
+	return r.getHub(ctx, `id = $1`, id)
+}
+
+func (r *PostgresRepository) GetHubByCode(ctx context.Context, code string) (*model.Hub, error) {
+	return r.getHub(ctx, `code = $1`, code)
+}
+
+func (r *PostgresRepository) getHub(ctx context.Context, where string, arg string) (*model.Hub, error) {
+	hub, err := scanHub(r.db.QueryRowContext(ctx, hubSelect+` WHERE `+where, arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No hub found
+		}
+		return nil, err
+	}
+	return hub, nil
+}
+
+// ListHubs lists hubs, optionally only those of one kind, serving a zone or
+// active.
+func (r *PostgresRepository) ListHubs(ctx context.Context, kind, zoneID string, activeOnly bool) ([]*model.Hub, error) {
+	query := hubSelect + `
+		WHERE 
+			($1 = '' OR kind = $1) AND ($2 = '' OR $2 = ANY(zone_ids)) AND ($3 = FALSE OR active = TRUE)
+		ORDER BY 
+			code ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, kind, zoneID, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hubs []*model.Hub
+
+	for rows.Next() {
+		hub, err := scanHub(rows)
+		if err != nil {
+			return nil, err
+		}
+		hubs = append(hubs, hub)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return hubs, nil
+}
+
+func (r *PostgresRepository) ListTransitLegs(ctx context.Context, deliveryID string) ([]*model.TransitLeg, error) {
+	query := `
+		SELECT 
+			id, delivery_id, sequence, from_hub_id, to_hub_id, status, departed_at, arrived_at
+		FROM 
+			transit_legs
+		WHERE 
+			delivery_id = $1
+		ORDER BY 
+			sequence ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var legs []*model.TransitLeg
+
+	for rows.Next() {
+		var leg model.TransitLeg
+		var departedAt, arrivedAt sql.NullTime
+
+		err := rows.Scan(
+			&leg.ID, &leg.DeliveryID, &leg.Sequence, &leg.FromHubID, &leg.ToHubID,
+			&leg.Status, &departedAt, &arrivedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if departedAt.Valid {
+			departed := departedAt.Time
+			leg.DepartedAt = &departed
+		}
+		if arrivedAt.Valid {
+			arrived := arrivedAt.Time
+			leg.ArrivedAt = &arrived
+		}
+		legs = append(legs, &leg)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return legs, nil
+}
+
+// HubScanWrite is a validated hub scan ready to be recorded. Status, if set,
+// moves the delivery and its lagging parcels to that status, provided the
+// delivery is still in one of the From statuses. HistoryOnly scans arrived out
+// of order and only add to the event trail.
+type HubScanWrite struct {
+	Scan        *model.HubScan
+	Hub         *model.Hub
+	DeliveryID  string
+	ParcelID    string
+	Status      string
+	From        []string
+	HistoryOnly bool
+}
+
+// RecordHubScans records a batch of hub scans in one transaction, moving each
+// delivery on or off site and advancing its transit legs. It fails with
+// ErrScanStale, recording nothing, if a delivery can no longer be scanned or
+// has left the status a scan moves it from.
+func (r *PostgresRepository) RecordHubScans(ctx context.Context, writes []HubScanWrite) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, w := range writes {
+		if err := recordHubScan(ctx, tx, w); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func recordHubScan(ctx context.Context, tx *sql.Tx, w HubScanWrite) error {
+	scan, hub := w.Scan, w.Hub
+
+	// The scan was checked against an earlier read of the delivery
+	var status string
+	err := tx.QueryRowContext(ctx, `SELECT status FROM deliveries WHERE id = $1 FOR UPDATE`, w.DeliveryID).Scan(&status)
+	if err != nil {
+		return err
+	}
+	if !model.CanScanAtHub(status) {
+		return ErrScanStale
+	}
+
+	description := "Arrived at " + hub.Name
+	switch scan.ScanType {
+	case model.ScanSorted:
+		description = "Sorted at " + hub.Name
+	case model.ScanDeparted:
+		description = "Departed " + hub.Name
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO delivery_events (
+			id, delivery_id, parcel_id, status, location, description, latitude, longitude, hub_id, timestamp
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		uuid.New().String(), w.DeliveryID, nullString(w.ParcelID), model.ScanEventStatus(scan.ScanType),
+		hub.Name, description, hub.Address.Latitude, hub.Address.Longitude, hub.ID, scan.Timestamp,
+	)
+	if err != nil {
+		return fmt.Errorf("error creating delivery event: %w", err)
+	}
+
+	if !w.HistoryOnly {
+		if err := moveThroughHub(ctx, tx, w); err != nil {
+			return err
+		}
+	}
+
+	if w.Status != "" {
+		now := time.Now()
+		result, err := tx.ExecContext(ctx,
+			`UPDATE deliveries SET status = $2, updated_at = $3 WHERE id = $1 AND status = ANY($4)`,
+			w.DeliveryID, w.Status, now, pq.Array(w.From),
+		)
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return ErrScanStale
+		}
+		_, err = tx.ExecContext(ctx,
+			`UPDATE parcels SET status = $2, updated_at = $3 WHERE delivery_id = $1 AND status = ANY($4)`,
+			w.DeliveryID, w.Status, now, pq.Array(model.ParcelsBehind(w.Status)),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// moveThroughHub puts a delivery on site for arrivals and sorts, takes it off
+// site for departures, and advances its transit legs.
+func moveThroughHub(ctx context.Context, tx *sql.Tx, w HubScanWrite) error {
+	var err error
+	switch w.Scan.ScanType {
+	case model.ScanArrived, model.ScanSorted:
+		_, err = tx.ExecContext(ctx,
+			`UPDATE deliveries SET current_hub_id = $2, updated_at = $3 WHERE id = $1`,
+			w.DeliveryID, w.Hub.ID, time.Now(),
+		)
+	case model.ScanDeparted:
+		_, err = tx.ExecContext(ctx,
+			`UPDATE deliveries SET current_hub_id = NULL, updated_at = $3 WHERE id = $1 AND current_hub_id = $2`,
+			w.DeliveryID, w.Hub.ID, time.Now(),
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("error updating delivery hub: %w", err)
+	}
+
+	switch w.Scan.ScanType {
+	case model.ScanArrived:
+		_, err = tx.ExecContext(ctx, `
+			UPDATE transit_legs 
+			SET status = $3, arrived_at = $4, departed_at = COALESCE(departed_at, $4) 
+			WHERE delivery_id = $1 AND to_hub_id = $2 AND status <> $3`,
+			w.DeliveryID, w.Hub.ID, model.LegCompleted, w.Scan.Timestamp,
+		)
+	case model.ScanDeparted:
+		_, err = tx.ExecContext(ctx, `
+			UPDATE transit_legs 
+			SET status = $3, departed_at = $4 
+			WHERE delivery_id = $1 AND from_hub_id = $2 AND status = $5`,
+			w.DeliveryID, w.Hub.ID, model.LegInProgress, w.Scan.Timestamp, model.LegPlanned,
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("error updating transit legs: %w", err)
+	}
+	return nil
+}
+
+// ResolveScanTargets maps delivery and parcel tracking numbers to what they
+// refer to. Unknown tracking numbers are left out.
+func (r *PostgresRepository) ResolveScanTargets(ctx context.Context, trackingNumbers []string) (map[string]model.ScanTarget, error) {
+	query := `
+		SELECT 
+			d.tracking_number, d.id, '', d.status
+		FROM 
+			deliveries d
+		WHERE 
+			d.tracking_number = ANY($1)
+		UNION ALL
+		SELECT 
+			p.tracking_number, p.delivery_id, p.id, d.status
+		FROM 
+			parcels p
+		JOIN 
+			deliveries d ON d.id = p.delivery_id
+		WHERE 
+			p.tracking_number = ANY($1)`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(trackingNumbers))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	targets := make(map[string]model.ScanTarget)
+	for rows.Next() {
+		var trackingNumber string
+		var target model.ScanTarget
+		if err := rows.Scan(&trackingNumber, &target.DeliveryID, &target.ParcelID, &target.Status); err != nil {
+			return nil, err
+		}
+		targets[trackingNumber] = target
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+// ListHubScans returns the hub scan events of the given deliveries, oldest
+// first.
+func (r *PostgresRepository) ListHubScans(ctx context.Context, deliveryIDs []string) ([]*model.DeliveryEvent, error) {
+	query := `
+		SELECT 
+			id, delivery_id, parcel_id, status, hub_id, timestamp
+		FROM 
+			delivery_events
+		WHERE 
+			delivery_id = ANY($1) AND hub_id IS NOT NULL
+		ORDER BY 
+			timestamp ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(deliveryIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*model.DeliveryEvent
+	for rows.Next() {
+		var event model.DeliveryEvent
+		var parcelID sql.NullString
+		err := rows.Scan(&event.ID, &event.DeliveryID, &parcelID, &event.Status, &event.HubID, &event.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		event.ParcelID = parcelID.String
+		events = append(events, &event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// ListHubDeliveries returns the deliveries currently on site at a hub.
+func (r *PostgresRepository) ListHubDeliveries(ctx context.Context, hubID string) ([]*model.Delivery, error) {
+	query := deliverySelect + `
+		WHERE 
+			d.current_hub_id = $1 AND d.status <> ALL($2)
+		ORDER BY 
+			d.updated_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, hubID, pq.Array(model.TerminalStatuses))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*model.Delivery
+
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, delivery := range deliveries {
+		delivery.Parcels, err = r.ListParcels(ctx, delivery.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return deliveries, nil
+}
+
+func insertTransitLegs(ctx context.Context, tx *sql.Tx, deliveryID string, legs []*model.TransitLeg) error {
+	for i, leg := range legs {
+		leg.ID = uuid.New().String()
+		leg.DeliveryID = deliveryID
+		leg.Sequence = i + 1
+		if leg.Status == "" {
+			leg.Status = model.LegPlanned
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO transit_legs (
+				id, delivery_id, sequence, from_hub_id, to_hub_id, status
+			) VALUES ($1, $2, $3, $4, $5, $6)`,
+			leg.ID, leg.DeliveryID, leg.Sequence, leg.FromHubID, leg.ToHubID, leg.Status,
+		)
+		if err != nil {
+			return fmt.Errorf("error creating transit leg: %w", err)
+		}
+	}
+	return nil
+}
+
+// retargetFinalLeg points a delivery's last leg at a new hub if it has not
+// started yet.
+func retargetFinalLeg(ctx context.Context, tx *sql.Tx, deliveryID, hubID string) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE transit_legs 
+		SET to_hub_id = $2 
+		WHERE delivery_id = $1 AND status = $3 
+			AND sequence = (SELECT MAX(sequence) FROM transit_legs WHERE delivery_id = $1)`,
+		deliveryID, hubID, model.LegPlanned,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating transit leg: %w", err)
+	}
+	return nil
+}
+
+func scanHub(row rowScanner) (*model.Hub, error) {
+	var hub model.Hub
+	var latitude, longitude sql.NullFloat64
+
+	err := row.Scan(
+		&hub.ID, &hub.Code, &hub.Name, &hub.Kind, &hub.Address.Street, &hub.Address.City,
+		&hub.Address.State, &hub.Address.Country, &hub.Address.ZipCode, &latitude, &longitude,
+		pq.Array(&hub.ZoneIDs), &hub.Active, &hub.CreatedAt, &hub.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if latitude.Valid && longitude.Valid {
+		hub.Address.Latitude = &latitude.Float64
+		hub.Address.Longitude = &longitude.Float64
+	}
+
+	return &hub, nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// locationInsertRows caps the rows per INSERT, keeping each statement well
+// under Postgres's limit of 65535 parameters.
+const locationInsertRows = 1000
+
+// InsertCourierLocations writes a batch of pings in one transaction, a
+// multi-row statement at a time.
+func (r *PostgresRepository) InsertCourierLocations(ctx context.Context, locations []model.CourierLocation) error {
+	if len(locations) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for start := 0; start < len(locations); start += locationInsertRows {
+		end := start + locationInsertRows
+		if end > len(locations) {
+			end = len(locations)
+		}
+		if err := insertCourierLocations(ctx, tx, locations[start:end]); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func insertCourierLocations(ctx context.Context, tx *sql.Tx, locations []model.CourierLocation) error {
+	var sb strings.Builder
+	sb.WriteString(`
+		INSERT INTO courier_locations (
+			courier_id, latitude, longitude, accuracy, speed, heading, recorded_at
+		) VALUES `)
+
+	args := make([]interface{}, 0, len(locations)*7)
+	for i, loc := range locations {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		n := i * 7
+		fmt.Fprintf(&sb, "($%d, $%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7)
+		args = append(args, loc.CourierID, loc.Latitude, loc.Longitude, loc.Accuracy, loc.Speed, loc.Heading, loc.RecordedAt)
+	}
+
+	_, err := tx.ExecContext(ctx, sb.String(), args...)
+	if err != nil {
+		return fmt.Errorf("error storing courier locations: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListCourierLocations(ctx context.Context, courierID string, from, to time.Time) ([]*model.CourierLocation, error) {
+	query := `
+		SELECT 
+			courier_id, latitude, longitude, accuracy, speed, heading, recorded_at
+		FROM 
+			courier_locations
+		WHERE 
+			courier_id = $1 AND recorded_at >= $2 AND recorded_at < $3
+		ORDER BY 
+			recorded_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, courierID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var locations []*model.CourierLocation
+
+	for rows.Next() {
+		var loc model.CourierLocation
+		err := rows.Scan(
+			&loc.CourierID, &loc.Latitude, &loc.Longitude, &loc.Accuracy,
+			&loc.Speed, &loc.Heading, &loc.RecordedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		locations = append(locations, &loc)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return locations, nil
+}
@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrDeliveryLocked  = errors.New("delivery is on a closed manifest")
+	ErrManifestClosed  = errors.New("manifest is already closed")
+	ErrManifestChanged = errors.New("deliveries on the manifest were reassigned or manifested elsewhere")
+)
+
+// ListCourierDeliveriesBetween returns the deliveries a courier handled in
+// [from, to): those delivered in the window, and those still due in it. It
+// skips cancelled deliveries and deliveries already on a closed manifest.
+func (r *PostgresRepository) ListCourierDeliveriesBetween(ctx context.Context, courierID string, from, to time.Time) ([]*model.Delivery, error) {
+	query := deliverySelect + `
+		WHERE 
+			d.courier_id = $1 AND d.status <> $2 AND d.manifest_id IS NULL
+			AND COALESCE(d.actual_delivery_time, d.estimated_delivery_time) >= $3
+			AND COALESCE(d.actual_delivery_time, d.estimated_delivery_time) < $4
+		ORDER BY 
+			d.created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, courierID, model.StatusCancelled, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*model.Delivery
+
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// CreateManifest stores an open manifest with its items.
+func (r *PostgresRepository) CreateManifest(ctx context.Context, manifest *model.Manifest) (*model.Manifest, error) {
+	manifest.ID = uuid.New().String()
+	manifest.Status = model.ManifestOpen
+	manifest.CreatedAt = time.Now()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO manifests (
+			id, courier_id, route_id, manifest_date, status, delivery_count, parcel_count,
+			weight_kg, cod_due_cents, cod_currency, created_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		manifest.ID, manifest.CourierID, nullString(manifest.RouteID), manifest.Date, manifest.Status,
+		manifest.DeliveryCount, manifest.ParcelCount, manifest.WeightKg, manifest.CODDueCents,
+		nullString(manifest.CODCurrency), manifest.CreatedBy, manifest.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating manifest: %w", err)
+	}
+
+	itemQuery := `
+		INSERT INTO manifest_items (
+			manifest_id, sequence, delivery_id, tracking_number, order_id, status, city, zip_code,
+			parcels, weight_kg, cod_due_cents, cod_currency
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	for _, item := range manifest.Items {
+		item.ManifestID = manifest.ID
+		_, err = tx.ExecContext(ctx, itemQuery,
+			item.ManifestID, item.Sequence, item.DeliveryID, item.TrackingNumber, item.OrderID, item.Status,
+			item.City, item.ZipCode, item.Parcels, item.WeightKg, item.CODDueCents, nullString(item.CODCurrency),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error creating manifest item: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// CloseManifest records the signature on an open manifest and locks its
+// deliveries to the manifest's courier. It fails with ErrManifestChanged if
+// any delivery has since moved to another courier or another manifest.
+func (r *PostgresRepository) CloseManifest(ctx context.Context, manifest *model.Manifest) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE manifests 
+		SET status = $2, signature = $3, closed_by = $4, closed_at = $5 
+		WHERE id = $1 AND status = $6`,
+		manifest.ID, model.ManifestClosed, manifest.Signature, manifest.ClosedBy, manifest.ClosedAt,
+		model.ManifestOpen,
+	)
+	if err != nil {
+		return fmt.Errorf("error closing manifest: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrManifestClosed
+	}
+
+	deliveryIDs := make([]string, len(manifest.Items))
+	for i, item := range manifest.Items {
+		deliveryIDs[i] = item.DeliveryID
+	}
+	result, err = tx.ExecContext(ctx, `
+		UPDATE deliveries 
+		SET manifest_id = $1, updated_at = $4 
+		WHERE id = ANY($2) AND courier_id = $3 AND manifest_id IS NULL`,
+		manifest.ID, pq.Array(deliveryIDs), manifest.CourierID, manifest.ClosedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("error locking manifest deliveries: %w", err)
+	}
+	affected, err = result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected != int64(len(deliveryIDs)) {
+		return ErrManifestChanged
+	}
+
+	return tx.Commit()
+}
+
+const manifestSelect = `
+		SELECT 
+			id, courier_id, route_id, manifest_date, status, delivery_count, parcel_count,
+			weight_kg, cod_due_cents, cod_currency, signature, created_by, created_at, closed_by, closed_at
+		FROM 
+			manifests`
+
+func (r *PostgresRepository) GetManifest(ctx context.Context, id string) (*model.Manifest, error) {
+	query := manifestSelect + `
+		WHERE 
+			id = $1`
+
+	manifest, err := scanManifest(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No manifest found
+		}
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT 
+			manifest_id, sequence, delivery_id, tracking_number, order_id, status, city, zip_code,
+			parcels, weight_kg, cod_due_cents, COALESCE(cod_currency, '')
+		FROM 
+			manifest_items
+		WHERE 
+			manifest_id = $1
+		ORDER BY 
+			sequence ASC`,
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item model.ManifestItem
+		err := rows.Scan(
+			&item.ManifestID, &item.Sequence, &item.DeliveryID, &item.TrackingNumber, &item.OrderID,
+			&item.Status, &item.City, &item.ZipCode, &item.Parcels, &item.WeightKg, &item.CODDueCents,
+			&item.CODCurrency,
+		)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Items = append(manifest.Items, &item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// ListManifests returns a courier's manifests, newest first, optionally for
+// one date. Items are not loaded.
+func (r *PostgresRepository) ListManifests(ctx context.Context, courierID, date string) ([]*model.Manifest, error) {
+	query := manifestSelect + `
+		WHERE 
+			courier_id = $1 AND ($2 = '' OR manifest_date = NULLIF($2, '')::date)
+		ORDER BY 
+			manifest_date DESC, created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, courierID, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var manifests []*model.Manifest
+
+	for rows.Next() {
+		manifest, err := scanManifest(rows)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, manifest)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return manifests, nil
+}
+
+func scanManifest(row rowScanner) (*model.Manifest, error) {
+	var manifest model.Manifest
+	var routeID, codCurrency, signature, closedBy sql.NullString
+	var date time.Time
+	var closedAt sql.NullTime
+
+	err := row.Scan(
+		&manifest.ID, &manifest.CourierID, &routeID, &date, &manifest.Status,
+		&manifest.DeliveryCount, &manifest.ParcelCount, &manifest.WeightKg, &manifest.CODDueCents,
+		&codCurrency, &signature, &manifest.CreatedBy, &manifest.CreatedAt, &closedBy, &closedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest.RouteID = routeID.String
+	manifest.Date = date.Format("2006-01-02")
+	manifest.CODCurrency = codCurrency.String
+	manifest.Signature = signature.String
+	manifest.ClosedBy = closedBy.String
+	if closedAt.Valid {
+		closed := closedAt.Time
+		manifest.ClosedAt = &closed
+	}
+
+	return &manifest, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+var (
+	ErrOTPNotFound = errors.New("no PIN has been issued for this delivery")
+	ErrOTPExpired  = errors.New("PIN has expired")
+)
+
+// SaveOTP issues a new PIN for a delivery, replacing any previous one and
+// clearing its attempts.
+func (r *PostgresRepository) SaveOTP(ctx context.Context, otp *model.DeliveryOTP) error {
+	return saveOTP(ctx, r.db, otp)
+}
+
+func saveOTP(ctx context.Context, db execer, otp *model.DeliveryOTP) error {
+	query := `
+		INSERT INTO delivery_otps (
+			delivery_id, pin_hash, attempts, locked_until, expires_at, verified_at, created_at
+		) VALUES ($1, $2, 0, NULL, $3, NULL, $4)
+		ON CONFLICT (delivery_id) DO UPDATE SET
+			pin_hash = EXCLUDED.pin_hash,
+			attempts = 0,
+			locked_until = NULL,
+			expires_at = EXCLUDED.expires_at,
+			verified_at = NULL,
+			created_at = EXCLUDED.created_at`
+
+	_, err := db.ExecContext(ctx, query, otp.DeliveryID, otp.PINHash, otp.ExpiresAt, otp.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error saving delivery PIN: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetOTP(ctx context.Context, deliveryID string) (*model.DeliveryOTP, error) {
+	return getOTP(ctx, r.db, deliveryID, "")
+}
+
+// VerifyOTP checks a PIN hash against the issued PIN under a row lock. A wrong
+// PIN counts as an attempt; reaching maxAttempts locks the PIN for lockout and
+// starts a fresh set of attempts afterwards.
+func (r *PostgresRepository) VerifyOTP(ctx context.Context, deliveryID, pinHash string, maxAttempts int, lockout time.Duration) (*model.VerifyOTPResponse, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	otp, err := getOTP(ctx, tx, deliveryID, "FOR UPDATE")
+	if err != nil {
+		return nil, err
+	}
+	if otp == nil {
+		return nil, ErrOTPNotFound
+	}
+
+	now := time.Now()
+	resp := &model.VerifyOTPResponse{}
+
+	switch {
+	case otp.VerifiedAt != nil:
+		resp.Verified = true
+		return resp, nil
+	case otp.LockedUntil != nil && now.Before(*otp.LockedUntil):
+		resp.LockedUntil = otp.LockedUntil
+		return resp, nil
+	case now.After(otp.ExpiresAt):
+		return nil, ErrOTPExpired
+	}
+
+	if otp.PINHash == pinHash {
+		_, err = tx.ExecContext(ctx,
+			`UPDATE delivery_otps SET verified_at = $2, locked_until = NULL WHERE delivery_id = $1`,
+			deliveryID, now,
+		)
+		resp.Verified = true
+	} else {
+		attempts := otp.Attempts + 1
+		var lockedUntil *time.Time
+		if attempts >= maxAttempts {
+			until := now.Add(lockout)
+			attempts, lockedUntil = 0, &until
+			resp.LockedUntil = lockedUntil
+		} else {
+			resp.AttemptsLeft = maxAttempts - attempts
+		}
+		_, err = tx.ExecContext(ctx,
+			`UPDATE delivery_otps SET attempts = $2, locked_until = $3 WHERE delivery_id = $1`,
+			deliveryID, attempts, lockedUntil,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error recording PIN attempt: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func getOTP(ctx context.Context, db queryRower, deliveryID, lock string) (*model.DeliveryOTP, error) {
+	query := `
+		SELECT 
+			delivery_id, pin_hash, attempts, locked_until, expires_at, verified_at, created_at
+		FROM 
+			delivery_otps
+		WHERE 
+			delivery_id = $1 ` + lock
+
+	var otp model.DeliveryOTP
+	var lockedUntil, verifiedAt sql.NullTime
+
+	err := db.QueryRowContext(ctx, query, deliveryID).Scan(
+		&otp.DeliveryID, &otp.PINHash, &otp.Attempts, &lockedUntil, &otp.ExpiresAt, &verifiedAt, &otp.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No PIN issued
+		}
+		return nil, err
+	}
+
+	if lockedUntil.Valid {
+		otp.LockedUntil = &lockedUntil.Time
+	}
+	if verifiedAt.Valid {
+		otp.VerifiedAt = &verifiedAt.Time
+	}
+	return &otp, nil
+}
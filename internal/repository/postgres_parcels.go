@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/google/uuid"
+)
+
+// AddParcel adds a parcel to an existing delivery. Parcel tracking numbers
+// extend the delivery's tracking number with the parcel's position.
+func (r *PostgresRepository) AddParcel(ctx context.Context, parcel *model.Parcel) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the delivery so concurrent adds get distinct positions
+	var trackingNumber string
+	err = tx.QueryRowContext(ctx,
+		`SELECT tracking_number FROM deliveries WHERE id = $1 FOR UPDATE`,
+		parcel.DeliveryID,
+	).Scan(&trackingNumber)
+	if err != nil {
+		return err
+	}
+
+	var count int
+	err = tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM parcels WHERE delivery_id = $1`,
+		parcel.DeliveryID,
+	).Scan(&count)
+	if err != nil {
+		return err
+	}
+
+	if err := insertParcel(ctx, tx, parcel, trackingNumber, count+1); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostgresRepository) ListParcels(ctx context.Context, deliveryID string) ([]*model.Parcel, error) {
+	query := parcelSelect + `
+		WHERE 
+			delivery_id = $1
+		ORDER BY 
+			tracking_number ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var parcels []*model.Parcel
+	for rows.Next() {
+		parcel, err := scanParcel(rows)
+		if err != nil {
+			return nil, err
+		}
+		parcels = append(parcels, parcel)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return parcels, nil
+}
+
+func (r *PostgresRepository) GetParcelByTracking(ctx context.Context, trackingNumber string) (*model.Parcel, error) {
+	query := parcelSelect + `
+		WHERE 
+			tracking_number = $1`
+
+	parcel, err := scanParcel(r.db.QueryRowContext(ctx, query, trackingNumber))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No parcel found
+		}
+		return nil, err
+	}
+	return parcel, nil
+}
+
+// ErrDeliveryFinal reports a change to a delivery that has already reached a
+// terminal status.
+var ErrDeliveryFinal = errors.New("delivery has already reached a final status")
+
+// ErrDeliveryHeld reports a parcel change on a delivery held at a pickup
+// point, which is only handed over whole, against its pickup code.
+var ErrDeliveryHeld = errors.New("delivery is held at a pickup point and is collected with its pickup code")
+
+// ParcelUpdate is the outcome of moving a parcel: the delivery's status
+// before, and the status derived from all its parcels after.
+type ParcelUpdate struct {
+	Previous  string
+	Status    string
+	Delivered int // parcels delivered
+	Total     int
+}
+
+// UpdateParcelStatus moves one parcel to a new status, records a
+// parcel-level event and, if the status derived from all of the delivery's
+// parcels changed, moves the delivery too, all in one transaction. The
+// delivery and its parcels are locked first, so concurrent updates to
+// sibling parcels each see the other's result.
+//
+// collect, if set, is called with the derived status before anything is
+// written and returns the cash on delivery payment to record when it is
+// DELIVERED. It returns nil if the parcel does not belong to the delivery,
+// and ErrDeliveryFinal if the delivery is already terminal.
+func (r *PostgresRepository) UpdateParcelStatus(ctx context.Context, req *model.UpdateParcelRequest, collect func(status string) (*model.CODCollection, error)) (*ParcelUpdate, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var previous string
+	err = tx.QueryRowContext(ctx,
+		`SELECT status FROM deliveries WHERE id = $1 FOR UPDATE`,
+		req.DeliveryID,
+	).Scan(&previous)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No delivery found
+		}
+		return nil, err
+	}
+	if model.IsTerminal(previous) {
+		return nil, ErrDeliveryFinal
+	}
+	if previous == model.StatusAvailableForPickup {
+		return nil, ErrDeliveryHeld
+	}
+
+	parcels, err := lockParcels(ctx, tx, req.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, p := range parcels {
+		if p.ID == req.ParcelID {
+			p.Status = req.Status
+			found = true
+		}
+	}
+	if !found {
+		return nil, nil // No parcel found
+	}
+
+	update := &ParcelUpdate{Previous: previous, Status: model.DeriveStatus(parcels), Total: len(parcels)}
+	for _, p := range parcels {
+		if p.Status == model.StatusDelivered {
+			update.Delivered++
+		}
+	}
+	var collection *model.CODCollection
+	if collect != nil {
+		if collection, err = collect(update.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE parcels SET status = $3, updated_at = $4 WHERE id = $1 AND delivery_id = $2`,
+		req.ParcelID, req.DeliveryID, req.Status, now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error updating parcel: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO delivery_events (
+			id, delivery_id, parcel_id, status, location, description, latitude, longitude, timestamp
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		uuid.New().String(), req.DeliveryID, req.ParcelID, req.Status,
+		req.Location, req.Description, req.Latitude, req.Longitude, now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating parcel event: %w", err)
+	}
+
+	if update.Status != previous {
+		if err := moveDelivery(ctx, tx, req, update, collection, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return update, nil
+}
+
+// moveDelivery applies the status derived from a delivery's parcels.
+func moveDelivery(ctx context.Context, tx *sql.Tx, req *model.UpdateParcelRequest, update *ParcelUpdate, collection *model.CODCollection, now time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE deliveries 
+		SET status = $2, updated_at = $3,
+			actual_delivery_time = CASE WHEN $2 = $4 THEN $3 ELSE actual_delivery_time END
+		WHERE id = $1`,
+		req.DeliveryID, update.Status, now, model.StatusDelivered,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating delivery: %w", err)
+	}
+
+	description := req.Description
+	if update.Status == model.StatusPartiallyDelivered || update.Status == model.StatusDelivered {
+		description = fmt.Sprintf("%d of %d parcels delivered", update.Delivered, update.Total)
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO delivery_events (
+			id, delivery_id, status, location, description, latitude, longitude, timestamp
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		uuid.New().String(), req.DeliveryID, update.Status, req.Location, description,
+		req.Latitude, req.Longitude, now,
+	)
+	if err != nil {
+		return fmt.Errorf("error creating delivery event: %w", err)
+	}
+
+	// Record the cash on delivery payment taken with the last parcel
+	if collection != nil {
+		collection.CollectedAt = now
+		if err := insertCODCollection(ctx, tx, collection); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func lockParcels(ctx context.Context, tx *sql.Tx, deliveryID string) ([]*model.Parcel, error) {
+	query := parcelSelect + `
+		WHERE 
+			delivery_id = $1
+		ORDER BY 
+			tracking_number ASC
+		FOR UPDATE`
+
+	rows, err := tx.QueryContext(ctx, query, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var parcels []*model.Parcel
+	for rows.Next() {
+		parcel, err := scanParcel(rows)
+		if err != nil {
+			return nil, err
+		}
+		parcels = append(parcels, parcel)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return parcels, nil
+}
+
+func insertParcel(ctx context.Context, db execer, parcel *model.Parcel, deliveryTracking string, position int) error {
+	parcel.ID = uuid.New().String()
+	parcel.TrackingNumber = fmt.Sprintf("%s-%02d", deliveryTracking, position)
+	parcel.Status = model.StatusPending
+	parcel.CreatedAt = time.Now()
+	parcel.UpdatedAt = parcel.CreatedAt
+
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO parcels (
+			id, delivery_id, tracking_number, status, weight_kg, length_cm, width_cm, height_cm,
+			declared_value_cents, currency, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		parcel.ID, parcel.DeliveryID, parcel.TrackingNumber, parcel.Status,
+		parcel.WeightKg, parcel.LengthCm, parcel.WidthCm, parcel.HeightCm,
+		parcel.DeclaredValueCents, nullString(parcel.Currency), parcel.CreatedAt, parcel.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("error creating parcel: %w", err)
+	}
+	return nil
+}
+
+const parcelSelect = `
+		SELECT 
+			id, delivery_id, tracking_number, status, weight_kg, length_cm, width_cm, height_cm,
+			declared_value_cents, currency, created_at, updated_at
+		FROM 
+			parcels`
+
+func scanParcel(row rowScanner) (*model.Parcel, error) {
+	var parcel model.Parcel
+	var currency sql.NullString
+
+	err := row.Scan(
+		&parcel.ID, &parcel.DeliveryID, &parcel.TrackingNumber, &parcel.Status,
+		&parcel.WeightKg, &parcel.LengthCm, &parcel.WidthCm, &parcel.HeightCm,
+		&parcel.DeclaredValueCents, &currency, &parcel.CreatedAt, &parcel.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parcel.Currency = currency.String
+	return &parcel, nil
+}
@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var ErrPickupPointFull = errors.New("pickup point has no free capacity")
+
+// pickupPointSelect counts the non-terminal deliveries routed to each point as
+// its occupancy.
+const pickupPointSelect = `
+		SELECT 
+			p.id, p.name, p.kind, p.street, p.city, p.state, p.country, p.zip_code, p.latitude, p.longitude,
+			p.zone_id, p.capacity, p.hold_days, p.active, p.created_at, p.updated_at,
+			(SELECT COUNT(*) FROM deliveries d WHERE d.pickup_point_id = p.id AND d.status <> ALL($1))
+		FROM 
+			pickup_points p`
+
+func (r *PostgresRepository) CreatePickupPoint(ctx context.Context, point *model.PickupPoint) (*model.PickupPoint, error) {
+	point.ID = uuid.New().String()
+	point.CreatedAt = time.Now()
+	point.UpdatedAt = point.CreatedAt
+
+	query := `
+		INSERT INTO pickup_points (
+			id, name, kind, street, city, state, country, zip_code, latitude, longitude,
+			zone_id, capacity, hold_days, active, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+
+	addr := point.Address
+	_, err := r.db.ExecContext(ctx, query,
+		point.ID, point.Name, point.Kind, addr.Street, addr.City, addr.State, addr.Country, addr.ZipCode,
+		addr.Latitude, addr.Longitude, nullString(point.ZoneID), point.Capacity, point.HoldDays,
+		point.Active, point.CreatedAt, point.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating pickup point: %w", err)
+	}
+
+	return point, nil
+}
+
+func (r *PostgresRepository) UpdatePickupPoint(ctx context.Context, point *model.PickupPoint) (*model.PickupPoint, error) {
+	point.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE pickup_points 
+		SET name = $2, kind = $3, street = $4, city = $5, state = $6, country = $7, zip_code = $8, 
+			latitude = $9, longitude = $10, zone_id = $11, capacity = $12, hold_days = $13, 
+			active = $14, updated_at = $15
+		WHERE id = $1`
+
+	addr := point.Address
+	result, err := r.db.ExecContext(ctx, query,
+		point.ID, point.Name, point.Kind, addr.Street, addr.City, addr.State, addr.Country, addr.ZipCode,
+		addr.Latitude, addr.Longitude, nullString(point.ZoneID), point.Capacity, point.HoldDays,
+		point.Active, point.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error updating pickup point: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, nil // No pickup point found
+	}
+
+	return r.GetPickupPoint(ctx, point.ID)
+}
+
+func (r *PostgresRepository) GetPickupPoint(ctx context.Context, id string) (*model.PickupPoint, error) {
+	row := r.db.QueryRowContext(ctx, pickupPointSelect+` WHERE p.id = $2`, pq.Array(model.TerminalStatuses), id)
+	point, err := scanPickupPoint(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No pickup point found
+		}
+		return nil, err
+	}
+	return point, nil
+}
+
+// ListPickupPoints lists pickup points, optionally only the active ones in a
+// zone.
+func (r *PostgresRepository) ListPickupPoints(ctx context.Context, zoneID string, activeOnly bool) ([]*model.PickupPoint, error) {
+	query := pickupPointSelect + `
+		WHERE 
+			($2 = '' OR p.zone_id = $2) AND ($3 = FALSE OR p.active = TRUE)
+		ORDER BY 
+			p.name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(model.TerminalStatuses), zoneID, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []*model.PickupPoint
+
+	for rows.Next() {
+		point, err := scanPickupPoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
+
+// HoldAtPickupPoint records that a delivery has been dropped at its pickup
+// point and will be held there until expiresAt, and issues the pickup code
+// the recipient collects it with, in one transaction.
+func (r *PostgresRepository) HoldAtPickupPoint(ctx context.Context, deliveryID, location string, expiresAt time.Time, code *model.DeliveryOTP) (*model.Delivery, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE deliveries 
+		SET status = $2, pickup_expires_at = $3, updated_at = $4 
+		WHERE id = $1`,
+		deliveryID, model.StatusAvailableForPickup, expiresAt, now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error holding delivery at pickup point: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO delivery_events (
+			id, delivery_id, status, location, description, timestamp
+		) VALUES ($1, $2, $3, $4, $5, $6)`,
+		uuid.New().String(), deliveryID, model.StatusAvailableForPickup, location,
+		"Available for pickup until "+expiresAt.Format(time.RFC3339), now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating delivery event: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE parcels SET status = $2, updated_at = $3 WHERE delivery_id = $1 AND status = ANY($4)`,
+		deliveryID, model.StatusAvailableForPickup, now, pq.Array(model.ParcelsBehind(model.StatusAvailableForPickup)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := saveOTP(ctx, tx, code); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r.GetDelivery(ctx, deliveryID)
+}
+
+// ExpirePickupHold returns a delivery to sender if it is still waiting at its
+// pickup point after its hold expired, in one transaction. It returns nil if
+// the delivery was collected, or otherwise moved on, in the meantime.
+func (r *PostgresRepository) ExpirePickupHold(ctx context.Context, deliveryID, location, description string, now time.Time) (*model.Delivery, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE deliveries 
+		SET status = $2, updated_at = $4 
+		WHERE id = $1 AND status = $3 AND pickup_expires_at < $4`,
+		deliveryID, model.StatusReturnToSender, model.StatusAvailableForPickup, now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error expiring pickup hold: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, nil // No expired hold found
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO delivery_events (
+			id, delivery_id, status, location, description, timestamp
+		) VALUES ($1, $2, $3, $4, $5, $6)`,
+		uuid.New().String(), deliveryID, model.StatusReturnToSender, location, description, now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating delivery event: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE parcels SET status = $2, updated_at = $3 WHERE delivery_id = $1 AND status = ANY($4)`,
+		deliveryID, model.StatusReturnToSender, now, pq.Array(model.ParcelsBehind(model.StatusReturnToSender)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r.GetDelivery(ctx, deliveryID)
+}
+
+// ListExpiredPickupHolds returns the IDs of deliveries still waiting at a
+// pickup point after their hold expired.
+func (r *PostgresRepository) ListExpiredPickupHolds(ctx context.Context, now time.Time) ([]string, error) {
+	query := `
+		SELECT 
+			id
+		FROM 
+			deliveries
+		WHERE 
+			status = $1 AND pickup_expires_at < $2
+		ORDER BY 
+			pickup_expires_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, model.StatusAvailableForPickup, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// claimPickupSpace locks a pickup point for the rest of the transaction and
+// fails with ErrPickupPointFull if it is already at capacity.
+func claimPickupSpace(ctx context.Context, tx *sql.Tx, pointID string) error {
+	var capacity int
+	err := tx.QueryRowContext(ctx,
+		`SELECT capacity FROM pickup_points WHERE id = $1 FOR UPDATE`, pointID,
+	).Scan(&capacity)
+	if err != nil {
+		return fmt.Errorf("error locking pickup point: %w", err)
+	}
+
+	var occupied int
+	err = tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM deliveries WHERE pickup_point_id = $1 AND status <> ALL($2)`,
+		pointID, pq.Array(model.TerminalStatuses),
+	).Scan(&occupied)
+	if err != nil {
+		return err
+	}
+
+	if occupied >= capacity {
+		return ErrPickupPointFull
+	}
+	return nil
+}
+
+func scanPickupPoint(row rowScanner) (*model.PickupPoint, error) {
+	var point model.PickupPoint
+	var latitude, longitude sql.NullFloat64
+	var zoneID sql.NullString
+
+	err := row.Scan(
+		&point.ID, &point.Name, &point.Kind, &point.Address.Street, &point.Address.City,
+		&point.Address.State, &point.Address.Country, &point.Address.ZipCode, &latitude, &longitude,
+		&zoneID, &point.Capacity, &point.HoldDays, &point.Active, &point.CreatedAt, &point.UpdatedAt,
+		&point.Occupied,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	point.ZoneID = zoneID.String
+	if latitude.Valid && longitude.Valid {
+		point.Address.Latitude = &latitude.Float64
+		point.Address.Longitude = &longitude.Float64
+	}
+
+	return &point, nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var ErrProofExists = errors.New("proof of delivery already recorded")
+
+// CreateProof records the proof for a delivery that is already delivered.
+// Proofs captured at the door are recorded by UpdateDelivery together with the
+// DELIVERED status.
+func (r *PostgresRepository) CreateProof(ctx context.Context, proof *model.ProofOfDelivery) error {
+	return insertProof(ctx, r.db, proof)
+}
+
+func insertProof(ctx context.Context, db execer, proof *model.ProofOfDelivery) error {
+	proof.ID = uuid.New().String()
+
+	query := `
+		INSERT INTO delivery_proofs (
+			id, delivery_id, recipient_name, signature_key, signature_content_type,
+			photo_key, photo_content_type, latitude, longitude, otp_result,
+			captured_by, captured_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	_, err := db.ExecContext(ctx, query,
+		proof.ID, proof.DeliveryID, proof.RecipientName,
+		nullString(proof.SignatureKey), nullString(proof.SignatureContentType),
+		nullString(proof.PhotoKey), nullString(proof.PhotoContentType),
+		proof.Latitude, proof.Longitude, proof.OTPResult,
+		proof.CapturedBy, proof.CapturedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return ErrProofExists
+		}
+		return fmt.Errorf("error creating proof of delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) GetProof(ctx context.Context, deliveryID string) (*model.ProofOfDelivery, error) {
+	query := `
+		SELECT 
+			id, delivery_id, recipient_name, signature_key, signature_content_type,
+			photo_key, photo_content_type, latitude, longitude, otp_result,
+			captured_by, captured_at
+		FROM 
+			delivery_proofs
+		WHERE 
+			delivery_id = $1`
+
+	var proof model.ProofOfDelivery
+	var signatureKey, signatureType, photoKey, photoType sql.NullString
+	var lat, lng sql.NullFloat64
+
+	err := r.db.QueryRowContext(ctx, query, deliveryID).Scan(
+		&proof.ID, &proof.DeliveryID, &proof.RecipientName, &signatureKey, &signatureType,
+		&photoKey, &photoType, &lat, &lng, &proof.OTPResult,
+		&proof.CapturedBy, &proof.CapturedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No proof found
+		}
+		return nil, err
+	}
+
+	proof.SignatureKey = signatureKey.String
+	proof.SignatureContentType = signatureType.String
+	proof.PhotoKey = photoKey.String
+	proof.PhotoContentType = photoType.String
+	proof.HasSignature = signatureKey.Valid
+	proof.HasPhoto = photoKey.Valid
+	if lat.Valid && lng.Valid {
+		proof.Latitude = &lat.Float64
+		proof.Longitude = &lng.Float64
+	}
+
+	return &proof, nil
+}
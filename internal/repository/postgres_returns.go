@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// ListReturns returns the return deliveries created for a delivery, oldest
+// first.
+func (r *PostgresRepository) ListReturns(ctx context.Context, parentDeliveryID string) ([]*model.Delivery, error) {
+	query := deliverySelect + `
+		WHERE 
+			d.parent_delivery_id = $1
+		ORDER BY 
+			d.created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, parentDeliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*model.Delivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// AssignCourier sets the courier on a delivery. It reports false if the
+// delivery does not exist, and ErrDeliveryLocked if a closed manifest holds
+// it.
+func (r *PostgresRepository) AssignCourier(ctx context.Context, deliveryID, courierID string) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE deliveries SET courier_id = $2, updated_at = $3 WHERE id = $1 AND manifest_id IS NULL`,
+		deliveryID, courierID, time.Now(),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected > 0 {
+		return true, nil
+	}
+
+	var locked bool
+	err = r.db.QueryRowContext(ctx,
+		`SELECT manifest_id IS NOT NULL FROM deliveries WHERE id = $1`, deliveryID,
+	).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if locked {
+		return false, ErrDeliveryLocked
+	}
+	return false, nil
+}
+
+// SaveRoute stores a planned route, with the deliveries left off it, and feeds
+// each stop's planned arrival into its delivery's ETA, recording an ETA
+// revision per stop.
+func (r *PostgresRepository) SaveRoute(ctx context.Context, route *model.Route, statuses map[string]string) error {
+	route.ID = uuid.New().String()
+	route.CreatedAt = time.Now()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO routes (
+			id, courier_id, start_latitude, start_longitude, start_at, distance_meters, unassigned, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		route.ID, route.CourierID, route.StartLatitude, route.StartLongitude,
+		route.StartAt, route.DistanceMeters, pq.Array(route.Unassigned), route.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("error creating route: %w", err)
+	}
+
+	stopQuery := `
+		INSERT INTO route_stops (
+			route_id, delivery_id, sequence, planned_arrival, distance_meters
+		) VALUES ($1, $2, $3, $4, $5)`
+
+	for _, stop := range route.Stops {
+		stop.RouteID = route.ID
+		_, err = tx.ExecContext(ctx, stopQuery,
+			stop.RouteID, stop.DeliveryID, stop.Sequence, stop.PlannedArrival, stop.DistanceMeters,
+		)
+		if err != nil {
+			return fmt.Errorf("error creating route stop: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`UPDATE deliveries SET estimated_delivery_time = $2, updated_at = $3 WHERE id = $1`,
+			stop.DeliveryID, stop.PlannedArrival, route.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("error updating delivery estimate: %w", err)
+		}
+
+		err = insertETARevision(ctx, tx, stop.DeliveryID, stop.PlannedArrival, statuses[stop.DeliveryID], route.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// PlannedArrival returns when the latest route of a delivery's courier has
+// them arriving at the delivery, or nil if that route does not include it.
+func (r *PostgresRepository) PlannedArrival(ctx context.Context, deliveryID string) (*time.Time, error) {
+	query := `
+		SELECT 
+			s.planned_arrival
+		FROM 
+			deliveries d
+		JOIN 
+			routes rt ON rt.id = (
+				SELECT id FROM routes WHERE courier_id = d.courier_id ORDER BY created_at DESC LIMIT 1
+			)
+		JOIN 
+			route_stops s ON s.route_id = rt.id AND s.delivery_id = d.id
+		WHERE 
+			d.id = $1`
+
+	var arrival time.Time
+	err := r.db.QueryRowContext(ctx, query, deliveryID).Scan(&arrival)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not on a route
+		}
+		return nil, err
+	}
+	return &arrival, nil
+}
+
+// GetLatestRoute returns the most recently planned route for a courier.
+func (r *PostgresRepository) GetLatestRoute(ctx context.Context, courierID string) (*model.Route, error) {
+	query := routeSelect + `
+		WHERE 
+			courier_id = $1
+		ORDER BY 
+			created_at DESC
+		LIMIT 1`
+
+	return r.getRoute(ctx, query, courierID)
+}
+
+func (r *PostgresRepository) GetRoute(ctx context.Context, id string) (*model.Route, error) {
+	query := routeSelect + `
+		WHERE 
+			id = $1`
+
+	return r.getRoute(ctx, query, id)
+}
+
+const routeSelect = `
+		SELECT 
+			id, courier_id, start_latitude, start_longitude, start_at, distance_meters, unassigned, created_at
+		FROM 
+			routes`
+
+// getRoute loads the route selected by query along with its stops.
+func (r *PostgresRepository) getRoute(ctx context.Context, query string, args ...interface{}) (*model.Route, error) {
+	var route model.Route
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(
+		&route.ID, &route.CourierID, &route.StartLatitude, &route.StartLongitude,
+		&route.StartAt, &route.DistanceMeters, pq.Array(&route.Unassigned), &route.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No route found
+		}
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT 
+			route_id, delivery_id, sequence, planned_arrival, distance_meters
+		FROM 
+			route_stops
+		WHERE 
+			route_id = $1
+		ORDER BY 
+			sequence ASC`,
+		route.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stop model.RouteStop
+		err := rows.Scan(&stop.RouteID, &stop.DeliveryID, &stop.Sequence, &stop.PlannedArrival, &stop.DistanceMeters)
+		if err != nil {
+			return nil, err
+		}
+		route.Stops = append(route.Stops, &stop)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &route, nil
+}
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSlotFull            = errors.New("slot is fully booked")
+	ErrSlotStarted         = errors.New("slot has already started")
+	ErrReservationNotFound = errors.New("slot reservation not found")
+	ErrReservationExpired  = errors.New("slot reservation is no longer held")
+)
+
+// EnsureSlots inserts slots that do not exist yet for their zone and start
+// time; existing slots keep their capacity.
+func (r *PostgresRepository) EnsureSlots(ctx context.Context, slots []*model.DeliverySlot) error {
+	query := `
+		INSERT INTO delivery_slots (
+			id, zone, starts_at, ends_at, capacity
+		) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (zone, starts_at) DO NOTHING`
+
+	for _, slot := range slots {
+		_, err := r.db.ExecContext(ctx, query,
+			uuid.New().String(), slot.Zone, slot.StartsAt, slot.EndsAt, slot.Capacity,
+		)
+		if err != nil {
+			return fmt.Errorf("error creating delivery slot: %w", err)
+		}
+	}
+	return nil
+}
+
+// SetSlotCapacity overrides the capacity of a single slot.
+func (r *PostgresRepository) SetSlotCapacity(ctx context.Context, slotID string, capacity int) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE delivery_slots SET capacity = $2 WHERE id = $1`, slotID, capacity)
+	return err
+}
+
+// ListSlots returns the zone's slots starting in [from, to) with the number of
+// places not taken by confirmed or still-held reservations.
+func (r *PostgresRepository) ListSlots(ctx context.Context, zone string, from, to time.Time) ([]*model.DeliverySlot, error) {
+	query := `
+		SELECT 
+			s.id, s.zone, s.starts_at, s.ends_at, s.capacity,
+			s.capacity - COUNT(r.id) FILTER (
+				WHERE r.status = 'CONFIRMED' OR (r.status = 'HELD' AND r.expires_at > $4)
+			)
+		FROM 
+			delivery_slots s
+		LEFT JOIN 
+			slot_reservations r ON r.slot_id = s.id
+		WHERE 
+			s.zone = $1 AND s.starts_at >= $2 AND s.starts_at < $3
+		GROUP BY 
+			s.id
+		ORDER BY 
+			s.starts_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, zone, from, to, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var slots []*model.DeliverySlot
+
+	for rows.Next() {
+		var slot model.DeliverySlot
+		err := rows.Scan(
+			&slot.ID, &slot.Zone, &slot.StartsAt, &slot.EndsAt, &slot.Capacity, &slot.Available,
+		)
+		if err != nil {
+			return nil, err
+		}
+		slots = append(slots, &slot)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return slots, nil
+}
+
+// ReserveSlot holds a place in the slot until the hold expires. The slot row is
+// locked so concurrent reservations cannot overbook it, and slots that have
+// started cannot be reserved.
+func (r *PostgresRepository) ReserveSlot(ctx context.Context, slotID, heldBy string, hold time.Duration) (*model.SlotReservation, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	var slot model.DeliverySlot
+	var upcoming bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT id, zone, starts_at, ends_at, capacity, starts_at > $2 FROM delivery_slots WHERE id = $1 FOR UPDATE`,
+		slotID, now,
+	).Scan(&slot.ID, &slot.Zone, &slot.StartsAt, &slot.EndsAt, &slot.Capacity, &upcoming)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No slot found
+		}
+		return nil, err
+	}
+	if !upcoming {
+		return nil, ErrSlotStarted
+	}
+
+	var taken int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM slot_reservations
+		WHERE slot_id = $1 AND (status = 'CONFIRMED' OR (status = 'HELD' AND expires_at > $2))`,
+		slotID, now,
+	).Scan(&taken)
+	if err != nil {
+		return nil, err
+	}
+	if taken >= slot.Capacity {
+		return nil, ErrSlotFull
+	}
+	slot.Available = slot.Capacity - taken - 1
+
+	reservation := &model.SlotReservation{
+		ID:        uuid.New().String(),
+		SlotID:    slotID,
+		Status:    model.ReservationHeld,
+		HeldBy:    heldBy,
+		ExpiresAt: now.Add(hold),
+		CreatedAt: now,
+		Slot:      &slot,
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO slot_reservations (
+			id, slot_id, status, held_by, expires_at, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6)`,
+		reservation.ID, reservation.SlotID, reservation.Status, nullString(reservation.HeldBy),
+		reservation.ExpiresAt, reservation.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating slot reservation: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+func (r *PostgresRepository) GetSlotReservation(ctx context.Context, id string) (*model.SlotReservation, error) {
+	query := `
+		SELECT 
+			r.id, r.slot_id, r.delivery_id, r.status, r.held_by, r.expires_at, r.created_at,
+			s.zone, s.starts_at, s.ends_at, s.capacity
+		FROM 
+			slot_reservations r
+		JOIN 
+			delivery_slots s ON s.id = r.slot_id
+		WHERE 
+			r.id = $1`
+
+	var reservation model.SlotReservation
+	var slot model.DeliverySlot
+	var deliveryID, heldBy sql.NullString
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&reservation.ID, &reservation.SlotID, &deliveryID, &reservation.Status, &heldBy,
+		&reservation.ExpiresAt, &reservation.CreatedAt,
+		&slot.Zone, &slot.StartsAt, &slot.EndsAt, &slot.Capacity,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No reservation found
+		}
+		return nil, err
+	}
+
+	reservation.DeliveryID = deliveryID.String
+	reservation.HeldBy = heldBy.String
+	slot.ID = reservation.SlotID
+	reservation.Slot = &slot
+
+	return &reservation, nil
+}
+
+// ReleaseSlotReservation gives a held place back to the slot. Confirmed
+// places are released only by cancelling their delivery, which also clears
+// the delivery's reference to them.
+func (r *PostgresRepository) ReleaseSlotReservation(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE slot_reservations SET status = $2 WHERE id = $1 AND status = 'HELD'`,
+		id, model.ReservationReleased,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrReservationNotFound
+	}
+	return nil
+}
+
+// confirmSlotReservation binds a held reservation to a delivery. It fails if
+// the hold has expired or was released in the meantime.
+func confirmSlotReservation(ctx context.Context, db execer, reservationID, deliveryID string) error {
+	result, err := db.ExecContext(ctx, `
+		UPDATE slot_reservations 
+		SET status = $3, delivery_id = $2 
+		WHERE id = $1 AND status = 'HELD' AND expires_at > $4`,
+		reservationID, deliveryID, model.ReservationConfirmed, time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("error confirming slot reservation: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrReservationExpired
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var ErrZoneExists = errors.New("a zone with this name already exists")
+
+const zoneSelect = `
+		SELECT 
+			id, name, country, postal_codes, postal_prefixes, boundary, active, created_at, updated_at
+		FROM 
+			zones`
+
+func (r *PostgresRepository) CreateZone(ctx context.Context, zone *model.Zone) (*model.Zone, error) {
+	zone.ID = uuid.New().String()
+	zone.CreatedAt = time.Now()
+	zone.UpdatedAt = zone.CreatedAt
+
+	query := `
+		INSERT INTO zones (
+			id, name, country, postal_codes, postal_prefixes, boundary, active, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		zone.ID, zone.Name, zone.Country, pq.Array(zone.PostalCodes), pq.Array(zone.PostalPrefixes),
+		nullJSON(zone.Boundary), zone.Active, zone.CreatedAt, zone.UpdatedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, ErrZoneExists
+		}
+		return nil, fmt.Errorf("error creating zone: %w", err)
+	}
+
+	return zone, nil
+}
+
+func (r *PostgresRepository) UpdateZone(ctx context.Context, zone *model.Zone) (*model.Zone, error) {
+	zone.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE zones 
+		SET name = $2, country = $3, postal_codes = $4, postal_prefixes = $5, 
+			boundary = $6, active = $7, updated_at = $8
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query,
+		zone.ID, zone.Name, zone.Country, pq.Array(zone.PostalCodes), pq.Array(zone.PostalPrefixes),
+		nullJSON(zone.Boundary), zone.Active, zone.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error updating zone: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, nil // No zone found
+	}
+
+	return r.GetZone(ctx, zone.ID)
+}
+
+func (r *PostgresRepository) GetZone(ctx context.Context, id string) (*model.Zone, error) {
+	zone, err := scanZone(r.db.QueryRowContext(ctx, zoneSelect+` WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No zone found
+		}
+		return nil, err
+	}
+	return zone, nil
+}
+
+// ListZones implements zone.Source.
+func (r *PostgresRepository) ListZones(ctx context.Context, activeOnly bool) ([]*model.Zone, error) {
+	query := zoneSelect + `
+		WHERE 
+			($1 = FALSE OR active = TRUE)
+		ORDER BY 
+			name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var zones []*model.Zone
+
+	for rows.Next() {
+		zone, err := scanZone(rows)
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, zone)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
+
+func scanZone(row rowScanner) (*model.Zone, error) {
+	var zone model.Zone
+	var boundary []byte
+
+	err := row.Scan(
+		&zone.ID, &zone.Name, &zone.Country,
+		pq.Array(&zone.PostalCodes), pq.Array(&zone.PostalPrefixes),
+		&boundary, &zone.Active, &zone.CreatedAt, &zone.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(boundary) > 0 {
+		zone.Boundary = boundary
+	}
+
+	return &zone, nil
+}
+
+func nullJSON(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
@@ -116,4 +116,19 @@ func (c *RedisCache) GetCachedDeliveryEvents(ctx context.Context, deliveryID str
 	}
 
 	return events, nil
-}
\ No newline at end of file
+}
+// InvalidateDeliveryEvents drops the cached event trail after new events are
+// recorded.
+func (c *RedisCache) InvalidateDeliveryEvents(ctx context.Context, deliveryID string) error {
+	key := fmt.Sprintf("delivery_events:%s", deliveryID)
+	return c.client.Del(ctx, key).Err()
+}
+
+// InvalidateDelivery drops every cache entry for a delivery.
+func (c *RedisCache) InvalidateDelivery(ctx context.Context, delivery *model.Delivery) error {
+	return c.client.Del(ctx,
+		fmt.Sprintf("delivery:%s", delivery.ID),
+		fmt.Sprintf("tracking:%s", delivery.TrackingNumber),
+		fmt.Sprintf("delivery_events:%s", delivery.ID),
+	).Err()
+}
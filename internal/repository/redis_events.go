@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// DomainEventsChannel is the Redis channel domain events are published on.
+const DomainEventsChannel = "delivery:domain-events"
+
+// PublishEvent publishes a domain event to subscribers of DomainEventsChannel.
+func (c *RedisCache) PublishEvent(ctx context.Context, event model.DomainEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return c.client.Publish(ctx, DomainEventsChannel, data).Err()
+}
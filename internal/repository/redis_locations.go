@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/go-redis/redis/v8"
+)
+
+// setIfNewer stores the location only if it was recorded after the one
+// already cached, so out-of-order pings never move a courier backwards.
+var setIfNewer = redis.NewScript(`
+local current = redis.call('HGET', KEYS[1], 'ts')
+if current and tonumber(current) >= tonumber(ARGV[1]) then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'ts', ARGV[1], 'data', ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
+// SetCourierLocation updates the courier's last known position. It reports
+// whether the position was newer than the cached one.
+func (c *RedisCache) SetCourierLocation(ctx context.Context, loc *model.CourierLocation, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf("courier_location:%s", loc.CourierID)
+	data, err := json.Marshal(loc)
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := setIfNewer.Run(ctx, c.client, []string{key},
+		loc.RecordedAt.UnixNano(), data, ttl.Milliseconds(),
+	).Int()
+	if err != nil {
+		return false, err
+	}
+	return updated == 1, nil
+}
+
+func (c *RedisCache) GetCourierLocation(ctx context.Context, courierID string) (*model.CourierLocation, error) {
+	key := fmt.Sprintf("courier_location:%s", courierID)
+	data, err := c.client.HGet(ctx, key, "data").Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil // Cache miss
+		}
+		return nil, err
+	}
+
+	var loc model.CourierLocation
+	if err := json.Unmarshal(data, &loc); err != nil {
+		return nil, err
+	}
+
+	return &loc, nil
+}
+
+// GetPersistedLocation returns the last ping written to the location history,
+// which the downsampler measures new pings against.
+func (c *RedisCache) GetPersistedLocation(ctx context.Context, courierID string) (*model.CourierLocation, error) {
+	key := fmt.Sprintf("courier_location_persisted:%s", courierID)
+	data, err := c.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil // Cache miss
+		}
+		return nil, err
+	}
+
+	var loc model.CourierLocation
+	if err := json.Unmarshal(data, &loc); err != nil {
+		return nil, err
+	}
+
+	return &loc, nil
+}
+
+func (c *RedisCache) SetPersistedLocation(ctx context.Context, loc *model.CourierLocation) error {
+	key := fmt.Sprintf("courier_location_persisted:%s", loc.CourierID)
+	data, err := json.Marshal(loc)
+	if err != nil {
+		return err
+	}
+
+	return c.client.Set(ctx, key, data, 24*time.Hour).Err()
+}
+
+// GetGeofenceState returns the IDs of the fences the courier is currently inside.
+func (c *RedisCache) GetGeofenceState(ctx context.Context, courierID string) (map[string]bool, error) {
+	key := fmt.Sprintf("geofence_state:%s", courierID)
+	ids, err := c.client.SMembers(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	inside := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		inside[id] = true
+	}
+	return inside, nil
+}
+
+func (c *RedisCache) SetGeofenceState(ctx context.Context, courierID string, inside map[string]bool, ttl time.Duration) error {
+	key := fmt.Sprintf("geofence_state:%s", courierID)
+
+	pipe := c.client.TxPipeline()
+	pipe.Del(ctx, key)
+	if len(inside) > 0 {
+		ids := make([]interface{}, 0, len(inside))
+		for id := range inside {
+			ids = append(ids, id)
+		}
+		pipe.SAdd(ctx, key, ids...)
+		pipe.Expire(ctx, key, ttl)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
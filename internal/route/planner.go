@@ -0,0 +1,167 @@
+package route
+
+import (
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/geo"
+)
+
+// Stop is a delivery to visit. Window is optional; a courier arriving early
+// waits for it to open, and arriving after it closes is infeasible.
+type Stop struct {
+	ID          string
+	Point       geo.Point
+	WindowStart time.Time
+	WindowEnd   time.Time
+	Demand      int
+}
+
+type Options struct {
+	Start       geo.Point
+	StartAt     time.Time
+	SpeedKmh    float64
+	Detour      float64       // road distance over straight-line distance
+	ServiceTime time.Duration // time spent at each stop
+	Capacity    int           // total demand the courier can carry; 0 means unlimited
+}
+
+type PlannedStop struct {
+	Stop     Stop
+	Arrival  time.Time
+	Distance float64 // meters from the previous stop
+}
+
+type Plan struct {
+	Stops      []PlannedStop
+	Unassigned []Stop // stops that could not be fitted
+	Distance   float64
+}
+
+// Build orders the stops with a nearest-neighbor construction that respects
+// capacity and time windows, then improves the order with 2-opt while keeping
+// every window satisfied.
+func Build(stops []Stop, opts Options) Plan {
+	order, unassigned := nearestNeighbor(stops, opts)
+	order = twoOpt(order, opts)
+
+	plan := Plan{Unassigned: unassigned}
+	schedule(order, opts, func(s Stop, arrival time.Time, dist float64) bool {
+		// Early arrivals wait for the window, so the stop is made when it opens
+		if arrival.Before(s.WindowStart) {
+			arrival = s.WindowStart
+		}
+		plan.Stops = append(plan.Stops, PlannedStop{Stop: s, Arrival: arrival, Distance: dist})
+		plan.Distance += dist
+		return true
+	})
+	return plan
+}
+
+func nearestNeighbor(stops []Stop, opts Options) ([]Stop, []Stop) {
+	remaining := make([]Stop, len(stops))
+	copy(remaining, stops)
+
+	var order []Stop
+	pos, now, load := opts.Start, opts.StartAt, 0
+
+	for len(remaining) > 0 {
+		best, bestDist := -1, 0.0
+		var bestArrival time.Time
+
+		for i, s := range remaining {
+			if opts.Capacity > 0 && load+s.Demand > opts.Capacity {
+				continue
+			}
+			arrival := now.Add(travel(pos, s.Point, opts))
+			if !s.WindowEnd.IsZero() && arrival.After(s.WindowEnd) {
+				continue
+			}
+			d := geo.Distance(pos, s.Point)
+			if best < 0 || d < bestDist {
+				best, bestDist, bestArrival = i, d, arrival
+			}
+		}
+		if best < 0 {
+			break
+		}
+
+		s := remaining[best]
+		order = append(order, s)
+		load += s.Demand
+		pos, now = s.Point, departure(s, bestArrival, opts)
+		remaining = append(remaining[:best], remaining[best+1:]...)
+	}
+
+	return order, remaining
+}
+
+// twoOpt reverses segments of the order while that shortens the route and the
+// schedule stays feasible.
+func twoOpt(order []Stop, opts Options) []Stop {
+	best := order
+	bestDist := routeDistance(best, opts.Start)
+
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < len(best)-1; i++ {
+			for j := i + 1; j < len(best); j++ {
+				candidate := reverse(best, i, j)
+				d := routeDistance(candidate, opts.Start)
+				if d < bestDist-1e-6 && feasible(candidate, opts) {
+					best, bestDist, improved = candidate, d, true
+				}
+			}
+		}
+	}
+
+	return best
+}
+
+func reverse(order []Stop, i, j int) []Stop {
+	out := make([]Stop, len(order))
+	copy(out, order)
+	for ; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+func feasible(order []Stop, opts Options) bool {
+	return schedule(order, opts, func(s Stop, arrival time.Time, dist float64) bool {
+		return s.WindowEnd.IsZero() || !arrival.After(s.WindowEnd)
+	})
+}
+
+// schedule walks the order computing arrival times and stops early if visit
+// returns false.
+func schedule(order []Stop, opts Options, visit func(s Stop, arrival time.Time, dist float64) bool) bool {
+	pos, now := opts.Start, opts.StartAt
+	for _, s := range order {
+		arrival := now.Add(travel(pos, s.Point, opts))
+		if !visit(s, arrival, geo.Distance(pos, s.Point)*opts.Detour) {
+			return false
+		}
+		pos, now = s.Point, departure(s, arrival, opts)
+	}
+	return true
+}
+
+func departure(s Stop, arrival time.Time, opts Options) time.Time {
+	if arrival.Before(s.WindowStart) {
+		arrival = s.WindowStart
+	}
+	return arrival.Add(opts.ServiceTime)
+}
+
+func travel(from, to geo.Point, opts Options) time.Duration {
+	return geo.TravelTime(from, to, opts.SpeedKmh, opts.Detour)
+}
+
+func routeDistance(order []Stop, start geo.Point) float64 {
+	total, pos := 0.0, start
+	for _, s := range order {
+		total += geo.Distance(pos, s.Point)
+		pos = s.Point
+	}
+	return total
+}
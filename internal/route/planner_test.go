@@ -0,0 +1,157 @@
+package route
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/geo"
+)
+
+var startAt = time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)
+
+// at returns a stop the given number of kilometers due north of the start.
+func at(id string, km float64) Stop {
+	return Stop{ID: id, Point: geo.Point{Lat: km / 111.19508}, Demand: 1}
+}
+
+func testOptions() Options {
+	return Options{StartAt: startAt, SpeedKmh: 60, Detour: 1, ServiceTime: 5 * time.Minute}
+}
+
+func ids(stops []Stop) []string {
+	var out []string
+	for _, s := range stops {
+		out = append(out, s.ID)
+	}
+	return out
+}
+
+func plannedIDs(plan Plan) []string {
+	var out []string
+	for _, ps := range plan.Stops {
+		out = append(out, ps.Stop.ID)
+	}
+	return out
+}
+
+func TestBuild(t *testing.T) {
+	windowed := func(s Stop, start, end time.Duration) Stop {
+		if start > 0 {
+			s.WindowStart = startAt.Add(start)
+		}
+		s.WindowEnd = startAt.Add(end)
+		return s
+	}
+
+	tests := []struct {
+		name       string
+		stops      []Stop
+		capacity   int
+		want       []string
+		unassigned []string
+	}{
+		{
+			name:  "no stops",
+			stops: nil,
+		},
+		{
+			name:  "nearest first",
+			stops: []Stop{at("c", 3), at("a", 1), at("b", 2)},
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name:       "capacity leaves the farthest off",
+			stops:      []Stop{at("c", 3), at("a", 1), at("b", 2)},
+			capacity:   2,
+			want:       []string{"a", "b"},
+			unassigned: []string{"c"},
+		},
+		{
+			name: "window reachable after a nearer stop",
+			stops: []Stop{
+				windowed(at("far", 10), 0, 20*time.Minute),
+				at("near", 1),
+			},
+			want: []string{"near", "far"},
+		},
+		{
+			name: "unreachable window is left off",
+			stops: []Stop{
+				at("near", 1),
+				windowed(at("far", 10), 0, 5*time.Minute),
+			},
+			want:       []string{"near"},
+			unassigned: []string{"far"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testOptions()
+			opts.Capacity = tt.capacity
+			plan := Build(tt.stops, opts)
+			if got := plannedIDs(plan); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("stops = %v, want %v", got, tt.want)
+			}
+			if got := ids(plan.Unassigned); !reflect.DeepEqual(got, tt.unassigned) {
+				t.Errorf("unassigned = %v, want %v", got, tt.unassigned)
+			}
+		})
+	}
+}
+
+func TestBuildSchedule(t *testing.T) {
+	late := at("b", 2)
+	late.WindowStart = startAt.Add(time.Hour)
+	plan := Build([]Stop{at("a", 1), late}, testOptions())
+
+	if len(plan.Stops) != 2 {
+		t.Fatalf("planned %d stops, want 2", len(plan.Stops))
+	}
+	// 1 km at 60 km/h
+	if want := startAt.Add(time.Minute); !plan.Stops[0].Arrival.Round(time.Second).Equal(want) {
+		t.Errorf("first arrival = %v, want %v", plan.Stops[0].Arrival, want)
+	}
+	// Arriving early, the courier waits for the window to open
+	if !plan.Stops[1].Arrival.Equal(late.WindowStart) {
+		t.Errorf("second arrival = %v, want %v", plan.Stops[1].Arrival, late.WindowStart)
+	}
+	if d := plan.Distance; d < 1999 || d > 2001 {
+		t.Errorf("distance = %.1f, want 2000", d)
+	}
+}
+
+func TestTwoOpt(t *testing.T) {
+	tests := []struct {
+		name  string
+		order []Stop
+		want  []string
+	}{
+		{
+			name:  "untangles a crossing",
+			order: []Stop{at("c", 3), at("a", 1), at("b", 2)},
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "keeps an optimal order",
+			order: []Stop{at("a", 1), at("b", 2), at("c", 3)},
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name: "keeps windows satisfied",
+			order: func() []Stop {
+				far := at("far", 10)
+				far.WindowEnd = startAt.Add(11 * time.Minute)
+				return []Stop{far, at("near", 1)}
+			}(),
+			want: []string{"far", "near"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ids(twoOpt(tt.order, testOptions())); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("twoOpt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
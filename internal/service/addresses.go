@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/address"
+	"github.com/bharathbbg/delivery-service/internal/geo"
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/repository"
+)
+
+// SetAddressVerifier replaces the stub verifier with an external one.
+func (s *DeliveryService) SetAddressVerifier(verifier address.Verifier) {
+	s.addresses = address.NewPipeline(verifier)
+}
+
+// SetGeocoder replaces the default empty geocoder.
+func (s *DeliveryService) SetGeocoder(geocoder geo.Geocoder) {
+	s.geocoder = geocoder
+}
+
+// ValidateAddress runs the address pipeline without creating anything.
+func (s *DeliveryService) ValidateAddress(ctx context.Context, addr model.Address) (model.Address, error) {
+	return s.prepareAddress(ctx, addr)
+}
+
+// prepareAddress normalizes the address and fills in its coordinates when the
+// caller did not supply them.
+func (s *DeliveryService) prepareAddress(ctx context.Context, addr model.Address) (model.Address, error) {
+	addr, err := s.normalizeAddress(ctx, addr)
+	if err != nil {
+		return model.Address{}, err
+	}
+
+	if geo.PointOf(addr) == nil {
+		point, err := s.geocoder.Geocode(ctx, addr)
+		if err != nil {
+			return model.Address{}, err
+		}
+		if point != nil {
+			addr.Latitude, addr.Longitude = &point.Lat, &point.Lng
+		}
+	}
+
+	return addr, nil
+}
+
+// normalizeAddress runs the address pipeline and reports field errors as a
+// CodeInvalidAddress error.
+func (s *DeliveryService) normalizeAddress(ctx context.Context, addr model.Address) (model.Address, error) {
+	normalized, err := s.addresses.Process(ctx, addr)
+	if err != nil {
+		var validationErr *address.ValidationError
+		if errors.As(err, &validationErr) {
+			return model.Address{}, &Error{
+				Code:    CodeInvalidAddress,
+				Message: validationErr.Error(),
+				Fields:  validationErr.Errors,
+			}
+		}
+		return model.Address{}, err
+	}
+	return normalized, nil
+}
+
+// UpdateDeliveryAddress changes where a delivery goes. It is only allowed
+// before the delivery is out for delivery, and re-checks serviceability and
+// the ETA for the new address.
+func (s *DeliveryService) UpdateDeliveryAddress(ctx context.Context, req *model.UpdateAddressRequest) (*model.Delivery, error) {
+	if err := requireStaff(req.Actor, "change delivery addresses"); err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.redirectableDelivery(ctx, req.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate, normalize and geocode the new address
+	newAddress, err := s.prepareAddress(ctx, req.Address)
+	if err != nil {
+		return nil, err
+	}
+	deliveryZone, err := s.resolveZone(ctx, newAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.changeAddress(ctx, delivery, &model.AddressChange{
+		NewAddress: newAddress,
+		NewZoneID:  deliveryZone.ID,
+		Reason:     req.Reason,
+		ChangedBy:  req.Actor.ID,
+	})
+}
+
+// redirectableDelivery loads a delivery whose destination may still change.
+func (s *DeliveryService) redirectableDelivery(ctx context.Context, id string) (*model.Delivery, error) {
+	delivery, err := s.repo.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, &Error{Code: CodeNotFound, Message: "delivery not found"}
+	}
+	if err := requireInHouse(delivery, "redirect the delivery"); err != nil {
+		return nil, err
+	}
+	if !containsStatus(model.RedirectableStatuses, delivery.Status) {
+		return nil, &Error{Code: CodeInvalidTransition, Message: repository.ErrNotRedirectable.Error()}
+	}
+	return delivery, nil
+}
+
+// changeAddress moves a delivery to the destination in change after checking
+// the new zone against its slot and service level, and re-estimates its ETA.
+func (s *DeliveryService) changeAddress(ctx context.Context, delivery *model.Delivery, change *model.AddressChange) (*model.Delivery, error) {
+	if change.NewZoneID != delivery.ZoneID {
+		if delivery.SlotReservationID != "" {
+			return nil, &Error{Code: CodeInvalidZone, Message: "the reserved delivery slot does not cover the new address"}
+		}
+		if policy, ok := s.serviceLevels[delivery.ServiceLevel]; ok && len(policy.AllowedZones) > 0 {
+			newZone, err := s.repo.GetZone(ctx, change.NewZoneID)
+			if err != nil {
+				return nil, err
+			}
+			if newZone == nil || !containsZone(policy.AllowedZones, newZone.Name) {
+				return nil, &Error{Code: CodeNotServiceable, Message: "service_level " + delivery.ServiceLevel + " is not available for the new address"}
+			}
+		}
+	}
+
+	// Outbound deliveries finish at the hub serving the new zone
+	if change.NewZoneID != delivery.ZoneID && delivery.Type != model.DeliveryTypeReturn {
+		hub, err := s.lastMileHub(ctx, change.NewZoneID)
+		if err != nil {
+			return nil, err
+		}
+		if hub != nil {
+			change.LastMileHubID = hub.ID
+		}
+	}
+
+	// Re-estimate against the new destination
+	change.DeliveryID = delivery.ID
+	change.OldAddress = delivery.ShippingAddress
+	change.OldZoneID = delivery.ZoneID
+	delivery.ZoneID = change.NewZoneID
+	estimate, err := s.eta.Revise(ctx, s.etaRequest(delivery, time.Now()), delivery.EstimatedDeliveryTime)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.UpdateDeliveryAddress(ctx, change, estimate, delivery.Status)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotRedirectable) {
+			return nil, &Error{Code: CodeInvalidTransition, Message: err.Error()}
+		}
+		return nil, err
+	}
+
+	s.refreshCache(ctx, updated)
+	if err := s.cache.InvalidateDeliveryEvents(ctx, updated.ID); err != nil {
+		// log.Printf("Failed to invalidate delivery events cache: %v", err)
+	}
+
+	return updated, nil
+}
+
+func (s *DeliveryService) GetAddressHistory(ctx context.Context, deliveryID string, actor model.Actor) ([]*model.AddressChange, error) {
+	if _, err := s.authorizedDelivery(ctx, deliveryID, actor); err != nil {
+		return nil, err
+	}
+	return s.repo.ListAddressHistory(ctx, deliveryID)
+}
+
+func containsStatus(statuses []string, status string) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
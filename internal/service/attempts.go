@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/geo"
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// AttemptPolicy decides what happens after a failed delivery attempt.
+type AttemptPolicy struct {
+	MaxAttempts  int      // attempts before the delivery is returned to sender
+	ReturnOn     []string // reason codes that return to sender immediately
+	ReattemptGap int      // business days until the next attempt
+}
+
+func (s *DeliveryService) SetAttemptPolicy(policy AttemptPolicy) {
+	s.attempts = policy
+}
+
+// RecordFailedAttempt logs a failed attempt for a delivery that is out for
+// delivery, then either schedules the next attempt or returns the delivery to
+// sender according to the attempt policy.
+func (s *DeliveryService) RecordFailedAttempt(ctx context.Context, req *model.FailedAttemptRequest) (*model.Delivery, error) {
+	// Validate request
+	if !model.IsAttemptReason(req.ReasonCode) {
+		return nil, fmt.Errorf("unknown reason_code %q", req.ReasonCode)
+	}
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return nil, errors.New("latitude and longitude must be given together")
+	}
+	if req.Latitude != nil && !(geo.Point{Lat: *req.Latitude, Lng: *req.Longitude}).Valid() {
+		return nil, errors.New("coordinates are out of range")
+	}
+
+	delivery, err := s.authorizedDelivery(ctx, req.DeliveryID, req.Actor)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status != model.StatusOutForDelivery {
+		return nil, &Error{Code: CodeInvalidTransition, Message: "only deliveries out for delivery can fail an attempt"}
+	}
+
+	delivery, err = s.repo.RecordFailedAttempt(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.returnsToSender(delivery.AttemptCount, req.ReasonCode) {
+		return s.UpdateDelivery(ctx, &model.UpdateDeliveryRequest{
+			ID:          delivery.ID,
+			Status:      model.StatusReturnToSender,
+			Location:    "Recipient address",
+			Description: fmt.Sprintf("Returning to sender after %d failed attempt(s)", delivery.AttemptCount),
+		})
+	}
+
+	next := s.nextAttempt(delivery, time.Now())
+	if err := s.repo.ScheduleReattempt(ctx, delivery.ID, next, delivery.Status); err != nil {
+		return nil, err
+	}
+	delivery.NextAttemptAt = &next
+	delivery.EstimatedDeliveryTime = next
+
+	s.refreshCache(ctx, delivery)
+	if err := s.cache.InvalidateDeliveryEvents(ctx, delivery.ID); err != nil {
+		// log.Printf("Failed to invalidate delivery events cache: %v", err)
+	}
+
+	return delivery, nil
+}
+
+func (s *DeliveryService) returnsToSender(attempts int, reasonCode string) bool {
+	if s.attempts.MaxAttempts > 0 && attempts >= s.attempts.MaxAttempts {
+		return true
+	}
+	for _, code := range s.attempts.ReturnOn {
+		if code == reasonCode {
+			return true
+		}
+	}
+	return false
+}
+
+// nextAttempt is the end of the delivery window on the business day the
+// policy's gap away.
+func (s *DeliveryService) nextAttempt(delivery *model.Delivery, now time.Time) time.Time {
+	calendar := s.eta.Calendar()
+	day := calendar.NextBusinessDay(now)
+	if s.attempts.ReattemptGap > 1 {
+		day = calendar.AddBusinessDays(day, s.attempts.ReattemptGap-1)
+	}
+	return calendar.At(day, s.eta.Rule(delivery.ServiceLevel).DeliverBy)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func TestCancelDeliveryValidation(t *testing.T) {
+	staff := model.Actor{ID: "agent-7", Role: model.RoleSupport}
+
+	tests := []struct {
+		name string
+		req  model.CancelDeliveryRequest
+		want string
+	}{
+		{"no delivery", model.CancelDeliveryRequest{ReasonCode: model.CancelCustomerRequest, Actor: staff}, "delivery_id"},
+		{"unknown reason", model.CancelDeliveryRequest{DeliveryID: "d1", ReasonCode: "CHANGED_MIND", Actor: staff}, "reason_code"},
+		{"other without a note", model.CancelDeliveryRequest{DeliveryID: "d1", ReasonCode: model.CancelOther, Actor: staff}, "note"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := (&DeliveryService{}).CancelDelivery(context.Background(), &tt.req)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCancelDeliveryRequiresStaff(t *testing.T) {
+	for _, actor := range []model.Actor{{}, {ID: "courier-1", Role: model.RoleCourier}} {
+		_, err := (&DeliveryService{}).CancelDelivery(context.Background(), &model.CancelDeliveryRequest{
+			DeliveryID: "d1",
+			ReasonCode: model.CancelCustomerRequest,
+			Actor:      actor,
+		})
+		assertError(t, err, errForbidden)
+	}
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/bharathbbg/delivery-service/internal/carrier"
+	"github.com/bharathbbg/delivery-service/internal/geo"
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/repository"
+)
+
+// CarrierPolicy names the third-party carriers we hand deliveries to and the
+// rules for when we do.
+type CarrierPolicy struct {
+	Carriers map[string]carrier.Carrier
+	Router   carrier.Router
+}
+
+// SetCarrierPolicy installs the carriers and routing rules. Every carrier the
+// rules name must be configured.
+func (s *DeliveryService) SetCarrierPolicy(policy CarrierPolicy) error {
+	for _, name := range policy.Router.Carriers() {
+		if _, ok := policy.Carriers[name]; !ok {
+			return fmt.Errorf("carrier rules name unknown carrier %q", name)
+		}
+	}
+	s.carriers = policy
+	return nil
+}
+
+// deliveryZone resolves the zone of a shipping address. Addresses outside all
+// zones are accepted, with a nil zone, only when a carrier covers them.
+func (s *DeliveryService) deliveryZone(ctx context.Context, addr model.Address) (*model.Zone, error) {
+	if !s.carriers.Router.Covers() {
+		return s.resolveZone(ctx, addr)
+	}
+	return s.zones.Resolve(ctx, addr, geo.PointOf(addr))
+}
+
+// routeCarrier returns the carrier that makes a delivery to a zone at a
+// service level, or "" to make it in-house.
+func (s *DeliveryService) routeCarrier(z *model.Zone, serviceLevel string) string {
+	name := ""
+	if z != nil {
+		name = z.Name
+	}
+	return s.carriers.Router.Route(name, serviceLevel)
+}
+
+// validateCarrierDelivery turns away what carriers do not offer: our slots,
+// pickup points, recipient PINs and cash on delivery.
+func validateCarrierDelivery(req *model.CreateDeliveryRequest) error {
+	switch {
+	case req.SlotReservationID != "":
+		return errors.New("delivery slots are not available for this destination")
+	case req.PickupPointID != "":
+		return errors.New("pickup points are not available for this destination")
+	case req.RequiresVerification:
+		return errors.New("PIN verification is not available for this destination")
+	case req.CODAmountCents > 0:
+		return errors.New("cash on delivery is not available for this destination")
+	}
+	return nil
+}
+
+// createWithCarrier books a delivery with its carrier under a freshly
+// generated tracking number, then saves it. A booking we fail to save is
+// cancelled so the carrier does not deliver something we have no record of.
+func (s *DeliveryService) createWithCarrier(ctx context.Context, delivery *model.Delivery) (*model.Delivery, error) {
+	c := s.carriers.Carriers[delivery.Carrier]
+
+	for attempt := 1; ; attempt++ {
+		trackingNumber, err := s.trackNumbers.Generate()
+		if err != nil {
+			return nil, err
+		}
+		delivery.TrackingNumber = trackingNumber
+
+		booking, err := c.CreateShipment(ctx, &carrier.Shipment{
+			Reference:    delivery.TrackingNumber,
+			OrderID:      delivery.OrderID,
+			ServiceLevel: delivery.ServiceLevel,
+			Recipient:    delivery.ShippingAddress,
+			Parcels:      delivery.Parcels,
+		})
+		if err != nil {
+			return nil, carrierError(delivery.Carrier, err)
+		}
+		delivery.CarrierTrackingNumber = booking.TrackingNumber
+
+		saved, err := s.repo.CreateDelivery(ctx, delivery)
+		if err == nil {
+			return saved, nil
+		}
+		if cancelErr := c.CancelShipment(ctx, booking.TrackingNumber); cancelErr != nil {
+			// log.Printf("Failed to cancel unsaved carrier shipment %s: %v", booking.TrackingNumber, cancelErr)
+		}
+		if !errors.Is(err, repository.ErrTrackingNumberTaken) || attempt >= maxTrackingNumberAttempts {
+			return nil, err
+		}
+	}
+}
+
+// carrierCancellation returns the step that cancels a carrier delivery's
+// shipment, or nil for in-house deliveries. CancelDelivery runs it with the
+// delivery locked and checked as cancellable, so the carrier never drops a
+// shipment we still consider active, and the carrier refusing leaves our
+// delivery untouched. Shipments the carrier has already picked up stay with
+// them.
+func (s *DeliveryService) carrierCancellation(ctx context.Context, deliveryID string) (func() error, error) {
+	delivery, err := s.repo.GetDelivery(ctx, deliveryID)
+	if err != nil || delivery == nil || delivery.CarrierTrackingNumber == "" {
+		return nil, err
+	}
+	c, ok := s.carriers.Carriers[delivery.Carrier]
+	if !ok {
+		return nil, &Error{Code: CodeCarrierUnavailable, Message: fmt.Sprintf("carrier %q is not configured", delivery.Carrier)}
+	}
+
+	return func() error {
+		err := c.CancelShipment(ctx, delivery.CarrierTrackingNumber)
+		if errors.Is(err, carrier.ErrNotCancellable) {
+			return &Error{Code: CodeInvalidTransition, Message: delivery.Carrier + " can no longer cancel the delivery"}
+		}
+		if err != nil {
+			return carrierError(delivery.Carrier, err)
+		}
+		return nil
+	}, nil
+}
+
+// carrierLabel fetches the carrier's own label for a carrier delivery.
+func (s *DeliveryService) carrierLabel(ctx context.Context, delivery *model.Delivery, format string) ([]byte, error) {
+	if delivery.CarrierTrackingNumber == "" {
+		return nil, &Error{Code: CodeNotFound, Message: "delivery has not been booked with " + delivery.Carrier}
+	}
+	label, err := s.carriers.Carriers[delivery.Carrier].GetLabel(ctx, delivery.CarrierTrackingNumber, format)
+	if err != nil {
+		return nil, carrierError(delivery.Carrier, err)
+	}
+	return label, nil
+}
+
+// requireInHouse turns away changes to deliveries a carrier is making; they
+// have to be made with the carrier.
+func requireInHouse(delivery *model.Delivery, action string) error {
+	if delivery.Carrier == "" {
+		return nil
+	}
+	return &Error{Code: CodeInvalidTransition, Message: "cannot " + action + ": delivery is with " + delivery.Carrier}
+}
+
+// SyncCarrierTracking pulls a carrier delivery's tracking from its carrier.
+func (s *DeliveryService) SyncCarrierTracking(ctx context.Context, deliveryID string, actor model.Actor) (*model.Delivery, error) {
+	if err := requireStaff(actor, "sync carrier tracking"); err != nil {
+		return nil, err
+	}
+	delivery, err := s.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, &Error{Code: CodeNotFound, Message: "delivery not found"}
+	}
+	if delivery.CarrierTrackingNumber == "" {
+		return nil, &Error{Code: CodeInvalidTransition, Message: "delivery is not with a carrier"}
+	}
+
+	if _, err := s.syncCarrier(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return s.reloadCache(ctx, deliveryID), nil
+}
+
+// SyncCarrierShipments pulls tracking for every open carrier delivery and
+// returns how many updates were new. A carrier failing for one delivery does
+// not stop the others.
+func (s *DeliveryService) SyncCarrierShipments(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.ListCarrierDeliveries(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	var errs []error
+	for _, d := range deliveries {
+		recorded, err := s.syncCarrier(ctx, d)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("delivery %s: %w", d.ID, err))
+			continue
+		}
+		if recorded > 0 {
+			s.reloadCache(ctx, d.ID)
+		}
+		total += recorded
+	}
+	return total, errors.Join(errs...)
+}
+
+// syncCarrier records a delivery's new carrier updates as delivery events and
+// re-estimates its ETA if its status moved.
+func (s *DeliveryService) syncCarrier(ctx context.Context, delivery *model.Delivery) (int, error) {
+	c, ok := s.carriers.Carriers[delivery.Carrier]
+	if !ok {
+		return 0, fmt.Errorf("carrier %q is not configured", delivery.Carrier)
+	}
+	events, err := c.FetchTracking(ctx, delivery.CarrierTrackingNumber)
+	if err != nil {
+		return 0, carrierError(delivery.Carrier, err)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	writes := make([]repository.CarrierEventWrite, 0, len(events))
+	for _, e := range events {
+		writes = append(writes, repository.CarrierEventWrite{
+			Event: &model.DeliveryEvent{
+				Status:      e.Status,
+				Location:    e.Location,
+				Description: carrierDescription(delivery.Carrier, e),
+				Carrier:     delivery.Carrier,
+				Timestamp:   e.Timestamp,
+			},
+			CarrierEventID: e.ID,
+		})
+	}
+
+	recorded, err := s.repo.RecordCarrierEvents(ctx, delivery.ID, writes)
+	if err != nil || recorded == 0 {
+		return recorded, err
+	}
+
+	updated, err := s.repo.GetDelivery(ctx, delivery.ID)
+	if err != nil {
+		return recorded, err
+	}
+	if updated != nil && updated.Status != delivery.Status && !model.IsTerminal(updated.Status) {
+		if err := s.reviseETA(ctx, updated); err != nil {
+			// log.Printf("Failed to revise delivery ETA: %v", err)
+		}
+	}
+	return recorded, nil
+}
+
+// carrierDescription keeps the carrier's wording and code, so updates we
+// could not map to a status still make sense on the tracking page.
+func carrierDescription(name string, e carrier.Event) string {
+	if e.Description == "" {
+		return name + ": " + e.Code
+	}
+	return fmt.Sprintf("%s: %s (%s)", name, e.Description, e.Code)
+}
+
+func carrierError(name string, err error) error {
+	return &Error{Code: CodeCarrierUnavailable, Message: name + ": " + err.Error()}
+}
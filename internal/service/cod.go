@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// validateCOD checks and normalizes the cash on delivery terms of a new
+// delivery.
+func validateCOD(req *model.CreateDeliveryRequest) error {
+	if req.CODAmountCents < 0 {
+		return errors.New("cod_amount_cents cannot be negative")
+	}
+	if req.CODAmountCents == 0 {
+		req.CODCurrency = ""
+		return nil
+	}
+	if req.Type == model.DeliveryTypeReturn {
+		return errors.New("returns cannot be cash on delivery")
+	}
+	req.CODCurrency = strings.ToUpper(req.CODCurrency)
+	if len(req.CODCurrency) != 3 {
+		return errors.New("cod_currency is required with a COD amount")
+	}
+	return nil
+}
+
+// codCollection checks the payment taken when a delivery is handed over and
+// turns it into the collection to record. Deliveries without cash on delivery
+// need no payment and get no collection.
+func codCollection(d *model.Delivery, payment *model.CODPayment) (*model.CODCollection, error) {
+	if d.CODAmountCents == 0 {
+		if payment != nil {
+			return nil, errors.New("delivery is not cash on delivery")
+		}
+		return nil, nil
+	}
+	if payment == nil {
+		return nil, &Error{
+			Code:    CodeCODRequired,
+			Message: fmt.Sprintf("cash on delivery of %s %s must be collected", formatCents(d.CODAmountCents), d.CODCurrency),
+		}
+	}
+
+	switch payment.Method {
+	case model.PaymentCash, model.PaymentCard, model.PaymentMobile:
+	default:
+		return nil, errors.New("method must be CASH, CARD or MOBILE")
+	}
+	if payment.AmountCents <= 0 {
+		return nil, errors.New("amount_cents must be positive")
+	}
+	currency := strings.ToUpper(payment.Currency)
+	if currency == "" {
+		currency = d.CODCurrency
+	}
+	if currency != d.CODCurrency {
+		return nil, errors.New("cash on delivery is due in " + d.CODCurrency)
+	}
+
+	// Parcels collected at a pickup point are paid at the counter, not to
+	// the courier who dropped them off
+	courierID := d.CourierID
+	if d.Status == model.StatusAvailableForPickup {
+		courierID = ""
+	}
+
+	// A short or over payment is recorded as taken and surfaces in
+	// reconciliation
+	return &model.CODCollection{
+		DeliveryID:  d.ID,
+		CourierID:   courierID,
+		DueCents:    d.CODAmountCents,
+		AmountCents: payment.AmountCents,
+		Currency:    currency,
+		Method:      payment.Method,
+		Reference:   payment.Reference,
+	}, nil
+}
+
+func (s *DeliveryService) GetCODCollection(ctx context.Context, deliveryID string, actor model.Actor) (*model.CODCollection, error) {
+	if _, err := s.authorizedDelivery(ctx, deliveryID, actor); err != nil {
+		return nil, err
+	}
+
+	collection, err := s.repo.GetCODCollection(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil {
+		return nil, &Error{Code: CodeNotFound, Message: "no cash on delivery collected"}
+	}
+	return collection, nil
+}
+
+// RecordDeposit books cash a courier handed in, reducing what they hold.
+func (s *DeliveryService) RecordDeposit(ctx context.Context, req *model.DepositRequest) (*model.LedgerEntry, error) {
+	if err := requireStaff(req.Actor, "record cash deposits"); err != nil {
+		return nil, err
+	}
+	if req.CourierID == "" {
+		return nil, errors.New("courier_id is required")
+	}
+	if req.AmountCents <= 0 {
+		return nil, errors.New("amount_cents must be positive")
+	}
+	currency := strings.ToUpper(req.Currency)
+	if len(currency) != 3 {
+		return nil, errors.New("currency is required")
+	}
+
+	return s.repo.CreateLedgerEntry(ctx, &model.LedgerEntry{
+		CourierID:   req.CourierID,
+		Type:        model.LedgerDeposit,
+		AmountCents: -req.AmountCents,
+		Currency:    currency,
+		Reference:   req.Reference,
+		RecordedBy:  req.Actor.ID,
+	})
+}
+
+// GetCashLedger returns the cash a courier holds and their cash movements on
+// a date.
+func (s *DeliveryService) GetCashLedger(ctx context.Context, courierID, date string, actor model.Actor) (*model.CashLedger, error) {
+	if courierID == "" {
+		return nil, errors.New("courier_id is required")
+	}
+	if err := authorizeCourier(actor, courierID, "view other couriers' cash"); err != nil {
+		return nil, err
+	}
+	day, _, err := s.businessDay(date)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := s.repo.CashBalances(ctx, courierID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.ListLedgerEntries(ctx, courierID, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []*model.LedgerEntry{}
+	}
+
+	return &model.CashLedger{CourierID: courierID, Balances: balances, Entries: entries}, nil
+}
+
+// ReconcileCOD checks a courier's day: the COD due on their closed manifests
+// against what they collected, and the cash they collected against what they
+// deposited. It stores one reconciliation per currency, replacing any earlier
+// run for the day, so it can be repeated once discrepancies are fixed.
+func (s *DeliveryService) ReconcileCOD(ctx context.Context, req *model.ReconcileRequest) ([]*model.CODReconciliation, error) {
+	if err := requireStaff(req.Actor, "reconcile cash on delivery"); err != nil {
+		return nil, err
+	}
+	if req.CourierID == "" {
+		return nil, errors.New("courier_id is required")
+	}
+	day, date, err := s.businessDay(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	listed, err := s.repo.ListManifests(ctx, req.CourierID, date)
+	if err != nil {
+		return nil, err
+	}
+	var manifests []*model.Manifest
+	for _, m := range listed {
+		if m.Status != model.ManifestClosed {
+			continue
+		}
+		full, err := s.repo.GetManifest(ctx, m.ID)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, full)
+	}
+	if len(manifests) == 0 {
+		return nil, &Error{Code: CodeNotFound, Message: "courier has no closed manifests on " + date}
+	}
+
+	r := &reconciler{
+		courierID:  req.CourierID,
+		date:       date,
+		actorID:    req.Actor.ID,
+		now:        time.Now(),
+		byCurrency: make(map[string]*model.CODReconciliation),
+		due:        make(map[string]manifestDue),
+		handedOver: make(map[string]bool),
+	}
+	var deliveryIDs []string
+	for _, m := range manifests {
+		r.manifestIDs = append(r.manifestIDs, m.ID)
+		for _, item := range m.Items {
+			r.due[item.DeliveryID] = manifestDue{manifestID: m.ID, item: item}
+			deliveryIDs = append(deliveryIDs, item.DeliveryID)
+		}
+	}
+
+	// The courier owes the COD of what they handed over. Deliveries that did
+	// not go through, or were left at a pickup point and paid at the counter,
+	// are not owed.
+	for deliveryID, due := range r.due {
+		if due.item.CODDueCents == 0 {
+			continue
+		}
+		d, err := s.repo.GetDelivery(ctx, deliveryID)
+		if err != nil {
+			return nil, err
+		}
+		if d != nil && d.Status == model.StatusDelivered && d.PickupPointID == "" {
+			r.handedOver[deliveryID] = true
+		}
+	}
+
+	to := day.AddDate(0, 0, 1)
+	collections, err := s.repo.ListCODCollections(ctx, req.CourierID, day, to, deliveryIDs)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.ListLedgerEntries(ctx, req.CourierID, day, to)
+	if err != nil {
+		return nil, err
+	}
+
+	reconciliations := r.reconcile(collections, entries)
+	if err := s.repo.SaveReconciliations(ctx, req.CourierID, date, reconciliations); err != nil {
+		return nil, err
+	}
+	return reconciliations, nil
+}
+
+// ListDiscrepancies reports the discrepancies found between two dates,
+// optionally for one courier.
+func (s *DeliveryService) ListDiscrepancies(ctx context.Context, filter model.DiscrepancyFilter, actor model.Actor) ([]*model.CODDiscrepancy, error) {
+	if err := requireStaff(actor, "view COD discrepancies"); err != nil {
+		return nil, err
+	}
+	_, from, err := s.businessDay(filter.From)
+	if err != nil {
+		return nil, err
+	}
+	if filter.To == "" {
+		filter.To = from
+	}
+	if _, err := time.Parse("2006-01-02", filter.To); err != nil {
+		return nil, errors.New("to must be YYYY-MM-DD")
+	}
+	filter.From = from
+	if filter.To < filter.From {
+		return nil, errors.New("to cannot be before from")
+	}
+	return s.repo.ListDiscrepancies(ctx, filter)
+}
+
+// businessDay parses a YYYY-MM-DD date in the service's timezone, defaulting
+// to today, and returns its start along with the normalized date.
+func (s *DeliveryService) businessDay(date string) (time.Time, string, error) {
+	loc := s.eta.Calendar().Location()
+	if date == "" {
+		date = time.Now().In(loc).Format("2006-01-02")
+	}
+	day, err := time.ParseInLocation("2006-01-02", date, loc)
+	if err != nil {
+		return time.Time{}, "", errors.New("date must be YYYY-MM-DD")
+	}
+	return day, date, nil
+}
+
+type manifestDue struct {
+	manifestID string
+	item       *model.ManifestItem
+}
+
+// reconciler accumulates one courier's day, per currency.
+type reconciler struct {
+	courierID   string
+	date        string
+	actorID     string
+	now         time.Time
+	manifestIDs []string
+	byCurrency  map[string]*model.CODReconciliation
+	due         map[string]manifestDue // by delivery ID
+	handedOver  map[string]bool        // COD deliveries the courier delivered
+}
+
+func (r *reconciler) reconcile(collections []*model.CODCollection, entries []*model.LedgerEntry) []*model.CODReconciliation {
+	// Everything on the manifests the courier delivered with COD is due
+	for deliveryID, due := range r.due {
+		if r.handedOver[deliveryID] {
+			r.currency(due.item.CODCurrency).DueCents += due.item.CODDueCents
+		}
+	}
+
+	collected := make(map[string]bool)
+	for _, c := range collections {
+		// Paid at a pickup point counter, not to the courier
+		if c.CourierID == "" {
+			continue
+		}
+		collected[c.DeliveryID] = true
+		rec := r.currency(c.Currency)
+		rec.CollectedCents += c.AmountCents
+
+		due, ok := r.due[c.DeliveryID]
+		switch {
+		case !ok:
+			r.flag(rec, model.DiscrepancyUnmanifestedCollection, c.DeliveryID, "", 0, c.AmountCents)
+		case due.item.CODCurrency != c.Currency || due.item.CODDueCents != c.AmountCents:
+			r.flag(rec, model.DiscrepancyAmountMismatch, c.DeliveryID, due.manifestID, due.item.CODDueCents, c.AmountCents)
+		}
+	}
+	for deliveryID, due := range r.due {
+		if r.handedOver[deliveryID] && !collected[deliveryID] {
+			rec := r.currency(due.item.CODCurrency)
+			r.flag(rec, model.DiscrepancyMissingCollection, deliveryID, due.manifestID, due.item.CODDueCents, 0)
+		}
+	}
+
+	// Cash collected during the day should all have been handed in
+	for _, e := range entries {
+		rec := r.currency(e.Currency)
+		switch e.Type {
+		case model.LedgerCollection:
+			rec.CashCollectedCents += e.AmountCents
+		case model.LedgerDeposit:
+			rec.DepositedCents -= e.AmountCents
+		}
+	}
+
+	reconciliations := make([]*model.CODReconciliation, 0, len(r.byCurrency))
+	for _, rec := range r.byCurrency {
+		if rec.CashCollectedCents != rec.DepositedCents {
+			r.flag(rec, model.DiscrepancyCashNotDeposited, "", "", rec.CashCollectedCents, rec.DepositedCents)
+		}
+		if len(rec.Discrepancies) > 0 {
+			rec.Status = model.ReconciliationDiscrepancy
+		}
+		sort.Slice(rec.Discrepancies, func(i, j int) bool {
+			a, b := rec.Discrepancies[i], rec.Discrepancies[j]
+			if a.Kind != b.Kind {
+				return a.Kind < b.Kind
+			}
+			return a.DeliveryID < b.DeliveryID
+		})
+		reconciliations = append(reconciliations, rec)
+	}
+	sort.Slice(reconciliations, func(i, j int) bool {
+		return reconciliations[i].Currency < reconciliations[j].Currency
+	})
+	return reconciliations
+}
+
+func (r *reconciler) currency(currency string) *model.CODReconciliation {
+	rec, ok := r.byCurrency[currency]
+	if !ok {
+		rec = &model.CODReconciliation{
+			CourierID:     r.courierID,
+			Date:          r.date,
+			Currency:      currency,
+			Status:        model.ReconciliationBalanced,
+			ManifestIDs:   r.manifestIDs,
+			Discrepancies: []*model.CODDiscrepancy{},
+			ReconciledBy:  r.actorID,
+			ReconciledAt:  r.now,
+		}
+		r.byCurrency[currency] = rec
+	}
+	return rec
+}
+
+func (r *reconciler) flag(rec *model.CODReconciliation, kind, deliveryID, manifestID string, expected, actual int64) {
+	rec.Discrepancies = append(rec.Discrepancies, &model.CODDiscrepancy{
+		CourierID:     r.courierID,
+		Date:          r.date,
+		Currency:      rec.Currency,
+		Kind:          kind,
+		DeliveryID:    deliveryID,
+		ManifestID:    manifestID,
+		ExpectedCents: expected,
+		ActualCents:   actual,
+	})
+}
+
+// formatCents formats an amount in minor units for messages.
+func formatCents(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
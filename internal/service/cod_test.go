@@ -0,0 +1,52 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func TestReconcileOnlyOwesHandedOverCOD(t *testing.T) {
+	item := func(id string) *model.ManifestItem {
+		return &model.ManifestItem{DeliveryID: id, CODDueCents: 2500, CODCurrency: "EUR"}
+	}
+	r := &reconciler{
+		courierID:  "courier-1",
+		date:       "2026-03-02",
+		byCurrency: make(map[string]*model.CODReconciliation),
+		due: map[string]manifestDue{
+			"paid":    {manifestID: "m1", item: item("paid")},
+			"unpaid":  {manifestID: "m1", item: item("unpaid")},
+			"failed":  {manifestID: "m1", item: item("failed")},
+			"counter": {manifestID: "m1", item: item("counter")},
+			"prepaid": {manifestID: "m1", item: &model.ManifestItem{DeliveryID: "prepaid"}},
+		},
+		handedOver: map[string]bool{"paid": true, "unpaid": true},
+	}
+	collections := []*model.CODCollection{
+		{DeliveryID: "paid", CourierID: "courier-1", AmountCents: 2500, Currency: "EUR"},
+		{DeliveryID: "counter", AmountCents: 2500, Currency: "EUR"},
+	}
+	entries := []*model.LedgerEntry{
+		{Type: model.LedgerCollection, AmountCents: 2500, Currency: "EUR"},
+		{Type: model.LedgerDeposit, AmountCents: -2500, Currency: "EUR"},
+	}
+
+	recs := r.reconcile(collections, entries)
+	if len(recs) != 1 {
+		t.Fatalf("reconciliations = %d, want 1", len(recs))
+	}
+	rec := recs[0]
+	if rec.DueCents != 5000 || rec.CollectedCents != 2500 {
+		t.Errorf("due = %d, collected = %d, want 5000 and 2500", rec.DueCents, rec.CollectedCents)
+	}
+	if len(rec.Discrepancies) != 1 {
+		t.Fatalf("discrepancies = %d, want 1", len(rec.Discrepancies))
+	}
+	if d := rec.Discrepancies[0]; d.Kind != model.DiscrepancyMissingCollection || d.DeliveryID != "unpaid" {
+		t.Errorf("discrepancy = %s for %s, want %s for unpaid", d.Kind, d.DeliveryID, model.DiscrepancyMissingCollection)
+	}
+	if rec.Status != model.ReconciliationDiscrepancy {
+		t.Errorf("status = %s, want %s", rec.Status, model.ReconciliationDiscrepancy)
+	}
+}
@@ -3,21 +3,115 @@ package service
 import (
 	"context"
 	"errors"
+	"regexp"
+	"time"
 
+	"github.com/bharathbbg/delivery-service/internal/address"
+	"github.com/bharathbbg/delivery-service/internal/blob"
+	"github.com/bharathbbg/delivery-service/internal/eta"
+	"github.com/bharathbbg/delivery-service/internal/geo"
+	"github.com/bharathbbg/delivery-service/internal/geofence"
 	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/notify"
+	"github.com/bharathbbg/delivery-service/internal/rate"
 	"github.com/bharathbbg/delivery-service/internal/repository"
+	"github.com/bharathbbg/delivery-service/internal/slot"
+	"github.com/bharathbbg/delivery-service/internal/tracking"
+	"github.com/bharathbbg/delivery-service/internal/tracknum"
+	"github.com/bharathbbg/delivery-service/internal/zone"
 )
 
 type DeliveryService struct {
-	repo  *repository.PostgresRepository
-	cache *repository.RedisCache
+	repo          *repository.PostgresRepository
+	cache         *repository.RedisCache
+	eta           *eta.Engine
+	serviceLevels map[string]ServiceLevelPolicy
+	slots         *slot.Schedule
+	zones         *zone.Directory
+	addresses     *address.Pipeline
+	geocoder      geo.Geocoder
+	tracking      tracking.Policy
+	geofences     GeofencePolicy
+	routes        RoutePolicy
+	blobs         blob.Store
+	otp           OTPPolicy
+	notifier      notify.Notifier
+	attempts      AttemptPolicy
+	returns       ReturnPolicy
+	events        EventPublisher
+	pickups       PickupPolicy
+	hubs          HubPolicy
+	scans         ScanPolicy
+	labels        LabelPolicy
+	manifests     ManifestPolicy
+	trackNumbers  tracknum.Scheme
+	legacyNumbers *regexp.Regexp
+	rates         *rate.Book
+	carriers      CarrierPolicy
 }
 
-func NewDeliveryService(repo *repository.PostgresRepository, cache *repository.RedisCache) *DeliveryService {
-	return &DeliveryService{
-		repo:  repo,
-		cache: cache,
+func NewDeliveryService(repo *repository.PostgresRepository, cache *repository.RedisCache, etaEngine *eta.Engine) *DeliveryService {
+	s := &DeliveryService{
+		repo:          repo,
+		cache:         cache,
+		eta:           etaEngine,
+		serviceLevels: make(map[string]ServiceLevelPolicy),
+		zones:         zone.NewDirectory(repo, time.Minute),
+		addresses:     address.NewPipeline(address.StubVerifier{}),
+		geocoder:      geo.NewCentroidGeocoder(),
+		tracking: tracking.Policy{
+			Downsampler:  tracking.Downsampler{MinInterval: 30 * time.Second, MinDistance: 50},
+			LastKnownTTL: time.Hour,
+			MaxAge:       24 * time.Hour,
+			MaxSkew:      time.Minute,
+			MaxItems:     1000,
+		},
+		geofences: GeofencePolicy{
+			Tracker:      geofence.Tracker{ExitFactor: 1.5},
+			NearbyRadius: 300,
+			StateTTL:     24 * time.Hour,
+		},
+		routes: RoutePolicy{
+			SpeedKmh:    30,
+			Detour:      1.3,
+			ServiceTime: 5 * time.Minute,
+			Capacity:    60,
+		},
+		otp: OTPPolicy{
+			Length:      6,
+			TTL:         24 * time.Hour,
+			MaxAttempts: 5,
+			Lockout:     15 * time.Minute,
+		},
+		notifier: notify.LogNotifier{},
+		attempts: AttemptPolicy{
+			MaxAttempts:  3,
+			ReturnOn:     []string{model.AttemptRefused},
+			ReattemptGap: 1,
+		},
+		returns: ReturnPolicy{
+			Window:    30 * 24 * time.Hour,
+			Warehouse: "WAREHOUSE",
+		},
+		events:        cache,
+		pickups:       PickupPolicy{HoldDays: 7},
+		labels:        LabelPolicy{SenderName: "Delivery Service"},
+		trackNumbers:  tracknum.Crockford{Prefix: "TRK-", Length: 10},
+		legacyNumbers: legacyTrackingNumbers("TRK-"),
+		scans: ScanPolicy{
+			MaxItems:        1000,
+			BatchSize:       100,
+			DuplicateWindow: 5 * time.Minute,
+			MaxSkew:         5 * time.Minute,
+		},
 	}
+
+	// Standard delivery is always offered and uses the engine's default rule
+	s.SetServiceLevel(model.ServiceLevelStandard, ServiceLevelPolicy{
+		ETA: etaEngine.Rule(model.ServiceLevelStandard),
+	})
+
+	return s
 }
 
 func (s *DeliveryService) CreateDelivery(ctx context.Context, req *model.CreateDeliveryRequest) (*model.Delivery, error) {
@@ -26,15 +120,106 @@ func (s *DeliveryService) CreateDelivery(ctx context.Context, req *model.CreateD
 		return nil, errors.New("order_id is required")
 	}
 
+	for _, parcel := range req.Parcels {
+		if err := validateParcel(parcel); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateCOD(req); err != nil {
+		return nil, err
+	}
+
+	// Deliveries to a pickup point go to the point's address
+	if req.PickupPointID != "" {
+		point, err := s.activePickupPoint(ctx, req.PickupPointID)
+		if err != nil {
+			return nil, err
+		}
+		req.ShippingAddress = point.Address
+	}
+
+	// Validate, normalize and geocode the shipping address
+	shippingAddress, err := s.prepareAddress(ctx, req.ShippingAddress)
+	if err != nil {
+		return nil, err
+	}
+	req.ShippingAddress = shippingAddress
+
+	// Reject addresses neither we nor a carrier deliver to
+	deliveryZone, err := s.deliveryZone(ctx, req.ShippingAddress)
+	if err != nil {
+		return nil, err
+	}
+	zoneID, zoneName := "", ""
+	if deliveryZone != nil {
+		zoneID, zoneName = deliveryZone.ID, deliveryZone.Name
+	}
+
+	if req.SlotReservationID != "" {
+		if err := s.applySlotReservation(ctx, req, zoneName); err != nil {
+			return nil, err
+		}
+	}
+	if req.ServiceLevel == "" {
+		req.ServiceLevel = model.ServiceLevelStandard
+	}
+
+	now := time.Now()
+	if err := s.validateServiceLevel(req, zoneName, now); err != nil {
+		return nil, err
+	}
+
+	// Hand the delivery to a carrier if the routing rules say so
+	carrierName := s.routeCarrier(deliveryZone, req.ServiceLevel)
+	if carrierName != "" {
+		if err := validateCarrierDelivery(req); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create delivery object
 	delivery := &model.Delivery{
-		OrderID:         req.OrderID,
-		ShippingAddress: req.ShippingAddress,
-		Status:          "PENDING",
+		OrderID:              req.OrderID,
+		ShippingAddress:      req.ShippingAddress,
+		Status:               model.StatusPending,
+		ServiceLevel:         req.ServiceLevel,
+		ScheduledFor:         req.ScheduledFor,
+		SlotReservationID:    req.SlotReservationID,
+		ZoneID:               zoneID,
+		RequiresVerification: req.RequiresVerification,
+		Type:                 req.Type,
+		ParentDeliveryID:     req.ParentDeliveryID,
+		ReturnReason:         req.ReturnReason,
+		ReturnTo:             req.ReturnTo,
+		Parcels:              req.Parcels,
+		PickupPointID:        req.PickupPointID,
+		CODAmountCents:       req.CODAmountCents,
+		CODCurrency:          req.CODCurrency,
+		Carrier:              carrierName,
 	}
 
-	// Save to database
-	savedDelivery, err := s.repo.CreateDelivery(ctx, delivery)
+	// Route the delivery through the hub network; carriers use their own
+	if delivery.Carrier == "" {
+		delivery.Legs, delivery.CurrentHubID, err = s.planTransit(ctx, delivery)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Estimate delivery time
+	estimate, err := s.eta.Estimate(ctx, s.etaRequest(delivery, now))
+	if err != nil {
+		return nil, err
+	}
+	delivery.EstimatedDeliveryTime = estimate
+
+	// Save to database, booking carrier deliveries with the carrier first
+	var savedDelivery *model.Delivery
+	if delivery.Carrier != "" {
+		savedDelivery, err = s.createWithCarrier(ctx, delivery)
+	} else {
+		savedDelivery, err = s.createWithTrackingNumber(ctx, delivery)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -84,9 +269,29 @@ func (s *DeliveryService) UpdateDelivery(ctx context.Context, req *model.UpdateD
 	if req.Status == "" {
 		return nil, errors.New("status is required")
 	}
+	if req.Status == model.StatusFailedAttempt {
+		return nil, errors.New("failed attempts must be recorded with a reason code")
+	}
+	if req.Status == model.StatusCancelled {
+		return nil, errors.New("deliveries must be cancelled with CancelDelivery")
+	}
+	if req.Status == model.StatusAvailableForPickup {
+		return nil, errors.New("deliveries must be held with HoldAtPickupPoint")
+	}
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return nil, errors.New("latitude and longitude must be given together")
+	}
+	if req.Latitude != nil && !(geo.Point{Lat: *req.Latitude, Lng: *req.Longitude}).Valid() {
+		return nil, errors.New("coordinates are out of range")
+	}
+
+	// The move is checked against the delivery as it is locked for the update
+	check := func(current *model.Delivery) error {
+		return s.checkUpdate(ctx, req, current)
+	}
 
 	// Update in database
-	updatedDelivery, err := s.repo.UpdateDelivery(ctx, req)
+	updatedDelivery, err := s.repo.UpdateDelivery(ctx, req, check)
 	if err != nil {
 		return nil, err
 	}
@@ -95,6 +300,21 @@ func (s *DeliveryService) UpdateDelivery(ctx context.Context, req *model.UpdateD
 		return nil, errors.New("delivery not found")
 	}
 
+	// Send the recipient a PIN when the courier sets out; pickup point
+	// deliveries get a pickup code when they are dropped off instead
+	if req.Status == model.StatusOutForDelivery && updatedDelivery.RequiresVerification && updatedDelivery.PickupPointID == "" {
+		if err := s.issueOTP(ctx, updatedDelivery); err != nil {
+			// log.Printf("Failed to issue delivery PIN: %v", err)
+		}
+	}
+
+	// Recompute ETA for the new status
+	if !model.IsTerminal(updatedDelivery.Status) {
+		if err := s.reviseETA(ctx, updatedDelivery); err != nil {
+			// log.Printf("Failed to revise delivery ETA: %v", err)
+		}
+	}
+
 	// Invalidate and update cache
 	if err := s.cache.CacheDelivery(ctx, updatedDelivery); err != nil {
 		// log.Printf("Failed to update delivery cache: %v", err)
@@ -102,10 +322,34 @@ func (s *DeliveryService) UpdateDelivery(ctx context.Context, req *model.UpdateD
 	if err := s.cache.CacheDeliveryByTracking(ctx, updatedDelivery); err != nil {
 		// log.Printf("Failed to update delivery tracking cache: %v", err)
 	}
+	if err := s.cache.InvalidateDeliveryEvents(ctx, updatedDelivery.ID); err != nil {
+		// log.Printf("Failed to invalidate delivery events cache: %v", err)
+	}
 
 	return updatedDelivery, nil
 }
 
+// checkUpdate checks a status update against the delivery it moves.
+// Deliveries flagged for verification need the recipient's PIN, and held
+// deliveries their pickup code. COD deliveries need the payment taken, which
+// is set on req for the repository to record.
+func (s *DeliveryService) checkUpdate(ctx context.Context, req *model.UpdateDeliveryRequest, current *model.Delivery) error {
+	if !model.CanTransition(current.Type, current.Status, req.Status) {
+		return &Error{Code: CodeInvalidTransition, Message: "delivery cannot move from " + current.Status + " to " + req.Status}
+	}
+	if req.Status != model.StatusDelivered {
+		return nil
+	}
+	if current.RequiresVerification || current.Status == model.StatusAvailableForPickup {
+		if err := s.requireVerification(ctx, current.ID, req.PIN); err != nil {
+			return err
+		}
+	}
+	var err error
+	req.Collection, err = codCollection(current, req.COD)
+	return err
+}
+
 func (s *DeliveryService) ListDeliveries(ctx context.Context, orderID string, page, pageSize int) ([]*model.Delivery, int, error) {
 	// Ensure valid pagination
 	if page < 1 {
@@ -120,6 +364,12 @@ func (s *DeliveryService) ListDeliveries(ctx context.Context, orderID string, pa
 }
 
 func (s *DeliveryService) TrackDelivery(ctx context.Context, trackingNumber string) (*model.Delivery, []*model.DeliveryEvent, error) {
+	// Turn away mistyped numbers before looking them up
+	trackingNumber, err := s.checkTrackingNumber(trackingNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Try to get from cache first
 	cachedDelivery, err := s.cache.GetCachedDeliveryByTracking(ctx, trackingNumber)
 	if err == nil && cachedDelivery != nil {
@@ -153,3 +403,71 @@ func (s *DeliveryService) TrackDelivery(ctx context.Context, trackingNumber stri
 
 	return delivery, events, nil
 }
+
+func (s *DeliveryService) reviseETA(ctx context.Context, delivery *model.Delivery) error {
+	// Out for delivery, the arrival planned on the courier's route beats
+	// transit times
+	if delivery.Status == model.StatusOutForDelivery {
+		planned, err := s.repo.PlannedArrival(ctx, delivery.ID)
+		if err != nil {
+			return err
+		}
+		if planned != nil {
+			if planned.Equal(delivery.EstimatedDeliveryTime) {
+				return nil // Recorded when the route was planned
+			}
+			return s.recordETA(ctx, delivery, *planned)
+		}
+	}
+
+	revised, err := s.eta.Revise(ctx, s.etaRequest(delivery, time.Now()), delivery.EstimatedDeliveryTime)
+	if err != nil {
+		return err
+	}
+	return s.recordETA(ctx, delivery, revised)
+}
+
+func (s *DeliveryService) recordETA(ctx context.Context, delivery *model.Delivery, estimate time.Time) error {
+	if err := s.repo.RecordETARevision(ctx, delivery.ID, estimate, delivery.Status); err != nil {
+		return err
+	}
+	delivery.EstimatedDeliveryTime = estimate
+	return nil
+}
+
+func (s *DeliveryService) etaRequest(delivery *model.Delivery, at time.Time) eta.Request {
+	req := eta.Request{
+		Lane:         laneOf(delivery),
+		ServiceLevel: delivery.ServiceLevel,
+		Status:       delivery.Status,
+		At:           at,
+	}
+	if delivery.ScheduledFor != nil {
+		req.ScheduledFor = *delivery.ScheduledFor
+	}
+	return req
+}
+
+// laneOf returns the lane a delivery travels: from the hub its first leg
+// leaves, legs being in order, to its destination zone.
+func laneOf(delivery *model.Delivery) eta.Lane {
+	lane := eta.Lane{Destination: delivery.ZoneID}
+	if len(delivery.Legs) > 0 {
+		lane.Origin = delivery.Legs[0].FromHubID
+	}
+	return lane
+}
+
+func (s *DeliveryService) GetETARevisions(ctx context.Context, deliveryID string) ([]*model.ETARevision, error) {
+	if deliveryID == "" {
+		return nil, errors.New("delivery_id is required")
+	}
+	return s.repo.ListETARevisions(ctx, deliveryID)
+}
+
+func (s *DeliveryService) GetETAAccuracy(ctx context.Context, from, to time.Time) (*model.ETAAccuracyReport, error) {
+	if !from.Before(to) {
+		return nil, errors.New("from must be before to")
+	}
+	return s.repo.ETAAccuracy(ctx, from, to)
+}
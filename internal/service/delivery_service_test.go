@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bharathbbg/delivery-service/internal/eta"
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func TestCheckUpdate(t *testing.T) {
+	errCODRequired := &Error{Code: CodeCODRequired}
+	cash := &model.CODPayment{Method: model.PaymentCash, AmountCents: 2500, Currency: "EUR"}
+
+	tests := []struct {
+		name     string
+		delivery model.Delivery
+		status   string
+		cod      *model.CODPayment
+		want     error
+	}{
+		{"out for delivery", model.Delivery{Status: model.StatusInTransit}, model.StatusOutForDelivery, nil, nil},
+		{"delivered at the door", model.Delivery{Status: model.StatusOutForDelivery}, model.StatusDelivered, nil, nil},
+		{"back to the hub", model.Delivery{Status: model.StatusOutForDelivery}, model.StatusInTransit, nil, nil},
+		{"event on a finished delivery", model.Delivery{Status: model.StatusCancelled}, model.EventAddressChanged, nil, nil},
+		{"return at the warehouse", model.Delivery{Type: model.DeliveryTypeReturn, Status: model.StatusInTransit}, model.StatusDelivered, nil, nil},
+		{"delivered without going out", model.Delivery{Status: model.StatusInTransit}, model.StatusDelivered, nil, errInvalidTransition},
+		{"delivered from pending", model.Delivery{Status: model.StatusPending}, model.StatusDelivered, nil, errInvalidTransition},
+		{"delivered twice", model.Delivery{Status: model.StatusDelivered}, model.StatusDelivered, nil, errInvalidTransition},
+		{"out of a terminal status", model.Delivery{Status: model.StatusReturnToSender}, model.StatusOutForDelivery, nil, errInvalidTransition},
+		{"derived status", model.Delivery{Status: model.StatusOutForDelivery}, model.StatusPartiallyDelivered, nil, errInvalidTransition},
+		{"outbound picked up", model.Delivery{Status: model.StatusInTransit}, model.StatusPickedUp, nil, errInvalidTransition},
+		{"COD without payment", model.Delivery{Status: model.StatusOutForDelivery, CODAmountCents: 2500, CODCurrency: "EUR"}, model.StatusDelivered, nil, errCODRequired},
+		{"COD with payment", model.Delivery{Status: model.StatusOutForDelivery, CODAmountCents: 2500, CODCurrency: "EUR"}, model.StatusDelivered, cash, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &model.UpdateDeliveryRequest{ID: "d1", Status: tt.status, COD: tt.cod}
+			tt.delivery.ID = "d1"
+			err := (&DeliveryService{}).checkUpdate(context.Background(), req, &tt.delivery)
+			assertError(t, err, tt.want)
+			if tt.want == nil && (req.Collection != nil) != (tt.cod != nil) {
+				t.Errorf("collection = %v, want one only for COD", req.Collection)
+			}
+		})
+	}
+}
+
+func TestLaneOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		delivery model.Delivery
+		want     eta.Lane
+	}{
+		{"through the hub network", model.Delivery{ZoneID: "z1", Legs: []*model.TransitLeg{
+			{FromHubID: "origin", ToHubID: "sort"},
+			{FromHubID: "sort", ToHubID: "last-mile"},
+		}}, eta.Lane{Origin: "origin", Destination: "z1"}},
+		{"without legs", model.Delivery{ZoneID: "z1"}, eta.Lane{Destination: "z1"}},
+		{"outside all zones", model.Delivery{}, eta.Lane{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := laneOf(&tt.delivery); got != tt.want {
+				t.Errorf("laneOf = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// Error is a client-facing error with a stable code that API layers can map
+// to transport status codes.
+type Error struct {
+	Code    string
+	Message string
+	Fields  []model.FieldError
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Error codes
+const (
+	CodeNotServiceable = "ADDRESS_NOT_SERVICEABLE"
+	CodeInvalidZone    = "INVALID_ZONE"
+	CodeInvalidAddress = "INVALID_ADDRESS"
+	CodeNotFound       = "NOT_FOUND"
+
+	CodeInvalidTrackingNumber = "INVALID_TRACKING_NUMBER"
+
+	CodeLocationUnavailable = "LOCATION_UNAVAILABLE"
+	CodeInvalidTransition   = "INVALID_STATUS_TRANSITION"
+	CodeForbidden           = "FORBIDDEN"
+
+	CodeVerificationRequired = "VERIFICATION_REQUIRED"
+	CodeInvalidPIN           = "INVALID_PIN"
+	CodePINLocked            = "PIN_LOCKED"
+
+	CodeCODRequired = "COD_PAYMENT_REQUIRED"
+
+	CodeRateUnavailable = "RATE_UNAVAILABLE"
+
+	CodeCarrierUnavailable = "CARRIER_UNAVAILABLE"
+)
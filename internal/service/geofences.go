@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/geo"
+	"github.com/bharathbbg/delivery-service/internal/geofence"
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// GeofencePolicy controls automatic geofence events.
+type GeofencePolicy struct {
+	Tracker      geofence.Tracker
+	NearbyRadius float64       // meters around a delivery's destination
+	StateTTL     time.Duration // how long a courier's fence state is kept without pings
+}
+
+func (s *DeliveryService) SetGeofencePolicy(policy GeofencePolicy) {
+	s.geofences = policy
+}
+
+func (s *DeliveryService) CreateHubGeofence(ctx context.Context, fence *geofence.Fence) (*geofence.Fence, error) {
+	fence.Kind = geofence.KindHub
+	if fence.RefID == "" {
+		return nil, errors.New("ref_id is required")
+	}
+	if fence.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if fence.Radius <= 0 {
+		return nil, errors.New("radius must be positive")
+	}
+	if !fence.Center.Valid() {
+		return nil, errors.New("center is out of range")
+	}
+	return s.repo.CreateGeofence(ctx, fence)
+}
+
+func (s *DeliveryService) ListHubGeofences(ctx context.Context) ([]geofence.Fence, error) {
+	return s.repo.ListGeofences(ctx, geofence.KindHub)
+}
+
+// evaluateGeofences checks a courier's new position against hub fences and the
+// destinations of its deliveries that are out for delivery, and records the
+// resulting events through UpdateDelivery.
+func (s *DeliveryService) evaluateGeofences(ctx context.Context, loc model.CourierLocation) error {
+	deliveries, err := s.repo.ListCourierDeliveries(ctx, loc.CourierID)
+	if err != nil {
+		return err
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	fences, err := s.repo.ListGeofences(ctx, geofence.KindHub)
+	if err != nil {
+		return err
+	}
+	for _, d := range deliveries {
+		center := geo.PointOf(d.ShippingAddress)
+		if d.Status != model.StatusOutForDelivery || center == nil {
+			continue
+		}
+		fences = append(fences, geofence.Fence{
+			ID:     "delivery:" + d.ID,
+			Kind:   geofence.KindDestination,
+			RefID:  d.ID,
+			Name:   "Destination",
+			Center: *center,
+			Radius: s.geofences.NearbyRadius,
+			Sticky: true,
+		})
+	}
+
+	inside, err := s.cache.GetGeofenceState(ctx, loc.CourierID)
+	if err != nil {
+		return err
+	}
+
+	// Forget fences that no longer apply, e.g. destinations already delivered
+	current := make(map[string]bool, len(fences))
+	for _, f := range fences {
+		current[f.ID] = true
+	}
+	for id := range inside {
+		if !current[id] {
+			delete(inside, id)
+		}
+	}
+
+	point := geo.Point{Lat: loc.Latitude, Lng: loc.Longitude}
+	transitions := s.geofences.Tracker.Evaluate(inside, fences, point, loc.Accuracy)
+
+	for _, t := range transitions {
+		switch t.Fence.Kind {
+		case geofence.KindDestination:
+			err = s.recordGeofenceEvent(ctx, t.Fence.RefID, model.EventNearby, t.Fence.Name, "Courier is nearby", loc)
+		case geofence.KindHub:
+			status, description := model.EventArrivedAtHub, "Arrived at "+t.Fence.Name
+			if !t.Entered {
+				status, description = model.EventDepartedHub, "Departed "+t.Fence.Name
+			}
+			for _, d := range deliveries {
+				if err = s.recordGeofenceEvent(ctx, d.ID, status, t.Fence.Name, description, loc); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.cache.SetGeofenceState(ctx, loc.CourierID, inside, s.geofences.StateTTL)
+}
+
+func (s *DeliveryService) recordGeofenceEvent(ctx context.Context, deliveryID, status, location, description string, loc model.CourierLocation) error {
+	_, err := s.UpdateDelivery(ctx, &model.UpdateDeliveryRequest{
+		ID:          deliveryID,
+		Status:      status,
+		Location:    location,
+		Description: description,
+		Latitude:    &loc.Latitude,
+		Longitude:   &loc.Longitude,
+	})
+	return err
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// HubPolicy names the hubs every delivery passes through. Last-mile hubs are
+// chosen by the zones they serve.
+type HubPolicy struct {
+	OriginCode string
+	SortCode   string
+}
+
+func (s *DeliveryService) SetHubPolicy(policy HubPolicy) {
+	s.hubs = policy
+}
+
+func (s *DeliveryService) CreateHub(ctx context.Context, hub *model.Hub) (*model.Hub, error) {
+	if err := s.prepareHub(ctx, hub); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateHub(ctx, hub)
+}
+
+func (s *DeliveryService) UpdateHub(ctx context.Context, hub *model.Hub) (*model.Hub, error) {
+	if hub.ID == "" {
+		return nil, errors.New("hub_id is required")
+	}
+	if err := s.prepareHub(ctx, hub); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.UpdateHub(ctx, hub)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, &Error{Code: CodeNotFound, Message: "hub not found"}
+	}
+	return updated, nil
+}
+
+func (s *DeliveryService) GetHub(ctx context.Context, id string) (*model.Hub, error) {
+	hub, err := s.repo.GetHub(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if hub == nil {
+		return nil, &Error{Code: CodeNotFound, Message: "hub not found"}
+	}
+	return hub, nil
+}
+
+func (s *DeliveryService) ListHubs(ctx context.Context, kind, zoneID string) ([]*model.Hub, error) {
+	return s.repo.ListHubs(ctx, kind, zoneID, false)
+}
+
+func (s *DeliveryService) prepareHub(ctx context.Context, hub *model.Hub) error {
+	hub.Code = strings.ToUpper(strings.TrimSpace(hub.Code))
+	if hub.Code == "" {
+		return errors.New("code is required")
+	}
+	if hub.Name == "" {
+		return errors.New("name is required")
+	}
+	switch hub.Kind {
+	case model.HubOrigin, model.HubSort:
+		if len(hub.ZoneIDs) > 0 {
+			return errors.New("only last-mile hubs serve zones")
+		}
+	case model.HubLastMile:
+	default:
+		return errors.New("kind must be ORIGIN, SORT or LAST_MILE")
+	}
+
+	addr, err := s.prepareAddress(ctx, hub.Address)
+	if err != nil {
+		return err
+	}
+	hub.Address = addr
+	return nil
+}
+
+// GetHubInventory lists what is currently on site at a hub.
+func (s *DeliveryService) GetHubInventory(ctx context.Context, hubID string, actor model.Actor) (*model.HubInventory, error) {
+	if err := requireStaff(actor, "view hub inventory"); err != nil {
+		return nil, err
+	}
+	hub, err := s.GetHub(ctx, hubID)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.repo.ListHubDeliveries(ctx, hub.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory := &model.HubInventory{Hub: hub, Deliveries: deliveries}
+	for _, d := range deliveries {
+		if len(d.Parcels) == 0 {
+			inventory.ParcelCount++ // a delivery without parcel records is one piece
+		}
+		inventory.ParcelCount += len(d.Parcels)
+	}
+	if inventory.Deliveries == nil {
+		inventory.Deliveries = []*model.Delivery{}
+	}
+	return inventory, nil
+}
+
+// ScanAtHub records a single hub scan. See IngestHubScans.
+func (s *DeliveryService) ScanAtHub(ctx context.Context, scan *model.HubScan) (*model.Delivery, error) {
+	if err := requireStaff(scan.Actor, "scan parcels at hubs"); err != nil {
+		return nil, err
+	}
+
+	results, errs, err := s.ingestHubScans(ctx, []model.HubScan{*scan})
+	if err != nil {
+		return nil, err
+	}
+	if errs[0] != nil {
+		return nil, errs[0]
+	}
+	return s.GetDelivery(ctx, results[0].DeliveryID)
+}
+
+// scanMovesFrom are the statuses a hub scan moves a delivery on from.
+var scanMovesFrom = []string{model.StatusPending, model.StatusPickedUp}
+
+// statusAfterScan returns the status a hub scan moves a delivery to, or ""
+// if it stays where it is.
+func statusAfterScan(status string) string {
+	for _, from := range scanMovesFrom {
+		if status == from {
+			return model.StatusInTransit
+		}
+	}
+	return ""
+}
+
+// resolveTrackingNumber finds the delivery for a delivery or parcel tracking
+// number, along with the parcel if it was a parcel's.
+func (s *DeliveryService) resolveTrackingNumber(ctx context.Context, trackingNumber string) (*model.Delivery, *model.Parcel, error) {
+	delivery, err := s.repo.GetDeliveryByTracking(ctx, trackingNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+	if delivery != nil {
+		return delivery, nil, nil
+	}
+
+	parcel, err := s.repo.GetParcelByTracking(ctx, trackingNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+	if parcel != nil {
+		delivery, err = s.repo.GetDelivery(ctx, parcel.DeliveryID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if delivery == nil {
+		return nil, nil, &Error{Code: CodeNotFound, Message: "no delivery with tracking number " + trackingNumber}
+	}
+	return delivery, parcel, nil
+}
+
+// planTransit routes a delivery through the hub network and returns its legs
+// and the hub it starts at. Outbound deliveries go origin → sort → last-mile
+// hub; returns come back last-mile → sort → the hub named by ReturnTo, or the
+// origin hub. Hubs that are not configured are skipped.
+func (s *DeliveryService) planTransit(ctx context.Context, delivery *model.Delivery) ([]*model.TransitLeg, string, error) {
+	origin, err := s.hubByCode(ctx, s.hubs.OriginCode)
+	if err != nil {
+		return nil, "", err
+	}
+	sortHub, err := s.hubByCode(ctx, s.hubs.SortCode)
+	if err != nil {
+		return nil, "", err
+	}
+	lastMile, err := s.lastMileHub(ctx, delivery.ZoneID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	path := []*model.Hub{origin, sortHub, lastMile}
+	if delivery.Type == model.DeliveryTypeReturn {
+		warehouse, err := s.hubByCode(ctx, delivery.ReturnTo)
+		if err != nil {
+			return nil, "", err
+		}
+		if warehouse == nil {
+			warehouse = origin
+		}
+		path = []*model.Hub{lastMile, sortHub, warehouse}
+	}
+
+	var hubIDs []string
+	for _, hub := range path {
+		if hub != nil && (len(hubIDs) == 0 || hubIDs[len(hubIDs)-1] != hub.ID) {
+			hubIDs = append(hubIDs, hub.ID)
+		}
+	}
+
+	var legs []*model.TransitLeg
+	for i := 1; i < len(hubIDs); i++ {
+		legs = append(legs, &model.TransitLeg{FromHubID: hubIDs[i-1], ToHubID: hubIDs[i]})
+	}
+
+	// Outbound deliveries start on site at the origin hub; returns start at
+	// the customer's door
+	start := ""
+	if delivery.Type != model.DeliveryTypeReturn && origin != nil {
+		start = origin.ID
+	}
+	return legs, start, nil
+}
+
+func (s *DeliveryService) hubByCode(ctx context.Context, code string) (*model.Hub, error) {
+	if code == "" {
+		return nil, nil
+	}
+	hub, err := s.repo.GetHubByCode(ctx, strings.ToUpper(code))
+	if err != nil || hub == nil || !hub.Active {
+		return nil, err
+	}
+	return hub, nil
+}
+
+// lastMileHub returns the active last-mile hub serving a zone, if any.
+func (s *DeliveryService) lastMileHub(ctx context.Context, zoneID string) (*model.Hub, error) {
+	if zoneID == "" {
+		return nil, nil
+	}
+	hubs, err := s.repo.ListHubs(ctx, model.HubLastMile, zoneID, true)
+	if err != nil || len(hubs) == 0 {
+		return nil, err
+	}
+	return hubs[0], nil
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/label"
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// LabelPolicy controls what is printed on shipping labels.
+type LabelPolicy struct {
+	SenderName string // printed when the origin hub is not known
+}
+
+func (s *DeliveryService) SetLabelPolicy(policy LabelPolicy) {
+	s.labels = policy
+}
+
+// RenderLabel renders the shipping labels for a delivery, one per parcel.
+func (s *DeliveryService) RenderLabel(ctx context.Context, deliveryID, format string, actor model.Actor) ([]byte, error) {
+	format, err := labelFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := s.authorizedDelivery(ctx, deliveryID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Carrier != "" {
+		return s.carrierLabel(ctx, delivery, format)
+	}
+
+	builder := s.newLabelBuilder()
+	labels, err := builder.labels(ctx, delivery, 0)
+	if err != nil {
+		return nil, err
+	}
+	return renderLabels(format, labels)
+}
+
+// RenderCourierLabels renders the labels for every open delivery assigned to
+// a courier, in the order of their latest route.
+func (s *DeliveryService) RenderCourierLabels(ctx context.Context, courierID, format string, actor model.Actor) ([]byte, error) {
+	format, err := labelFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	if courierID == "" {
+		return nil, errors.New("courier_id is required")
+	}
+	if err := authorizeCourier(actor, courierID, "print labels for other couriers"); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.repo.ListCourierDeliveries(ctx, courierID)
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, &Error{Code: CodeNotFound, Message: "courier has no open deliveries"}
+	}
+
+	// Print in stop order; deliveries not on the route go last
+	sequence := make(map[string]int)
+	plan, err := s.repo.GetLatestRoute(ctx, courierID)
+	if err != nil {
+		return nil, err
+	}
+	if plan != nil {
+		for _, stop := range plan.Stops {
+			sequence[stop.DeliveryID] = stop.Sequence
+		}
+	}
+	rank := func(d *model.Delivery) int {
+		if stop, ok := sequence[d.ID]; ok {
+			return stop
+		}
+		return math.MaxInt
+	}
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return rank(deliveries[i]) < rank(deliveries[j])
+	})
+
+	builder := s.newLabelBuilder()
+	var labels []label.Label
+	for _, d := range deliveries {
+		// Listed deliveries do not carry their parcels and legs
+		full, err := s.repo.GetDelivery(ctx, d.ID)
+		if err != nil {
+			return nil, err
+		}
+		if full == nil {
+			continue
+		}
+		deliveryLabels, err := builder.labels(ctx, full, sequence[d.ID])
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, deliveryLabels...)
+	}
+	return renderLabels(format, labels)
+}
+
+func labelFormat(format string) (string, error) {
+	format = strings.ToLower(format)
+	if format == "" {
+		return label.FormatPDF, nil
+	}
+	if !label.IsFormat(format) {
+		return "", errors.New("format must be pdf or zpl")
+	}
+	return format, nil
+}
+
+func renderLabels(format string, labels []label.Label) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := label.Render(&buf, format, labels); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// labelBuilder turns deliveries into labels, looking each hub up once.
+type labelBuilder struct {
+	s    *DeliveryService
+	hubs map[string]*model.Hub
+	now  time.Time
+}
+
+func (s *DeliveryService) newLabelBuilder() *labelBuilder {
+	return &labelBuilder{s: s, hubs: make(map[string]*model.Hub), now: time.Now()}
+}
+
+// labels builds a delivery's labels. stop is its position on the courier's
+// route, or 0 if it has none.
+func (b *labelBuilder) labels(ctx context.Context, d *model.Delivery, stop int) ([]label.Label, error) {
+	var first, last *model.Hub
+	if len(d.Legs) > 0 {
+		var err error
+		if first, err = b.hub(ctx, d.Legs[0].FromHubID); err != nil {
+			return nil, err
+		}
+		if last, err = b.hub(ctx, d.Legs[len(d.Legs)-1].ToHubID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Outbound labels go from the origin hub to the customer; return labels
+	// from the customer to the warehouse
+	customer := label.Party{Name: "Order " + d.OrderID, Address: d.ShippingAddress}
+	from, to := label.Party{Name: b.s.labels.SenderName}, customer
+	routeHub := last
+	if first != nil {
+		from = label.Party{Name: first.Name, Address: first.Address}
+	}
+	if d.Type == model.DeliveryTypeReturn {
+		from, to = customer, label.Party{Name: d.ReturnTo}
+		if last != nil {
+			to = label.Party{Name: last.Name, Address: last.Address}
+		}
+		routeHub = first
+	}
+
+	base := label.Label{
+		TrackingNumber: d.TrackingNumber,
+		OrderID:        d.OrderID,
+		ServiceLevel:   d.ServiceLevel,
+		RouteCode:      routeCode(routeHub, d.ZoneID, stop),
+		From:           from,
+		To:             to,
+		PrintedAt:      b.now,
+	}
+	if len(d.Parcels) == 0 {
+		return []label.Label{base}, nil
+	}
+
+	labels := make([]label.Label, 0, len(d.Parcels))
+	for i, p := range d.Parcels {
+		l := base
+		l.TrackingNumber = p.TrackingNumber
+		l.Piece, l.Pieces = i+1, len(d.Parcels)
+		l.WeightKg = p.WeightKg
+		labels = append(labels, l)
+	}
+	return labels, nil
+}
+
+func (b *labelBuilder) hub(ctx context.Context, id string) (*model.Hub, error) {
+	if hub, ok := b.hubs[id]; ok {
+		return hub, nil
+	}
+	hub, err := b.s.repo.GetHub(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	b.hubs[id] = hub
+	return hub, nil
+}
+
+// routeCode tells sorters where a parcel goes: the code of the hub that
+// delivers it (or its zone when there is none), then its stop on the courier's
+// route.
+func routeCode(hub *model.Hub, zoneID string, stop int) string {
+	code := "UNROUTED"
+	switch {
+	case hub != nil:
+		code = hub.Code
+	case zoneID != "":
+		code = strings.ToUpper(zoneID)
+		if len(code) > 8 {
+			code = code[:8]
+		}
+	}
+	if stop > 0 {
+		code += fmt.Sprintf("-%03d", stop)
+	}
+	return code
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/geo"
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/tracking"
+)
+
+// SetTrackingPolicy replaces the default location ingestion policy.
+func (s *DeliveryService) SetTrackingPolicy(policy tracking.Policy) {
+	s.tracking = policy
+}
+
+// IngestLocations accepts a batch of courier pings. Couriers report only their
+// own position; staff may report any courier's. Invalid pings are reported
+// individually; the rest update each courier's last known position and are
+// downsampled into the location history.
+func (s *DeliveryService) IngestLocations(ctx context.Context, req *model.IngestLocationsRequest) (*model.IngestLocationsResponse, error) {
+	if s.tracking.MaxItems > 0 && len(req.Pings) > s.tracking.MaxItems {
+		return nil, fmt.Errorf("at most %d pings can be sent at once", s.tracking.MaxItems)
+	}
+	if req.Actor.Role != model.RoleCourier {
+		if err := requireStaff(req.Actor, "report courier locations"); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, byCourier := s.groupPings(req.Pings, req.Actor, time.Now())
+
+	var history []model.CourierLocation
+	persisted := make(map[string]model.CourierLocation)
+
+	for courierID, group := range byCourier {
+		last, err := s.cache.GetPersistedLocation(ctx, courierID)
+		if err != nil {
+			return nil, err
+		}
+
+		kept := s.tracking.Downsampler.Select(last, group)
+		if len(kept) > 0 {
+			history = append(history, kept...)
+			persisted[courierID] = kept[len(kept)-1]
+		}
+	}
+
+	// Write history before moving the downsampling markers forward
+	if err := s.repo.InsertCourierLocations(ctx, history); err != nil {
+		return nil, err
+	}
+	resp.Stored = len(history)
+
+	for _, loc := range persisted {
+		loc := loc
+		if err := s.cache.SetPersistedLocation(ctx, &loc); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, group := range byCourier {
+		latest := group[0]
+		for _, p := range group[1:] {
+			if p.RecordedAt.After(latest.RecordedAt) {
+				latest = p
+			}
+		}
+		updated, err := s.cache.SetCourierLocation(ctx, &latest, s.tracking.LastKnownTTL)
+		if err != nil {
+			return nil, err
+		}
+
+		// Only a newer position can cross a geofence
+		if updated {
+			if err := s.evaluateGeofences(ctx, latest); err != nil {
+				// log.Printf("Failed to evaluate geofences: %v", err)
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// groupPings checks each ping and groups the accepted ones by courier.
+func (s *DeliveryService) groupPings(pings []model.CourierLocation, actor model.Actor, now time.Time) (*model.IngestLocationsResponse, map[string][]model.CourierLocation) {
+	resp := &model.IngestLocationsResponse{}
+	byCourier := make(map[string][]model.CourierLocation)
+	for i, p := range pings {
+		err := s.validatePing(p, now)
+		if err == nil {
+			err = authorizeCourier(actor, p.CourierID, "report another courier's location")
+		}
+		if err != nil {
+			resp.Rejected = append(resp.Rejected, model.RejectedPing{Index: i, Error: err.Error()})
+			continue
+		}
+		byCourier[p.CourierID] = append(byCourier[p.CourierID], p)
+		resp.Accepted++
+	}
+	return resp, byCourier
+}
+
+func (s *DeliveryService) validatePing(p model.CourierLocation, now time.Time) error {
+	if p.CourierID == "" {
+		return errors.New("courier_id is required")
+	}
+	if !(geo.Point{Lat: p.Latitude, Lng: p.Longitude}).Valid() {
+		return errors.New("coordinates are out of range")
+	}
+	if p.RecordedAt.IsZero() {
+		return errors.New("recorded_at is required")
+	}
+	if p.RecordedAt.After(now.Add(s.tracking.MaxSkew)) {
+		return errors.New("recorded_at is in the future")
+	}
+	if p.RecordedAt.Before(now.Add(-s.tracking.MaxAge)) {
+		return errors.New("recorded_at is too old")
+	}
+	return nil
+}
+
+// GetDeliveryLocation returns the assigned courier's last known position. It
+// is only available while the delivery is out for delivery.
+func (s *DeliveryService) GetDeliveryLocation(ctx context.Context, deliveryID string, actor model.Actor) (*model.DeliveryLocation, error) {
+	delivery, err := s.authorizedDelivery(ctx, deliveryID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status != model.StatusOutForDelivery || delivery.CourierID == "" {
+		return nil, &Error{Code: CodeLocationUnavailable, Message: "courier location is only available while out for delivery"}
+	}
+
+	loc, err := s.cache.GetCourierLocation(ctx, delivery.CourierID)
+	if err != nil {
+		return nil, err
+	}
+	if loc == nil {
+		return nil, &Error{Code: CodeLocationUnavailable, Message: "courier location is not known yet"}
+	}
+
+	return &model.DeliveryLocation{
+		DeliveryID: delivery.ID,
+		CourierID:  delivery.CourierID,
+		Location:   loc,
+	}, nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/tracking"
+)
+
+func TestIngestLocationsRequiresCourierOrStaff(t *testing.T) {
+	for _, actor := range []model.Actor{{}, {ID: "customer-1"}} {
+		req := &model.IngestLocationsRequest{
+			Pings: []model.CourierLocation{{CourierID: "courier-1", RecordedAt: time.Now()}},
+			Actor: actor,
+		}
+		_, err := (&DeliveryService{}).IngestLocations(context.Background(), req)
+		assertError(t, err, errForbidden)
+	}
+}
+
+func TestGroupPings(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	s := &DeliveryService{tracking: tracking.Policy{MaxAge: time.Hour, MaxSkew: time.Minute}}
+	pings := []model.CourierLocation{
+		{CourierID: "courier-1", Latitude: 52.52, Longitude: 13.40, RecordedAt: now.Add(-time.Minute)},
+		{CourierID: "courier-2", Latitude: 52.52, Longitude: 13.40, RecordedAt: now.Add(-time.Minute)},
+		{CourierID: "courier-1", Latitude: 95, Longitude: 13.40, RecordedAt: now.Add(-time.Minute)},
+		{CourierID: "courier-1", Latitude: 52.53, Longitude: 13.41, RecordedAt: now},
+	}
+
+	tests := []struct {
+		name     string
+		actor    model.Actor
+		accepted map[string]int
+		rejected []int
+	}{
+		{"courier", model.Actor{ID: "courier-1", Role: model.RoleCourier}, map[string]int{"courier-1": 2}, []int{1, 2}},
+		{"staff", model.Actor{Role: model.RoleSystem}, map[string]int{"courier-1": 2, "courier-2": 1}, []int{2}},
+		{"courier without an ID", model.Actor{Role: model.RoleCourier}, map[string]int{}, []int{0, 1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, byCourier := s.groupPings(pings, tt.actor, now)
+
+			var rejected []int
+			for _, r := range resp.Rejected {
+				rejected = append(rejected, r.Index)
+			}
+			if len(rejected) != len(tt.rejected) {
+				t.Fatalf("rejected = %v, want %v", rejected, tt.rejected)
+			}
+			for i := range rejected {
+				if rejected[i] != tt.rejected[i] {
+					t.Fatalf("rejected = %v, want %v", rejected, tt.rejected)
+				}
+			}
+			if len(byCourier) != len(tt.accepted) {
+				t.Fatalf("couriers = %d, want %d", len(byCourier), len(tt.accepted))
+			}
+			for courierID, n := range tt.accepted {
+				if len(byCourier[courierID]) != n {
+					t.Errorf("%s pings = %d, want %d", courierID, len(byCourier[courierID]), n)
+				}
+			}
+			if resp.Accepted != len(pings)-len(tt.rejected) {
+				t.Errorf("accepted = %d, want %d", resp.Accepted, len(pings)-len(tt.rejected))
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/manifest"
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// ManifestPolicy controls how closed manifests are signed.
+type ManifestPolicy struct {
+	Secret []byte // HMAC key for manifest signatures
+}
+
+func (s *DeliveryService) SetManifestPolicy(policy ManifestPolicy) {
+	s.manifests = policy
+}
+
+// CreateManifest builds an open manifest of the deliveries a courier handled
+// on a date, or of the stops on one of their routes. The items are a snapshot:
+// closing the manifest signs exactly what was built here.
+func (s *DeliveryService) CreateManifest(ctx context.Context, req *model.CreateManifestRequest) (*model.Manifest, error) {
+	var deliveries []*model.Delivery
+	var sequence map[string]int
+	var err error
+
+	if req.RouteID != "" {
+		deliveries, sequence, err = s.routeManifestDeliveries(ctx, req)
+	} else {
+		deliveries, err = s.dayManifestDeliveries(ctx, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, &Error{Code: CodeNotFound, Message: "no deliveries to manifest"}
+	}
+
+	m := &model.Manifest{
+		CourierID: req.CourierID,
+		RouteID:   req.RouteID,
+		Date:      req.Date,
+		CreatedBy: req.Actor.ID,
+	}
+	for i, d := range deliveries {
+		item := manifestItem(d)
+		item.Sequence = i + 1
+		if stop, ok := sequence[d.ID]; ok {
+			item.Sequence = stop
+		}
+		if item.CODCurrency != "" {
+			if m.CODCurrency != "" && m.CODCurrency != item.CODCurrency {
+				return nil, errors.New("deliveries collect cash on delivery in more than one currency")
+			}
+			m.CODCurrency = item.CODCurrency
+		}
+		m.Items = append(m.Items, item)
+		m.DeliveryCount++
+		m.ParcelCount += item.Parcels
+		m.WeightKg += item.WeightKg
+		m.CODDueCents += item.CODDueCents
+	}
+
+	return s.repo.CreateManifest(ctx, m)
+}
+
+// dayManifestDeliveries returns the deliveries a courier was due to deliver,
+// or delivered, on the requested date.
+func (s *DeliveryService) dayManifestDeliveries(ctx context.Context, req *model.CreateManifestRequest) ([]*model.Delivery, error) {
+	if req.CourierID == "" {
+		return nil, errors.New("courier_id or route_id is required")
+	}
+	if err := authorizeCourier(req.Actor, req.CourierID, "manifest other couriers"); err != nil {
+		return nil, err
+	}
+
+	loc := s.eta.Calendar().Location()
+	if req.Date == "" {
+		req.Date = time.Now().In(loc).Format("2006-01-02")
+	}
+	day, err := time.ParseInLocation("2006-01-02", req.Date, loc)
+	if err != nil {
+		return nil, errors.New("date must be YYYY-MM-DD")
+	}
+
+	listed, err := s.repo.ListCourierDeliveriesBetween(ctx, req.CourierID, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	// Listed deliveries do not carry their parcels
+	deliveries := make([]*model.Delivery, 0, len(listed))
+	for _, d := range listed {
+		full, err := s.repo.GetDelivery(ctx, d.ID)
+		if err != nil {
+			return nil, err
+		}
+		if full != nil {
+			deliveries = append(deliveries, full)
+		}
+	}
+	return deliveries, nil
+}
+
+// routeManifestDeliveries returns the stops of a route that are still with
+// its courier, with their stop numbers. The manifest date is the day the
+// route started.
+func (s *DeliveryService) routeManifestDeliveries(ctx context.Context, req *model.CreateManifestRequest) ([]*model.Delivery, map[string]int, error) {
+	route, err := s.repo.GetRoute(ctx, req.RouteID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if route == nil {
+		return nil, nil, &Error{Code: CodeNotFound, Message: "route not found"}
+	}
+	if req.CourierID != "" && req.CourierID != route.CourierID {
+		return nil, nil, errors.New("route belongs to another courier")
+	}
+	if err := authorizeCourier(req.Actor, route.CourierID, "manifest other couriers"); err != nil {
+		return nil, nil, err
+	}
+
+	date := route.StartAt.In(s.eta.Calendar().Location()).Format("2006-01-02")
+	if req.Date != "" && req.Date != date {
+		return nil, nil, errors.New("date does not match the route's start date")
+	}
+	req.CourierID, req.Date = route.CourierID, date
+
+	var deliveries []*model.Delivery
+	sequence := make(map[string]int)
+	for _, stop := range route.Stops {
+		d, err := s.repo.GetDelivery(ctx, stop.DeliveryID)
+		if err != nil {
+			return nil, nil, err
+		}
+		// Skip stops that were cancelled, handed to another courier or
+		// already manifested
+		if d == nil || d.Status == model.StatusCancelled || d.CourierID != route.CourierID || d.ManifestID != "" {
+			continue
+		}
+		deliveries = append(deliveries, d)
+		sequence[d.ID] = stop.Sequence
+	}
+	return deliveries, sequence, nil
+}
+
+func manifestItem(d *model.Delivery) *model.ManifestItem {
+	item := &model.ManifestItem{
+		DeliveryID:     d.ID,
+		TrackingNumber: d.TrackingNumber,
+		OrderID:        d.OrderID,
+		Status:         d.Status,
+		City:           d.ShippingAddress.City,
+		ZipCode:        d.ShippingAddress.ZipCode,
+		Parcels:        len(d.Parcels),
+	}
+	// A delivery without parcels still goes out as one piece
+	if item.Parcels == 0 {
+		item.Parcels = 1
+	}
+	for _, p := range d.Parcels {
+		item.WeightKg += p.WeightKg
+	}
+	// The courier carries the COD of every delivery on the manifest;
+	// reconciliation works out what they handed over
+	if d.CODAmountCents > 0 {
+		item.CODDueCents = d.CODAmountCents
+		item.CODCurrency = d.CODCurrency
+	}
+	return item
+}
+
+// CloseManifest signs an open manifest and locks its deliveries against
+// reassignment. Closed manifests cannot change.
+func (s *DeliveryService) CloseManifest(ctx context.Context, id string, actor model.Actor) (*model.Manifest, error) {
+	if err := requireStaff(actor, "close manifests"); err != nil {
+		return nil, err
+	}
+
+	m, err := s.repo.GetManifest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, &Error{Code: CodeNotFound, Message: "manifest not found"}
+	}
+	if m.Status != model.ManifestOpen {
+		return nil, &Error{Code: CodeInvalidTransition, Message: "manifest is already closed"}
+	}
+
+	// Stored timestamps keep microseconds and no zone; sign what will be read
+	// back
+	closedAt := time.Now().UTC().Truncate(time.Microsecond)
+	m.Status = model.ManifestClosed
+	m.ClosedBy = actor.ID
+	m.ClosedAt = &closedAt
+	m.Signature = manifest.Sign(s.manifests.Secret, m)
+
+	if err := s.repo.CloseManifest(ctx, m); err != nil {
+		return nil, err
+	}
+	for _, item := range m.Items {
+		s.reloadCache(ctx, item.DeliveryID)
+	}
+
+	m.Verified = true
+	return m, nil
+}
+
+func (s *DeliveryService) GetManifest(ctx context.Context, id string, actor model.Actor) (*model.Manifest, error) {
+	m, err := s.repo.GetManifest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, &Error{Code: CodeNotFound, Message: "manifest not found"}
+	}
+	if err := authorizeCourier(actor, m.CourierID, "view other couriers' manifests"); err != nil {
+		return nil, err
+	}
+
+	m.Verified = m.Status == model.ManifestClosed && manifest.Verify(s.manifests.Secret, m)
+	return m, nil
+}
+
+func (s *DeliveryService) ListManifests(ctx context.Context, courierID, date string, actor model.Actor) ([]*model.Manifest, error) {
+	if courierID == "" {
+		return nil, errors.New("courier_id is required")
+	}
+	if err := authorizeCourier(actor, courierID, "view other couriers' manifests"); err != nil {
+		return nil, err
+	}
+	if date != "" {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, errors.New("date must be YYYY-MM-DD")
+		}
+	}
+	return s.repo.ListManifests(ctx, courierID, date)
+}
+
+// RenderManifest exports a manifest as CSV or PDF.
+func (s *DeliveryService) RenderManifest(ctx context.Context, id, format string, actor model.Actor) ([]byte, error) {
+	format = strings.ToLower(format)
+	if !manifest.IsFormat(format) {
+		return nil, errors.New("format must be csv or pdf")
+	}
+	m, err := s.GetManifest(ctx, id, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := manifest.Render(&buf, format, m); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// authorizeCourier lets couriers act on their own records and staff on
+// anyone's.
+func authorizeCourier(actor model.Actor, courierID, action string) error {
+	if actor.Role == model.RoleCourier && actor.ID == courierID {
+		return nil
+	}
+	return requireStaff(actor, action)
+}
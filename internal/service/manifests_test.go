@@ -0,0 +1,32 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func TestManifestItemCODDue(t *testing.T) {
+	tests := []struct {
+		name     string
+		delivery model.Delivery
+		want     int64
+	}{
+		{"out for delivery", model.Delivery{Status: model.StatusOutForDelivery, CODAmountCents: 2500, CODCurrency: "EUR"}, 2500},
+		{"delivered", model.Delivery{Status: model.StatusDelivered, CODAmountCents: 2500, CODCurrency: "EUR"}, 2500},
+		{"failed attempt", model.Delivery{Status: model.StatusFailedAttempt, CODAmountCents: 2500, CODCurrency: "EUR"}, 2500},
+		{"for a pickup point", model.Delivery{Status: model.StatusInTransit, PickupPointID: "pp1", CODAmountCents: 2500, CODCurrency: "EUR"}, 2500},
+		{"prepaid", model.Delivery{Status: model.StatusDelivered}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := manifestItem(&tt.delivery)
+			if item.CODDueCents != tt.want {
+				t.Errorf("CODDueCents = %d, want %d", item.CODDueCents, tt.want)
+			}
+			if (item.CODCurrency != "") != (tt.want > 0) {
+				t.Errorf("CODCurrency = %q", item.CODCurrency)
+			}
+		})
+	}
+}
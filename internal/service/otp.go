@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/notify"
+	"github.com/bharathbbg/delivery-service/internal/repository"
+)
+
+// OTPPolicy controls one-time PINs for deliveries that require verification.
+type OTPPolicy struct {
+	Length      int
+	TTL         time.Duration
+	MaxAttempts int
+	Lockout     time.Duration
+	Secret      []byte // HMAC key for stored PIN hashes
+}
+
+func (s *DeliveryService) SetOTPPolicy(policy OTPPolicy) {
+	s.otp = policy
+}
+
+func (s *DeliveryService) SetNotifier(notifier notify.Notifier) {
+	s.notifier = notifier
+}
+
+// IssueOTP generates a new PIN for a delivery that is out for delivery and
+// sends it to the recipient. Any previous PIN stops working.
+func (s *DeliveryService) IssueOTP(ctx context.Context, deliveryID string, actor model.Actor) error {
+	delivery, err := s.authorizedDelivery(ctx, deliveryID, actor)
+	if err != nil {
+		return err
+	}
+	if !delivery.RequiresVerification {
+		return &Error{Code: CodeInvalidTransition, Message: "delivery does not require verification"}
+	}
+	if delivery.Status != model.StatusOutForDelivery {
+		return &Error{Code: CodeInvalidTransition, Message: "PIN can only be issued while out for delivery"}
+	}
+
+	// A new PIN must not reset an active lockout
+	current, err := s.repo.GetOTP(ctx, deliveryID)
+	if err != nil {
+		return err
+	}
+	if current != nil && current.LockedUntil != nil && time.Now().Before(*current.LockedUntil) {
+		return &Error{Code: CodePINLocked, Message: "too many wrong PINs; try again later"}
+	}
+
+	return s.issueOTP(ctx, delivery)
+}
+
+// VerifyOTP checks a PIN entered by the courier.
+func (s *DeliveryService) VerifyOTP(ctx context.Context, req *model.VerifyOTPRequest) (*model.VerifyOTPResponse, error) {
+	if req.PIN == "" {
+		return nil, errors.New("pin is required")
+	}
+	if _, err := s.authorizedDelivery(ctx, req.DeliveryID, req.Actor); err != nil {
+		return nil, err
+	}
+	return s.checkPIN(ctx, req.DeliveryID, req.PIN)
+}
+
+func (s *DeliveryService) issueOTP(ctx context.Context, delivery *model.Delivery) error {
+	pin, err := generatePIN(s.otp.Length)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = s.repo.SaveOTP(ctx, &model.DeliveryOTP{
+		DeliveryID: delivery.ID,
+		PINHash:    s.hashPIN(delivery.ID, pin),
+		ExpiresAt:  now.Add(s.otp.TTL),
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.notifier.Send(ctx, notify.Message{
+		Kind:       notify.KindDeliveryOTP,
+		OrderID:    delivery.OrderID,
+		DeliveryID: delivery.ID,
+		Data:       map[string]string{"pin": pin, "tracking_number": delivery.TrackingNumber},
+	})
+}
+
+func (s *DeliveryService) checkPIN(ctx context.Context, deliveryID, pin string) (*model.VerifyOTPResponse, error) {
+	resp, err := s.repo.VerifyOTP(ctx, deliveryID, s.hashPIN(deliveryID, pin), s.otp.MaxAttempts, s.otp.Lockout)
+	if err != nil {
+		if errors.Is(err, repository.ErrOTPNotFound) || errors.Is(err, repository.ErrOTPExpired) {
+			return nil, &Error{Code: CodeVerificationRequired, Message: err.Error()}
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+// requireVerification ensures a delivery flagged for verification has a
+// verified PIN, checking pin first if one is given.
+func (s *DeliveryService) requireVerification(ctx context.Context, deliveryID, pin string) error {
+	if pin == "" {
+		otp, err := s.repo.GetOTP(ctx, deliveryID)
+		if err != nil {
+			return err
+		}
+		if otp == nil || otp.VerifiedAt == nil {
+			return &Error{Code: CodeVerificationRequired, Message: "recipient PIN must be verified before delivery"}
+		}
+		return nil
+	}
+
+	resp, err := s.checkPIN(ctx, deliveryID, pin)
+	if err != nil {
+		return err
+	}
+	switch {
+	case resp.Verified:
+		return nil
+	case resp.LockedUntil != nil:
+		return &Error{Code: CodePINLocked, Message: "too many wrong PINs; try again after " + resp.LockedUntil.Format(time.RFC3339)}
+	default:
+		return &Error{Code: CodeInvalidPIN, Message: "PIN does not match"}
+	}
+}
+
+func (s *DeliveryService) hashPIN(deliveryID, pin string) string {
+	mac := hmac.New(sha256.New, s.otp.Secret)
+	mac.Write([]byte(deliveryID + ":" + pin))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func generatePIN(length int) (string, error) {
+	digits := make([]byte, length)
+	for i := range digits {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		digits[i] = byte('0' + n.Int64())
+	}
+	return string(digits), nil
+}
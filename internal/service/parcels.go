@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bharathbbg/delivery-service/internal/geo"
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/repository"
+)
+
+// AddParcel adds a box to a delivery that has not left the warehouse yet.
+func (s *DeliveryService) AddParcel(ctx context.Context, deliveryID string, parcel *model.Parcel, actor model.Actor) (*model.Parcel, error) {
+	if err := validateParcel(parcel); err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.authorizedDelivery(ctx, deliveryID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status != model.StatusPending {
+		return nil, &Error{Code: CodeInvalidTransition, Message: "parcels can only be added while the delivery is pending"}
+	}
+
+	parcel.DeliveryID = delivery.ID
+	if err := s.repo.AddParcel(ctx, parcel); err != nil {
+		return nil, err
+	}
+
+	s.reloadCache(ctx, delivery.ID)
+	return parcel, nil
+}
+
+func (s *DeliveryService) ListParcels(ctx context.Context, deliveryID string, actor model.Actor) ([]*model.Parcel, error) {
+	if _, err := s.authorizedDelivery(ctx, deliveryID, actor); err != nil {
+		return nil, err
+	}
+	return s.repo.ListParcels(ctx, deliveryID)
+}
+
+// UpdateParcelStatus moves one parcel and, when that changes the status
+// derived from all parcels, moves the delivery too, in the same transaction.
+func (s *DeliveryService) UpdateParcelStatus(ctx context.Context, req *model.UpdateParcelRequest) (*model.Delivery, error) {
+	// Validate request
+	switch req.Status {
+	case model.StatusInTransit, model.StatusOutForDelivery, model.StatusDelivered, model.StatusReturnToSender:
+	default:
+		return nil, fmt.Errorf("invalid parcel status %q", req.Status)
+	}
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return nil, errors.New("latitude and longitude must be given together")
+	}
+	if req.Latitude != nil && !(geo.Point{Lat: *req.Latitude, Lng: *req.Longitude}).Valid() {
+		return nil, errors.New("coordinates are out of range")
+	}
+	if _, err := s.authorizedDelivery(ctx, req.DeliveryID, req.Actor); err != nil {
+		return nil, err
+	}
+
+	// Read through to the database for the delivery's current terms
+	delivery, err := s.repo.GetDelivery(ctx, req.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, &Error{Code: CodeNotFound, Message: "delivery not found"}
+	}
+	if err := parcelsMovable(delivery); err != nil {
+		return nil, err
+	}
+
+	// Handing over any parcel of a verified delivery needs the recipient's PIN
+	if req.Status == model.StatusDelivered && delivery.RequiresVerification {
+		if err := s.requireVerification(ctx, delivery.ID, req.PIN); err != nil {
+			return nil, err
+		}
+	}
+
+	// Handing over the last parcel of a COD delivery needs the payment. Which
+	// parcel is last is only known once the parcels are locked.
+	collect := func(derived string) (*model.CODCollection, error) {
+		if derived != model.StatusDelivered {
+			return nil, nil
+		}
+		return codCollection(delivery, req.COD)
+	}
+
+	update, err := s.repo.UpdateParcelStatus(ctx, req, collect)
+	if err != nil {
+		if errors.Is(err, repository.ErrDeliveryFinal) || errors.Is(err, repository.ErrDeliveryHeld) {
+			return nil, &Error{Code: CodeInvalidTransition, Message: err.Error()}
+		}
+		return nil, err
+	}
+	if update == nil {
+		return nil, &Error{Code: CodeNotFound, Message: "parcel not found"}
+	}
+
+	updated := s.reloadCache(ctx, delivery.ID)
+	if updated == nil {
+		return nil, &Error{Code: CodeNotFound, Message: "delivery not found"}
+	}
+	if update.Status == update.Previous {
+		return updated, nil
+	}
+
+	// Send the recipient a PIN when the courier sets out with the parcels
+	if update.Status == model.StatusOutForDelivery && updated.RequiresVerification && updated.PickupPointID == "" {
+		if err := s.issueOTP(ctx, updated); err != nil {
+			// log.Printf("Failed to issue delivery PIN: %v", err)
+		}
+	}
+
+	// Recompute ETA for the new status
+	if !model.IsTerminal(updated.Status) {
+		if err := s.reviseETA(ctx, updated); err != nil {
+			// log.Printf("Failed to revise delivery ETA: %v", err)
+		}
+		s.refreshCache(ctx, updated)
+	}
+
+	return updated, nil
+}
+
+// parcelsMovable turns away parcel changes on finished deliveries and on
+// deliveries held at a pickup point, which CollectFromPickupPoint hands over
+// against the pickup code.
+func parcelsMovable(delivery *model.Delivery) error {
+	if model.IsTerminal(delivery.Status) {
+		return &Error{Code: CodeInvalidTransition, Message: "delivery is already " + delivery.Status}
+	}
+	if delivery.Status == model.StatusAvailableForPickup {
+		return &Error{Code: CodeInvalidTransition, Message: repository.ErrDeliveryHeld.Error()}
+	}
+	return nil
+}
+
+func validateParcel(p *model.Parcel) error {
+	if p.WeightKg <= 0 {
+		return errors.New("weight_kg must be positive")
+	}
+	if p.LengthCm < 0 || p.WidthCm < 0 || p.HeightCm < 0 {
+		return errors.New("dimensions cannot be negative")
+	}
+	if p.DeclaredValueCents < 0 {
+		return errors.New("declared_value_cents cannot be negative")
+	}
+	if p.DeclaredValueCents > 0 && len(p.Currency) != 3 {
+		return errors.New("currency is required with a declared value")
+	}
+	return nil
+}
+
+// reloadCache re-reads a delivery after a change that UpdateDelivery did not
+// make and refreshes its cache entries.
+func (s *DeliveryService) reloadCache(ctx context.Context, deliveryID string) *model.Delivery {
+	delivery, err := s.repo.GetDelivery(ctx, deliveryID)
+	if err != nil || delivery == nil {
+		// log.Printf("Failed to reload delivery: %v", err)
+		return delivery
+	}
+
+	s.refreshCache(ctx, delivery)
+	if err := s.cache.InvalidateDeliveryEvents(ctx, deliveryID); err != nil {
+		// log.Printf("Failed to invalidate delivery events cache: %v", err)
+	}
+	return delivery
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func TestParcelsMovable(t *testing.T) {
+	tests := []struct {
+		status string
+		want   error
+	}{
+		{model.StatusPending, nil},
+		{model.StatusInTransit, nil},
+		{model.StatusOutForDelivery, nil},
+		{model.StatusPartiallyDelivered, nil},
+		{model.StatusFailedAttempt, nil},
+		{model.StatusAvailableForPickup, errInvalidTransition},
+		{model.StatusDelivered, errInvalidTransition},
+		{model.StatusCancelled, errInvalidTransition},
+		{model.StatusReturnToSender, errInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			assertError(t, parcelsMovable(&model.Delivery{Status: tt.status}), tt.want)
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/notify"
+)
+
+// PickupPolicy controls how long parcels wait at pickup points.
+type PickupPolicy struct {
+	HoldDays int // used for points created without their own hold period
+}
+
+func (s *DeliveryService) SetPickupPolicy(policy PickupPolicy) {
+	s.pickups = policy
+}
+
+func (s *DeliveryService) CreatePickupPoint(ctx context.Context, point *model.PickupPoint) (*model.PickupPoint, error) {
+	if err := s.preparePickupPoint(ctx, point); err != nil {
+		return nil, err
+	}
+	return s.repo.CreatePickupPoint(ctx, point)
+}
+
+func (s *DeliveryService) UpdatePickupPoint(ctx context.Context, point *model.PickupPoint) (*model.PickupPoint, error) {
+	if point.ID == "" {
+		return nil, errors.New("pickup_point_id is required")
+	}
+	if err := s.preparePickupPoint(ctx, point); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.UpdatePickupPoint(ctx, point)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, &Error{Code: CodeNotFound, Message: "pickup point not found"}
+	}
+	return updated, nil
+}
+
+func (s *DeliveryService) GetPickupPoint(ctx context.Context, id string) (*model.PickupPoint, error) {
+	point, err := s.repo.GetPickupPoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if point == nil {
+		return nil, &Error{Code: CodeNotFound, Message: "pickup point not found"}
+	}
+	return point, nil
+}
+
+func (s *DeliveryService) ListPickupPoints(ctx context.Context, zoneID string) ([]*model.PickupPoint, error) {
+	return s.repo.ListPickupPoints(ctx, zoneID, false)
+}
+
+// preparePickupPoint validates a point and places it in the zone covering
+// its address.
+func (s *DeliveryService) preparePickupPoint(ctx context.Context, point *model.PickupPoint) error {
+	if point.Name == "" {
+		return errors.New("name is required")
+	}
+	if point.Kind != model.PickupLocker && point.Kind != model.PickupCounter {
+		return errors.New("kind must be LOCKER or COUNTER")
+	}
+	if point.Capacity <= 0 {
+		return errors.New("capacity must be positive")
+	}
+	if point.HoldDays < 0 {
+		return errors.New("hold_days cannot be negative")
+	}
+	if point.HoldDays == 0 {
+		point.HoldDays = s.pickups.HoldDays
+	}
+
+	addr, err := s.prepareAddress(ctx, point.Address)
+	if err != nil {
+		return err
+	}
+	pointZone, err := s.resolveZone(ctx, addr)
+	if err != nil {
+		return err
+	}
+	point.Address = addr
+	point.ZoneID = pointZone.ID
+	return nil
+}
+
+// activePickupPoint loads a pickup point that can accept deliveries.
+func (s *DeliveryService) activePickupPoint(ctx context.Context, id string) (*model.PickupPoint, error) {
+	point, err := s.GetPickupPoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !point.Active {
+		return nil, &Error{Code: CodeNotServiceable, Message: "pickup point is not accepting deliveries"}
+	}
+	return point, nil
+}
+
+// RedirectToPickupPoint sends a delivery that has not gone out yet to a
+// pickup point instead of its shipping address.
+func (s *DeliveryService) RedirectToPickupPoint(ctx context.Context, req *model.RedirectRequest) (*model.Delivery, error) {
+	if req.PickupPointID == "" {
+		return nil, errors.New("pickup_point_id is required")
+	}
+	if err := requireStaff(req.Actor, "redirect deliveries"); err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.redirectableDelivery(ctx, req.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.PickupPointID == req.PickupPointID {
+		return nil, &Error{Code: CodeInvalidTransition, Message: "delivery is already going to this pickup point"}
+	}
+	point, err := s.activePickupPoint(ctx, req.PickupPointID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.changeAddress(ctx, delivery, &model.AddressChange{
+		NewAddress:    point.Address,
+		NewZoneID:     point.ZoneID,
+		Reason:        "Redirected to " + point.Name,
+		PickupPointID: point.ID,
+		ChangedBy:     req.Actor.ID,
+	})
+}
+
+// HoldAtPickupPoint records that the courier has dropped a delivery at its
+// pickup point, and sends the recipient a code to collect it with before the
+// point's hold period runs out.
+func (s *DeliveryService) HoldAtPickupPoint(ctx context.Context, deliveryID string, actor model.Actor) (*model.Delivery, error) {
+	delivery, err := s.authorizedDelivery(ctx, deliveryID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := canHold(delivery); err != nil {
+		return nil, err
+	}
+
+	point, err := s.GetPickupPoint(ctx, delivery.PickupPointID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The code is stored with the hold, so a held delivery always has one
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(point.HoldDays) * 24 * time.Hour)
+	code, err := generatePIN(s.otp.Length)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.repo.HoldAtPickupPoint(ctx, delivery.ID, point.Name, expiresAt, &model.DeliveryOTP{
+		DeliveryID: delivery.ID,
+		PINHash:    s.hashPIN(delivery.ID, code),
+		ExpiresAt:  expiresAt,
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sendPickupCode(ctx, updated, point, code); err != nil {
+		// log.Printf("Failed to send pickup code: %v", err)
+	}
+
+	s.refreshCache(ctx, updated)
+	if err := s.cache.InvalidateDeliveryEvents(ctx, updated.ID); err != nil {
+		// log.Printf("Failed to invalidate delivery events cache: %v", err)
+	}
+
+	return updated, nil
+}
+
+// canHold allows holding deliveries routed to a pickup point that are on
+// their way there.
+func canHold(delivery *model.Delivery) error {
+	if delivery.PickupPointID == "" {
+		return &Error{Code: CodeInvalidTransition, Message: "delivery is not routed to a pickup point"}
+	}
+	switch delivery.Status {
+	case model.StatusInTransit, model.StatusOutForDelivery:
+		return nil
+	}
+	return &Error{Code: CodeInvalidTransition, Message: "cannot hold a delivery with status " + delivery.Status}
+}
+
+// CollectFromPickupPoint hands a held delivery to the recipient once their
+// pickup code checks out.
+func (s *DeliveryService) CollectFromPickupPoint(ctx context.Context, req *model.CollectRequest) (*model.Delivery, error) {
+	if req.PickupCode == "" {
+		return nil, errors.New("pickup_code is required")
+	}
+
+	delivery, err := s.authorizedDelivery(ctx, req.DeliveryID, req.Actor)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status != model.StatusAvailableForPickup {
+		return nil, &Error{Code: CodeInvalidTransition, Message: "delivery is not waiting at a pickup point"}
+	}
+	if _, err := codCollection(delivery, req.COD); err != nil {
+		return nil, err
+	}
+	if err := s.requireVerification(ctx, delivery.ID, req.PickupCode); err != nil {
+		return nil, err
+	}
+
+	description := "Collected from pickup point"
+	if req.RecipientName != "" {
+		description += " by " + req.RecipientName
+	}
+	location := "Pickup point"
+	if point, err := s.repo.GetPickupPoint(ctx, delivery.PickupPointID); err == nil && point != nil {
+		location = point.Name
+	}
+
+	return s.UpdateDelivery(ctx, &model.UpdateDeliveryRequest{
+		ID:          delivery.ID,
+		Status:      model.StatusDelivered,
+		Location:    location,
+		Description: description,
+		COD:         req.COD,
+	})
+}
+
+// ExpirePickupHolds returns to sender every delivery that was not collected
+// before its hold ran out, and reports how many were returned. Each delivery
+// is only returned if it is still held when it is updated, so a parcel
+// collected after the listing stays delivered. A delivery that fails to
+// return does not hold up the others.
+func (s *DeliveryService) ExpirePickupHolds(ctx context.Context, now time.Time) (int, error) {
+	ids, err := s.repo.ListExpiredPickupHolds(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	returned := 0
+	var errs []error
+	for _, id := range ids {
+		delivery, err := s.repo.ExpirePickupHold(ctx, id, "Pickup point", "Not collected before the pickup deadline", now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("delivery %s: %w", id, err))
+			continue
+		}
+		if delivery == nil {
+			continue
+		}
+
+		s.refreshCache(ctx, delivery)
+		if err := s.cache.InvalidateDeliveryEvents(ctx, delivery.ID); err != nil {
+			// log.Printf("Failed to invalidate delivery events cache: %v", err)
+		}
+		returned++
+	}
+
+	return returned, errors.Join(errs...)
+}
+
+// sendPickupCode tells the recipient their delivery is ready and the code to
+// collect it with.
+func (s *DeliveryService) sendPickupCode(ctx context.Context, delivery *model.Delivery, point *model.PickupPoint, code string) error {
+	return s.notifier.Send(ctx, notify.Message{
+		Kind:       notify.KindPickupReady,
+		OrderID:    delivery.OrderID,
+		DeliveryID: delivery.ID,
+		Data: map[string]string{
+			"pickup_code":     code,
+			"tracking_number": delivery.TrackingNumber,
+			"pickup_point":    point.Name,
+			"hold_days":       strconv.Itoa(point.HoldDays),
+			"expires_at":      delivery.PickupExpiresAt.Format(time.RFC3339),
+		},
+	})
+}
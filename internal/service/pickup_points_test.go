@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func TestCanHold(t *testing.T) {
+	tests := []struct {
+		name     string
+		delivery model.Delivery
+		want     error
+	}{
+		{"in transit", model.Delivery{PickupPointID: "pp1", Status: model.StatusInTransit}, nil},
+		{"out for delivery", model.Delivery{PickupPointID: "pp1", Status: model.StatusOutForDelivery}, nil},
+		{"not routed to a point", model.Delivery{Status: model.StatusOutForDelivery}, errInvalidTransition},
+		{"already held", model.Delivery{PickupPointID: "pp1", Status: model.StatusAvailableForPickup}, errInvalidTransition},
+		{"returned", model.Delivery{PickupPointID: "pp1", Status: model.StatusReturnToSender}, errInvalidTransition},
+		{"pending", model.Delivery{PickupPointID: "pp1", Status: model.StatusPending}, errInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertError(t, canHold(&tt.delivery), tt.want)
+		})
+	}
+}
+
+func TestRedirectToPickupPointRequiresStaff(t *testing.T) {
+	for _, actor := range []model.Actor{{}, {ID: "courier-1", Role: model.RoleCourier}} {
+		_, err := (&DeliveryService{}).RedirectToPickupPoint(context.Background(), &model.RedirectRequest{
+			DeliveryID:    "d1",
+			PickupPointID: "pp1",
+			Actor:         actor,
+		})
+		assertError(t, err, errForbidden)
+	}
+}
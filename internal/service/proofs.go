@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/blob"
+	"github.com/bharathbbg/delivery-service/internal/geo"
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+// MaxAttachmentBytes caps each signature or photo upload.
+const MaxAttachmentBytes = 10 << 20
+
+var attachmentTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/webp": true,
+}
+
+func (s *DeliveryService) SetBlobStore(store blob.Store) {
+	s.blobs = store
+}
+
+// SubmitProof stores the proof of delivery and its attachments and marks the
+// delivery DELIVERED if it is still out for delivery.
+func (s *DeliveryService) SubmitProof(ctx context.Context, req *model.SubmitProofRequest) (*model.ProofOfDelivery, error) {
+	// Validate request
+	if req.DeliveryID == "" {
+		return nil, errors.New("delivery_id is required")
+	}
+	if req.RecipientName == "" {
+		return nil, errors.New("recipient_name is required")
+	}
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return nil, errors.New("latitude and longitude must be given together")
+	}
+	if req.Latitude != nil && !(geo.Point{Lat: *req.Latitude, Lng: *req.Longitude}).Valid() {
+		return nil, errors.New("coordinates are out of range")
+	}
+	for _, a := range []*model.ProofAttachment{req.Signature, req.Photo} {
+		if a != nil && !attachmentTypes[a.ContentType] {
+			return nil, fmt.Errorf("unsupported attachment type %q", a.ContentType)
+		}
+	}
+	if s.blobs == nil && (req.Signature != nil || req.Photo != nil) {
+		return nil, errors.New("proof storage is not configured")
+	}
+
+	delivery, err := s.repo.GetDelivery(ctx, req.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, &Error{Code: CodeNotFound, Message: "delivery not found"}
+	}
+	if err := canCaptureProof(req.Actor, delivery); err != nil {
+		return nil, err
+	}
+
+	// The OTP result is recorded from our own verification, never the client's
+	otpResult := model.OTPNotRequired
+	if delivery.RequiresVerification {
+		if err := s.requireVerification(ctx, delivery.ID, req.PIN); err != nil {
+			return nil, err
+		}
+		otpResult = model.OTPVerified
+	}
+	if delivery.Status != model.StatusDelivered {
+		if _, err := codCollection(delivery, req.COD); err != nil {
+			return nil, err
+		}
+	}
+
+	// A delivery has one proof; check before uploading anything for a second
+	existing, err := s.repo.GetProof(ctx, delivery.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, repository.ErrProofExists
+	}
+
+	proof := &model.ProofOfDelivery{
+		DeliveryID:    delivery.ID,
+		RecipientName: req.RecipientName,
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+		OTPResult:     otpResult,
+		CapturedBy:    req.Actor.ID,
+		CapturedAt:    time.Now(),
+	}
+
+	// Upload attachments before the record so it never points at missing blobs.
+	// Keys are unique to this submission, so cleaning up after a failure can
+	// never touch the blobs of a proof recorded concurrently.
+	submission := uuid.New().String()
+	if req.Signature != nil {
+		proof.SignatureKey = proofKey(delivery.ID, submission, model.AttachmentSignature)
+		proof.SignatureContentType = req.Signature.ContentType
+		if err := s.putAttachment(ctx, proof.SignatureKey, req.Signature.Body); err != nil {
+			return nil, err
+		}
+	}
+	if req.Photo != nil {
+		proof.PhotoKey = proofKey(delivery.ID, submission, model.AttachmentPhoto)
+		proof.PhotoContentType = req.Photo.ContentType
+		if err := s.putAttachment(ctx, proof.PhotoKey, req.Photo.Body); err != nil {
+			s.deleteAttachments(ctx, proof)
+			return nil, err
+		}
+	}
+
+	// The proof and the DELIVERED status are written together, so a refused
+	// status change never leaves a proof behind that blocks the retry
+	if delivery.Status == model.StatusDelivered {
+		err = s.repo.CreateProof(ctx, proof)
+	} else {
+		_, err = s.UpdateDelivery(ctx, &model.UpdateDeliveryRequest{
+			ID:          delivery.ID,
+			Status:      model.StatusDelivered,
+			Location:    "Recipient address",
+			Description: "Delivered to " + req.RecipientName,
+			Latitude:    req.Latitude,
+			Longitude:   req.Longitude,
+			COD:         req.COD,
+			Proof:       proof,
+		})
+	}
+	if err != nil {
+		s.deleteAttachments(ctx, proof)
+		return nil, err
+	}
+	proof.HasSignature = proof.SignatureKey != ""
+	proof.HasPhoto = proof.PhotoKey != ""
+
+	return proof, nil
+}
+
+// canCaptureProof allows the assigned courier or staff to capture the proof
+// while the delivery is out for delivery, or after it was delivered without one.
+func canCaptureProof(actor model.Actor, delivery *model.Delivery) error {
+	if err := authorizeDelivery(actor, delivery); err != nil {
+		return err
+	}
+	if delivery.Status != model.StatusOutForDelivery && delivery.Status != model.StatusDelivered {
+		return &Error{Code: CodeInvalidTransition, Message: "proof can only be captured for deliveries out for delivery"}
+	}
+	return nil
+}
+
+func (s *DeliveryService) GetProof(ctx context.Context, deliveryID string, actor model.Actor) (*model.ProofOfDelivery, error) {
+	if _, err := s.authorizedDelivery(ctx, deliveryID, actor); err != nil {
+		return nil, err
+	}
+
+	proof, err := s.repo.GetProof(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if proof == nil {
+		return nil, &Error{Code: CodeNotFound, Message: "no proof of delivery recorded"}
+	}
+	return proof, nil
+}
+
+// OpenProofAttachment returns the stored signature or photo and its content
+// type. The caller must close the reader.
+func (s *DeliveryService) OpenProofAttachment(ctx context.Context, deliveryID, kind string, actor model.Actor) (io.ReadCloser, string, error) {
+	proof, err := s.GetProof(ctx, deliveryID, actor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var key, contentType string
+	switch kind {
+	case model.AttachmentSignature:
+		key, contentType = proof.SignatureKey, proof.SignatureContentType
+	case model.AttachmentPhoto:
+		key, contentType = proof.PhotoKey, proof.PhotoContentType
+	default:
+		return nil, "", fmt.Errorf("unknown attachment %q", kind)
+	}
+	if key == "" || s.blobs == nil {
+		return nil, "", &Error{Code: CodeNotFound, Message: kind + " was not captured"}
+	}
+
+	body, err := s.blobs.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			return nil, "", &Error{Code: CodeNotFound, Message: kind + " was not captured"}
+		}
+		return nil, "", err
+	}
+	return body, contentType, nil
+}
+
+func (s *DeliveryService) authorizedDelivery(ctx context.Context, deliveryID string, actor model.Actor) (*model.Delivery, error) {
+	if deliveryID == "" {
+		return nil, errors.New("delivery_id is required")
+	}
+
+	delivery, err := s.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, &Error{Code: CodeNotFound, Message: "delivery not found"}
+	}
+	if err := authorizeDelivery(actor, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// authorizeDelivery lets staff access any delivery and couriers only the
+// deliveries assigned to them.
+func authorizeDelivery(actor model.Actor, delivery *model.Delivery) error {
+	switch actor.Role {
+	case model.RoleAdmin, model.RoleSupport, model.RoleSystem:
+		return nil
+	case model.RoleCourier:
+		if actor.ID != "" && actor.ID == delivery.CourierID {
+			return nil
+		}
+	}
+	return &Error{Code: CodeForbidden, Message: "not allowed to access this delivery"}
+}
+
+// requireStaff allows only operations staff and internal systems.
+func requireStaff(actor model.Actor, action string) error {
+	switch actor.Role {
+	case model.RoleAdmin, model.RoleSupport, model.RoleSystem:
+		return nil
+	}
+	return &Error{Code: CodeForbidden, Message: "not allowed to " + action}
+}
+
+func (s *DeliveryService) putAttachment(ctx context.Context, key string, body io.Reader) error {
+	n, err := s.blobs.Put(ctx, key, io.LimitReader(body, MaxAttachmentBytes+1))
+	if err != nil {
+		return err
+	}
+	if n > MaxAttachmentBytes {
+		s.blobs.Delete(ctx, key)
+		return fmt.Errorf("attachment exceeds %d bytes", MaxAttachmentBytes)
+	}
+	return nil
+}
+
+func (s *DeliveryService) deleteAttachments(ctx context.Context, proof *model.ProofOfDelivery) {
+	for _, key := range []string{proof.SignatureKey, proof.PhotoKey} {
+		if key == "" {
+			continue
+		}
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			// log.Printf("Failed to delete proof attachment: %v", err)
+		}
+	}
+}
+
+func proofKey(deliveryID, submission, kind string) string {
+	return "proofs/" + deliveryID + "/" + submission + "/" + kind
+}
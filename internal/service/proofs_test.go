@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func TestCanCaptureProof(t *testing.T) {
+	courier := model.Actor{ID: "courier-1", Role: model.RoleCourier}
+
+	tests := []struct {
+		name   string
+		actor  model.Actor
+		status string
+		want   error
+	}{
+		{"assigned courier out for delivery", courier, model.StatusOutForDelivery, nil},
+		{"assigned courier after delivery", courier, model.StatusDelivered, nil},
+		{"staff", model.Actor{Role: model.RoleSupport}, model.StatusOutForDelivery, nil},
+		{"other courier", model.Actor{ID: "courier-2", Role: model.RoleCourier}, model.StatusOutForDelivery, errForbidden},
+		{"no role", model.Actor{ID: "courier-1"}, model.StatusOutForDelivery, errForbidden},
+		{"not yet out", courier, model.StatusInTransit, errInvalidTransition},
+		{"cancelled", courier, model.StatusCancelled, errInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delivery := &model.Delivery{ID: "d1", CourierID: "courier-1", Status: tt.status}
+			assertError(t, canCaptureProof(tt.actor, delivery), tt.want)
+		})
+	}
+}
+
+func TestSubmitProofValidation(t *testing.T) {
+	lat, lng := 91.0, 10.0
+	tests := []struct {
+		name string
+		req  model.SubmitProofRequest
+		want string
+	}{
+		{"no delivery", model.SubmitProofRequest{RecipientName: "A"}, "delivery_id"},
+		{"no recipient", model.SubmitProofRequest{DeliveryID: "d1"}, "recipient_name"},
+		{"half a point", model.SubmitProofRequest{DeliveryID: "d1", RecipientName: "A", Latitude: &lat}, "together"},
+		{"out of range", model.SubmitProofRequest{DeliveryID: "d1", RecipientName: "A", Latitude: &lat, Longitude: &lng}, "out of range"},
+		{"unsupported attachment", model.SubmitProofRequest{DeliveryID: "d1", RecipientName: "A", Photo: &model.ProofAttachment{ContentType: "image/gif"}}, "unsupported"},
+		{"no blob store", model.SubmitProofRequest{DeliveryID: "d1", RecipientName: "A", Photo: &model.ProofAttachment{ContentType: "image/png"}}, "not configured"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := (&DeliveryService{}).SubmitProof(context.Background(), &tt.req)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
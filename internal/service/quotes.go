@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/geo"
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/rate"
+)
+
+// SetRateCards installs the rate cards that quotes are priced from.
+func (s *DeliveryService) SetRateCards(book *rate.Book) {
+	s.rates = book
+}
+
+// QuoteDelivery prices a shipment before it is created, for one service level
+// or every level offered to the destination, using the rate card in effect
+// today. Levels the card does not price for the lane are left out.
+func (s *DeliveryService) QuoteDelivery(ctx context.Context, req *model.QuoteRequest) (*model.Quote, error) {
+	if s.rates == nil {
+		return nil, errors.New("rate quotes are not enabled")
+	}
+	if len(req.Parcels) == 0 {
+		return nil, errors.New("at least one parcel is required")
+	}
+	for _, parcel := range req.Parcels {
+		if err := validateParcel(parcel); err != nil {
+			return nil, err
+		}
+	}
+
+	origin, err := s.prepareAddress(ctx, req.Origin)
+	if err != nil {
+		return nil, err
+	}
+	destination, err := s.prepareAddress(ctx, req.Destination)
+	if err != nil {
+		return nil, err
+	}
+	destinationZone, err := s.resolveZone(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+	// Shipments may start outside our zones; the card's wildcard prices them
+	originZone, err := s.zones.Resolve(ctx, origin, geo.PointOf(origin))
+	if err != nil {
+		return nil, err
+	}
+	originName := ""
+	if originZone != nil {
+		originName = originZone.Name
+	}
+
+	now := time.Now()
+	card := s.rates.At(now.In(s.eta.Calendar().Location()).Format("2006-01-02"))
+	if card == nil {
+		return nil, &Error{Code: CodeRateUnavailable, Message: "no rate card is in effect"}
+	}
+
+	levels := []string{req.ServiceLevel}
+	if req.ServiceLevel == "" {
+		levels = levels[:0]
+		for level := range s.serviceLevels {
+			levels = append(levels, level)
+		}
+	}
+
+	quote := &model.Quote{
+		RateCardVersion: card.Version,
+		OriginZone:      originName,
+		DestinationZone: destinationZone.Name,
+		Options:         []*model.QuoteOption{},
+		QuotedAt:        now,
+	}
+	for _, level := range levels {
+		option, err := s.quoteOption(ctx, card, req, level, originName, destinationZone, now)
+		if err != nil {
+			if req.ServiceLevel != "" {
+				return nil, err
+			}
+			continue
+		}
+		quote.Options = append(quote.Options, option)
+	}
+	if len(quote.Options) == 0 {
+		return nil, &Error{Code: CodeRateUnavailable, Message: "no service level is priced for this destination"}
+	}
+
+	sort.Slice(quote.Options, func(i, j int) bool {
+		a, b := quote.Options[i], quote.Options[j]
+		if a.TotalCents != b.TotalCents {
+			return a.TotalCents < b.TotalCents
+		}
+		return a.ServiceLevel < b.ServiceLevel
+	})
+	return quote, nil
+}
+
+// quoteOption prices one service level, checking it is offered to the
+// destination the same way delivery creation does.
+func (s *DeliveryService) quoteOption(ctx context.Context, card *rate.Card, req *model.QuoteRequest, level, origin string, destination *model.Zone, now time.Time) (*model.QuoteOption, error) {
+	delivery := &model.Delivery{
+		ZoneID:       destination.ID,
+		Status:       model.StatusPending,
+		ServiceLevel: level,
+	}
+	if level == model.ServiceLevelScheduled {
+		delivery.ScheduledFor = req.ScheduledFor
+	}
+	create := &model.CreateDeliveryRequest{ServiceLevel: level, ScheduledFor: delivery.ScheduledFor}
+	if err := s.validateServiceLevel(create, destination.Name, now); err != nil {
+		return nil, err
+	}
+
+	option, err := card.Price(level, origin, destination.Name, req.Parcels)
+	if errors.Is(err, rate.ErrNoRate) {
+		return nil, &Error{Code: CodeRateUnavailable, Message: "service_level " + level + " is not priced for this destination"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	option.EstimatedDeliveryTime, err = s.eta.Estimate(ctx, s.etaRequest(delivery, now))
+	if err != nil {
+		return nil, err
+	}
+	return option, nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/repository"
+)
+
+// ReturnPolicy controls which deliveries can be returned and where returns go.
+type ReturnPolicy struct {
+	Window    time.Duration // how long after delivery a return can be authorized
+	Warehouse string        // where returned parcels are brought
+}
+
+func (s *DeliveryService) SetReturnPolicy(policy ReturnPolicy) {
+	s.returns = policy
+}
+
+// CreateReturn authorizes a return for a delivered parcel. The return is a
+// delivery of its own, with its own tracking number, that is picked up from
+// the original shipping address and brought back to the warehouse.
+func (s *DeliveryService) CreateReturn(ctx context.Context, req *model.CreateReturnRequest) (*model.Delivery, error) {
+	// Validate request
+	if req.Reason == "" {
+		return nil, errors.New("reason is required")
+	}
+
+	parent, err := s.authorizedDelivery(ctx, req.DeliveryID, req.Actor)
+	if err != nil {
+		return nil, err
+	}
+	if parent.Type == model.DeliveryTypeReturn {
+		return nil, &Error{Code: CodeInvalidTransition, Message: "a return cannot itself be returned"}
+	}
+	if parent.Status != model.StatusDelivered || parent.ActualDeliveryTime == nil {
+		return nil, &Error{Code: CodeInvalidTransition, Message: "only delivered parcels can be returned"}
+	}
+	if s.returns.Window > 0 && time.Since(*parent.ActualDeliveryTime) > s.returns.Window {
+		return nil, &Error{Code: CodeInvalidTransition, Message: "the return window has closed"}
+	}
+
+	existing, err := s.repo.ListReturns(ctx, parent.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, &Error{Code: CodeInvalidTransition, Message: repository.ErrReturnExists.Error()}
+	}
+
+	createReq := &model.CreateDeliveryRequest{
+		OrderID:           parent.OrderID,
+		ShippingAddress:   parent.ShippingAddress,
+		SlotReservationID: req.SlotReservationID,
+		Type:              model.DeliveryTypeReturn,
+		ParentDeliveryID:  parent.ID,
+		ReturnReason:      req.Reason,
+		ReturnTo:          s.returns.Warehouse,
+	}
+	if req.PickupDate != nil {
+		createReq.ServiceLevel = model.ServiceLevelScheduled
+		createReq.ScheduledFor = req.PickupDate
+	}
+
+	// A concurrent request may have created the return since the check
+	created, err := s.CreateDelivery(ctx, createReq)
+	if errors.Is(err, repository.ErrReturnExists) {
+		return nil, &Error{Code: CodeInvalidTransition, Message: err.Error()}
+	}
+	return created, err
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/geo"
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/route"
+)
+
+// RoutePolicy holds the courier assumptions used when planning routes.
+type RoutePolicy struct {
+	SpeedKmh    float64
+	Detour      float64       // road distance over straight-line distance
+	ServiceTime time.Duration // time spent at each stop
+	Capacity    int           // deliveries per route; 0 means unlimited
+}
+
+func (s *DeliveryService) SetRoutePolicy(policy RoutePolicy) {
+	s.routes = policy
+}
+
+// AssignCourier hands a delivery to a courier. Couriers can see the proofs,
+// labels and PINs of their deliveries, so only staff assign them.
+func (s *DeliveryService) AssignCourier(ctx context.Context, req *model.AssignCourierRequest) (*model.Delivery, error) {
+	if err := requireStaff(req.Actor, "assign couriers"); err != nil {
+		return nil, err
+	}
+
+	// Validate request
+	if req.DeliveryID == "" {
+		return nil, errors.New("delivery_id is required")
+	}
+	if req.CourierID == "" {
+		return nil, errors.New("courier_id is required")
+	}
+
+	delivery, err := s.repo.GetDelivery(ctx, req.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, &Error{Code: CodeNotFound, Message: "delivery not found"}
+	}
+	if err := requireInHouse(delivery, "assign a courier"); err != nil {
+		return nil, err
+	}
+
+	found, err := s.repo.AssignCourier(ctx, req.DeliveryID, req.CourierID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, &Error{Code: CodeNotFound, Message: "delivery not found"}
+	}
+
+	delivery, err = s.repo.GetDelivery(ctx, req.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+	s.refreshCache(ctx, delivery)
+
+	return delivery, nil
+}
+
+// PlanRoute orders a courier's open deliveries into a route and stores it.
+// Each stop's planned arrival becomes the delivery's estimated delivery time.
+func (s *DeliveryService) PlanRoute(ctx context.Context, req *model.PlanRouteRequest) (*model.Route, error) {
+	if err := requireStaff(req.Actor, "plan routes"); err != nil {
+		return nil, err
+	}
+
+	// Validate request
+	if req.CourierID == "" {
+		return nil, errors.New("courier_id is required")
+	}
+	start := geo.Point{Lat: req.StartLatitude, Lng: req.StartLongitude}
+	if !start.Valid() {
+		return nil, errors.New("start coordinates are out of range")
+	}
+	if req.StartAt.IsZero() {
+		req.StartAt = time.Now()
+	}
+
+	deliveries, err := s.repo.ListCourierDeliveries(ctx, req.CourierID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &model.Route{
+		CourierID:      req.CourierID,
+		StartLatitude:  req.StartLatitude,
+		StartLongitude: req.StartLongitude,
+		StartAt:        req.StartAt,
+	}
+
+	// Deliveries without coordinates cannot be placed on a route
+	var stops []route.Stop
+	statuses := make(map[string]string)
+	byID := make(map[string]*model.Delivery)
+	for _, d := range deliveries {
+		point := geo.PointOf(d.ShippingAddress)
+		if point == nil {
+			plan.Unassigned = append(plan.Unassigned, d.ID)
+			continue
+		}
+
+		stop := route.Stop{ID: d.ID, Point: *point, Demand: 1}
+		if d.TimeWindow != nil {
+			stop.WindowStart = d.TimeWindow.Start
+			stop.WindowEnd = d.TimeWindow.End
+		}
+		stops = append(stops, stop)
+		statuses[d.ID] = d.Status
+		byID[d.ID] = d
+	}
+
+	built := route.Build(stops, route.Options{
+		Start:       start,
+		StartAt:     req.StartAt,
+		SpeedKmh:    s.routes.SpeedKmh,
+		Detour:      s.routes.Detour,
+		ServiceTime: s.routes.ServiceTime,
+		Capacity:    s.routes.Capacity,
+	})
+
+	plan.DistanceMeters = built.Distance
+	for i, ps := range built.Stops {
+		plan.Stops = append(plan.Stops, &model.RouteStop{
+			DeliveryID:     ps.Stop.ID,
+			Sequence:       i + 1,
+			PlannedArrival: ps.Arrival,
+			DistanceMeters: ps.Distance,
+		})
+	}
+	for _, stop := range built.Unassigned {
+		plan.Unassigned = append(plan.Unassigned, stop.ID)
+	}
+
+	if err := s.repo.SaveRoute(ctx, plan, statuses); err != nil {
+		return nil, err
+	}
+
+	// Planned arrivals replace the cached estimates
+	for _, stop := range plan.Stops {
+		d := byID[stop.DeliveryID]
+		d.EstimatedDeliveryTime = stop.PlannedArrival
+		s.refreshCache(ctx, d)
+	}
+
+	return plan, nil
+}
+
+func (s *DeliveryService) GetRoute(ctx context.Context, courierID string, actor model.Actor) (*model.Route, error) {
+	if courierID == "" {
+		return nil, errors.New("courier_id is required")
+	}
+	if err := authorizeCourier(actor, courierID, "view other couriers' routes"); err != nil {
+		return nil, err
+	}
+
+	plan, err := s.repo.GetLatestRoute(ctx, courierID)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return nil, &Error{Code: CodeNotFound, Message: "no route planned for courier"}
+	}
+	return plan, nil
+}
+
+func (s *DeliveryService) refreshCache(ctx context.Context, delivery *model.Delivery) {
+	if err := s.cache.CacheDelivery(ctx, delivery); err != nil {
+		// log.Printf("Failed to update delivery cache: %v", err)
+	}
+	if err := s.cache.CacheDeliveryByTracking(ctx, delivery); err != nil {
+		// log.Printf("Failed to update delivery tracking cache: %v", err)
+	}
+}
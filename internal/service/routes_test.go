@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// Authorization runs before anything is loaded, so a bare service is enough
+// to check who is turned away.
+func TestRouteActionsRequireStaff(t *testing.T) {
+	s := &DeliveryService{}
+	ctx := context.Background()
+
+	actors := []model.Actor{
+		{},
+		{ID: "customer-1"},
+		{ID: "courier-1", Role: model.RoleCourier},
+	}
+	for _, actor := range actors {
+		t.Run("assign as "+actor.Role+actor.ID, func(t *testing.T) {
+			_, err := s.AssignCourier(ctx, &model.AssignCourierRequest{DeliveryID: "d1", CourierID: "courier-1", Actor: actor})
+			assertError(t, err, errForbidden)
+		})
+		t.Run("plan as "+actor.Role+actor.ID, func(t *testing.T) {
+			_, err := s.PlanRoute(ctx, &model.PlanRouteRequest{CourierID: "courier-1", Actor: actor})
+			assertError(t, err, errForbidden)
+		})
+	}
+}
+
+func TestAuthorizeCourier(t *testing.T) {
+	tests := []struct {
+		name  string
+		actor model.Actor
+		want  error
+	}{
+		{"the courier", model.Actor{ID: "courier-1", Role: model.RoleCourier}, nil},
+		{"another courier", model.Actor{ID: "courier-2", Role: model.RoleCourier}, errForbidden},
+		{"same ID without the courier role", model.Actor{ID: "courier-1"}, errForbidden},
+		{"support", model.Actor{ID: "agent-1", Role: model.RoleSupport}, nil},
+		{"system", model.Actor{Role: model.RoleSystem}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertError(t, authorizeCourier(tt.actor, "courier-1", "view routes"), tt.want)
+		})
+	}
+}
+
+func TestAuthorizeDelivery(t *testing.T) {
+	delivery := &model.Delivery{ID: "d1", CourierID: "courier-1"}
+	tests := []struct {
+		name     string
+		actor    model.Actor
+		delivery *model.Delivery
+		want     error
+	}{
+		{"assigned courier", model.Actor{ID: "courier-1", Role: model.RoleCourier}, delivery, nil},
+		{"other courier", model.Actor{ID: "courier-2", Role: model.RoleCourier}, delivery, errForbidden},
+		{"courier without an ID on an unassigned delivery", model.Actor{Role: model.RoleCourier}, &model.Delivery{ID: "d2"}, errForbidden},
+		{"admin", model.Actor{Role: model.RoleAdmin}, delivery, nil},
+		{"anonymous", model.Actor{}, delivery, errForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertError(t, authorizeDelivery(tt.actor, tt.delivery), tt.want)
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/repository"
+)
+
+// ScanPolicy controls bulk hub scan ingestion.
+type ScanPolicy struct {
+	MaxItems        int           // scans accepted per request
+	BatchSize       int           // scans written per transaction
+	DuplicateWindow time.Duration // repeats of a scan within this window are duplicates
+	MaxSkew         time.Duration // how far in the future a scanner clock may be
+}
+
+func (s *DeliveryService) SetScanPolicy(policy ScanPolicy) {
+	s.scans = policy
+}
+
+// IngestHubScans records a batch of hub scans from handheld scanners. Each
+// scan is checked on its own and reported in the results; one bad scan does
+// not fail the rest. Scans are applied in the order they were made, repeats
+// of an already recorded scan are reported as duplicates and not recorded
+// again, and scans older than a delivery's latest hub scan are added to its
+// history without moving it back.
+func (s *DeliveryService) IngestHubScans(ctx context.Context, req *model.BulkScanRequest) (*model.BulkScanResponse, error) {
+	if len(req.Scans) == 0 {
+		return nil, errors.New("scans are required")
+	}
+	if s.scans.MaxItems > 0 && len(req.Scans) > s.scans.MaxItems {
+		return nil, fmt.Errorf("at most %d scans can be sent at once", s.scans.MaxItems)
+	}
+	if err := requireStaff(req.Actor, "scan parcels at hubs"); err != nil {
+		return nil, err
+	}
+
+	results, _, err := s.ingestHubScans(ctx, req.Scans)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &model.BulkScanResponse{Results: results}
+	for _, result := range results {
+		switch result.Result {
+		case model.ScanAccepted:
+			resp.Accepted++
+		case model.ScanDuplicate:
+			resp.Duplicates++
+		case model.ScanOutOfOrder:
+			resp.OutOfOrder++
+		case model.ScanRejected:
+			resp.Rejected++
+		}
+	}
+	return resp, nil
+}
+
+type pendingScan struct {
+	index  int
+	scan   *model.HubScan
+	hub    *model.Hub
+	target model.ScanTarget
+}
+
+// ingestHubScans validates and records scans, returning a result for each
+// and, for rejected scans, the error that rejected it.
+func (s *DeliveryService) ingestHubScans(ctx context.Context, scans []model.HubScan) ([]model.ScanResult, []error, error) {
+	now := time.Now()
+	results := make([]model.ScanResult, len(scans))
+	errs := make([]error, len(scans))
+	reject := func(i int, err error) {
+		results[i].Result = model.ScanRejected
+		results[i].Error = err.Error()
+		errs[i] = err
+	}
+
+	hubs, err := s.repo.ListHubs(ctx, "", "", false)
+	if err != nil {
+		return nil, nil, err
+	}
+	hubsByID := make(map[string]*model.Hub, len(hubs))
+	for _, hub := range hubs {
+		hubsByID[hub.ID] = hub
+	}
+
+	trackingNumbers := make([]string, 0, len(scans))
+	for _, scan := range scans {
+		trackingNumbers = append(trackingNumbers, scan.TrackingNumber)
+	}
+	targets, err := s.repo.ResolveScanTargets(ctx, trackingNumbers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Check each scan against the delivery's state
+	var pending []*pendingScan
+	for i := range scans {
+		scan := &scans[i]
+		results[i] = model.ScanResult{Index: i, TrackingNumber: scan.TrackingNumber}
+		if scan.Timestamp.IsZero() {
+			scan.Timestamp = now
+		}
+		if err := s.validateScan(scan, now); err != nil {
+			reject(i, err)
+			continue
+		}
+
+		hub, ok := hubsByID[scan.HubID]
+		if !ok || !hub.Active {
+			reject(i, &Error{Code: CodeNotFound, Message: "hub not found"})
+			continue
+		}
+		target, ok := targets[scan.TrackingNumber]
+		if !ok {
+			reject(i, &Error{Code: CodeNotFound, Message: "no delivery with tracking number " + scan.TrackingNumber})
+			continue
+		}
+		results[i].DeliveryID = target.DeliveryID
+		if !model.CanScanAtHub(target.Status) {
+			reject(i, &Error{Code: CodeInvalidTransition, Message: "cannot scan a delivery with status " + target.Status})
+			continue
+		}
+
+		pending = append(pending, &pendingScan{index: i, scan: scan, hub: hub, target: target})
+	}
+	if len(pending) == 0 {
+		return results, errs, nil
+	}
+
+	// Apply scans in the order they were made
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].scan.Timestamp.Before(pending[j].scan.Timestamp)
+	})
+
+	deliveryIDs := make([]string, 0, len(pending))
+	statuses := make(map[string]string)
+	for _, p := range pending {
+		if _, ok := statuses[p.target.DeliveryID]; !ok {
+			deliveryIDs = append(deliveryIDs, p.target.DeliveryID)
+			statuses[p.target.DeliveryID] = p.target.Status
+		}
+	}
+	history, err := s.repo.ListHubScans(ctx, deliveryIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	seen := make(map[string][]*model.DeliveryEvent)
+	latest := make(map[string]time.Time)
+	for _, event := range history {
+		seen[event.DeliveryID] = append(seen[event.DeliveryID], event)
+		if event.Timestamp.After(latest[event.DeliveryID]) {
+			latest[event.DeliveryID] = event.Timestamp
+		}
+	}
+
+	var writes []repository.HubScanWrite
+	var writeIndexes []int
+	changed := make(map[string]bool)
+	for _, p := range pending {
+		id := p.target.DeliveryID
+		event := &model.DeliveryEvent{
+			DeliveryID: id,
+			ParcelID:   p.target.ParcelID,
+			Status:     model.ScanEventStatus(p.scan.ScanType),
+			HubID:      p.hub.ID,
+			Timestamp:  p.scan.Timestamp,
+		}
+		if s.isDuplicateScan(seen[id], event) {
+			results[p.index].Result = model.ScanDuplicate
+			continue
+		}
+		seen[id] = append(seen[id], event)
+
+		w := repository.HubScanWrite{Scan: p.scan, Hub: p.hub, DeliveryID: id, ParcelID: p.target.ParcelID}
+		if p.scan.Timestamp.Before(latest[id]) {
+			w.HistoryOnly = true
+			results[p.index].Result = model.ScanOutOfOrder
+		} else {
+			latest[id] = p.scan.Timestamp
+			results[p.index].Result = model.ScanAccepted
+		}
+		if next := statusAfterScan(statuses[id]); next != "" {
+			w.Status, w.From = next, scanMovesFrom
+			statuses[id] = next
+			changed[id] = true
+		}
+
+		writes = append(writes, w)
+		writeIndexes = append(writeIndexes, p.index)
+	}
+
+	// Write in batches; if a batch fails, retry its scans one at a time so a
+	// single bad scan does not reject the rest
+	batchSize := s.scans.BatchSize
+	if batchSize <= 0 {
+		batchSize = len(writes)
+	}
+	touched := make(map[string]bool)
+	for start := 0; start < len(writes); start += batchSize {
+		end := start + batchSize
+		if end > len(writes) {
+			end = len(writes)
+		}
+
+		if err := s.repo.RecordHubScans(ctx, writes[start:end]); err == nil {
+			for _, w := range writes[start:end] {
+				touched[w.DeliveryID] = true
+			}
+			continue
+		}
+		for i := start; i < end; i++ {
+			if err := s.repo.RecordHubScans(ctx, writes[i:i+1]); err != nil {
+				reject(writeIndexes[i], err)
+				continue
+			}
+			touched[writes[i].DeliveryID] = true
+		}
+	}
+
+	for id := range touched {
+		delivery := s.reloadCache(ctx, id)
+		if delivery != nil && changed[id] {
+			if err := s.reviseETA(ctx, delivery); err != nil {
+				// log.Printf("Failed to revise delivery ETA: %v", err)
+				continue
+			}
+			s.refreshCache(ctx, delivery)
+		}
+	}
+
+	return results, errs, nil
+}
+
+func (s *DeliveryService) validateScan(scan *model.HubScan, now time.Time) error {
+	if scan.TrackingNumber == "" {
+		return errors.New("tracking_number is required")
+	}
+	if scan.HubID == "" {
+		return errors.New("hub_id is required")
+	}
+	if !model.IsScanType(scan.ScanType) {
+		return errors.New("scan_type must be ARRIVED, SORTED or DEPARTED")
+	}
+	if scan.Timestamp.After(now.Add(s.scans.MaxSkew)) {
+		return errors.New("timestamp is in the future")
+	}
+	return nil
+}
+
+// isDuplicateScan reports whether the same scan of the same parcel at the same
+// hub is already recorded within the duplicate window.
+func (s *DeliveryService) isDuplicateScan(seen []*model.DeliveryEvent, scan *model.DeliveryEvent) bool {
+	for _, event := range seen {
+		if event.ParcelID != scan.ParcelID || event.HubID != scan.HubID || event.Status != scan.Status {
+			continue
+		}
+		gap := event.Timestamp.Sub(scan.Timestamp)
+		if gap < 0 {
+			gap = -gap
+		}
+		if gap <= s.scans.DuplicateWindow {
+			return true
+		}
+	}
+	return false
+}
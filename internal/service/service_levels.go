@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/eta"
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// ServiceLevelPolicy describes how a service level is estimated and where it
+// is offered.
+type ServiceLevelPolicy struct {
+	ETA              eta.Rule
+	AllowedZones     []string      // zone names; empty means every zone
+	StrictCutoff     bool          // reject requests after the cutoff instead of rolling over
+	MaxScheduleAhead time.Duration // how far ahead a scheduled delivery may be booked
+}
+
+// SetServiceLevel makes a service level available and registers its ETA rule.
+func (s *DeliveryService) SetServiceLevel(level string, policy ServiceLevelPolicy) {
+	s.serviceLevels[level] = policy
+	s.eta.SetRule(level, policy.ETA)
+}
+
+func (s *DeliveryService) validateServiceLevel(req *model.CreateDeliveryRequest, zone string, now time.Time) error {
+	policy, ok := s.serviceLevels[req.ServiceLevel]
+	if !ok {
+		return fmt.Errorf("invalid service_level %q", req.ServiceLevel)
+	}
+
+	if len(policy.AllowedZones) > 0 && !containsZone(policy.AllowedZones, zone) {
+		return fmt.Errorf("service_level %s is not available for this destination", req.ServiceLevel)
+	}
+
+	calendar := s.eta.Calendar()
+	if policy.StrictCutoff {
+		if !calendar.IsBusinessDay(now) || !now.Before(calendar.At(now, policy.ETA.Cutoff)) {
+			return fmt.Errorf("service_level %s cutoff has passed", req.ServiceLevel)
+		}
+	}
+
+	if req.ServiceLevel != model.ServiceLevelScheduled {
+		if req.ScheduledFor != nil {
+			return errors.New("scheduled_for is only allowed for SCHEDULED deliveries")
+		}
+		return nil
+	}
+
+	// Scheduled deliveries need a future business day within the booking horizon
+	if req.ScheduledFor == nil {
+		return errors.New("scheduled_for is required for SCHEDULED deliveries")
+	}
+	if !req.ScheduledFor.After(now) {
+		return errors.New("scheduled_for must be in the future")
+	}
+	if policy.MaxScheduleAhead > 0 && req.ScheduledFor.After(now.Add(policy.MaxScheduleAhead)) {
+		return errors.New("scheduled_for is too far in the future")
+	}
+	if !calendar.IsBusinessDay(*req.ScheduledFor) {
+		return errors.New("scheduled_for must be a business day")
+	}
+
+	return nil
+}
+
+func containsZone(zones []string, zone string) bool {
+	for _, z := range zones {
+		if z == zone {
+			return true
+		}
+	}
+	return false
+}
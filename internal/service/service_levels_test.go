@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/eta"
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+func TestValidateServiceLevelZones(t *testing.T) {
+	calendar, err := eta.NewCalendar("UTC", nil)
+	if err != nil {
+		t.Fatalf("NewCalendar: %v", err)
+	}
+	s := &DeliveryService{
+		eta: eta.NewEngine(calendar, nil, eta.Options{}),
+		serviceLevels: map[string]ServiceLevelPolicy{
+			model.ServiceLevelStandard: {},
+			"SAME_DAY":                 {AllowedZones: []string{"Downtown", "Harbour"}},
+		},
+	}
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		level   string
+		zone    string
+		wantErr bool
+	}{
+		{"allowed zone", "SAME_DAY", "Harbour", false},
+		{"other zone", "SAME_DAY", "Uplands", true},
+		{"zone ID instead of name", "SAME_DAY", "6f1c2a9e-1b7d-4c55-9f10-0d5f3f2b8a11", true},
+		{"outside all zones", "SAME_DAY", "", true},
+		{"offered everywhere", model.ServiceLevelStandard, "Uplands", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.validateServiceLevel(&model.CreateDeliveryRequest{ServiceLevel: tt.level}, tt.zone, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+// Sentinels for the service error codes, matched by code only.
+var (
+	errForbidden         = &Error{Code: CodeForbidden}
+	errInvalidTransition = &Error{Code: CodeInvalidTransition}
+)
+
+// assertError checks err against want: nil, a service error with the same
+// code, or an error matched with errors.Is.
+func assertError(t *testing.T, err, want error) {
+	t.Helper()
+	var wantErr, gotErr *Error
+	switch {
+	case want == nil:
+		if err != nil {
+			t.Errorf("error = %v, want nil", err)
+		}
+	case errors.As(want, &wantErr):
+		if !errors.As(err, &gotErr) || gotErr.Code != wantErr.Code {
+			t.Errorf("error = %v, want code %s", err, wantErr.Code)
+		}
+	case !errors.Is(err, want):
+		t.Errorf("error = %v, want %v", err, want)
+	}
+}
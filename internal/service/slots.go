@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/eta"
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/repository"
+	"github.com/bharathbbg/delivery-service/internal/slot"
+)
+
+// SetSlotSchedule enables customer-selectable time slots.
+func (s *DeliveryService) SetSlotSchedule(schedule slot.Schedule) {
+	s.slots = &schedule
+}
+
+func (s *DeliveryService) ListAvailableSlots(ctx context.Context, req *model.ListSlotsRequest) ([]*model.DeliverySlot, error) {
+	if s.slots == nil {
+		return nil, errors.New("time slots are not enabled")
+	}
+
+	now := time.Now()
+	calendar := s.eta.Calendar()
+
+	// Clamp the requested range to the booking horizon
+	from := req.From
+	if from.Before(now) {
+		from = now
+	}
+	days := req.Days
+	if days < 1 || days > s.slots.HorizonDays {
+		days = s.slots.HorizonDays
+	}
+
+	addr, err := s.prepareAddress(ctx, req.Address)
+	if err != nil {
+		return nil, err
+	}
+	zone, err := s.resolveZone(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	day := calendar.At(from, eta.Clock{})
+	to := day.AddDate(0, 0, days)
+
+	// Materialize the default layout for any day that has not been booked yet
+	for d := day; d.Before(to); d = d.AddDate(0, 0, 1) {
+		if err := s.repo.EnsureSlots(ctx, s.slots.SlotsFor(zone.Name, d, calendar)); err != nil {
+			return nil, err
+		}
+	}
+
+	slots, err := s.repo.ListSlots(ctx, zone.Name, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	available := make([]*model.DeliverySlot, 0, len(slots))
+	for _, ds := range slots {
+		if ds.Available > 0 {
+			available = append(available, ds)
+		}
+	}
+
+	return available, nil
+}
+
+// ReserveSlot holds a place in a slot for the actor until the hold expires.
+func (s *DeliveryService) ReserveSlot(ctx context.Context, slotID string, actor model.Actor) (*model.SlotReservation, error) {
+	if s.slots == nil {
+		return nil, errors.New("time slots are not enabled")
+	}
+	if slotID == "" {
+		return nil, errors.New("slot_id is required")
+	}
+
+	reservation, err := s.repo.ReserveSlot(ctx, slotID, actor.ID, s.slots.Hold)
+	if err != nil {
+		return nil, err
+	}
+	if reservation == nil {
+		return nil, errors.New("slot not found")
+	}
+
+	return reservation, nil
+}
+
+// ReleaseSlot gives up a hold before it is used. Only whoever placed the hold
+// and staff can release it, and a reservation bound to a delivery is released
+// by cancelling the delivery.
+func (s *DeliveryService) ReleaseSlot(ctx context.Context, reservationID string, actor model.Actor) error {
+	if reservationID == "" {
+		return errors.New("reservation_id is required")
+	}
+
+	reservation, err := s.repo.GetSlotReservation(ctx, reservationID)
+	if err != nil {
+		return err
+	}
+	if reservation == nil {
+		return repository.ErrReservationNotFound
+	}
+	if err := canReleaseSlot(actor, reservation); err != nil {
+		return err
+	}
+	return s.repo.ReleaseSlotReservation(ctx, reservationID)
+}
+
+func canReleaseSlot(actor model.Actor, reservation *model.SlotReservation) error {
+	if actor.ID == "" || actor.ID != reservation.HeldBy {
+		if err := requireStaff(actor, "release this reservation"); err != nil {
+			return err
+		}
+	}
+	switch reservation.Status {
+	case model.ReservationHeld:
+		return nil
+	case model.ReservationConfirmed:
+		return &Error{Code: CodeInvalidTransition, Message: "reservation is bound to a delivery; cancel the delivery to free the slot"}
+	}
+	return repository.ErrReservationExpired
+}
+
+// applySlotReservation checks that a held reservation can be used for the new
+// delivery and pins the request's schedule to the end of the slot.
+func (s *DeliveryService) applySlotReservation(ctx context.Context, req *model.CreateDeliveryRequest, zoneName string) error {
+	if req.ScheduledFor != nil {
+		return errors.New("scheduled_for cannot be combined with a slot reservation")
+	}
+
+	reservation, err := s.repo.GetSlotReservation(ctx, req.SlotReservationID)
+	if err != nil {
+		return err
+	}
+	if reservation == nil {
+		return errors.New("slot reservation not found")
+	}
+	if reservation.Status != model.ReservationHeld || !reservation.ExpiresAt.After(time.Now()) {
+		return errors.New("slot reservation has expired")
+	}
+	if reservation.Slot.Zone != zoneName {
+		return errors.New("slot reservation does not cover the shipping address")
+	}
+
+	if req.ServiceLevel == "" {
+		req.ServiceLevel = model.ServiceLevelScheduled
+	}
+	slotEnd := reservation.Slot.EndsAt
+	req.ScheduledFor = &slotEnd
+
+	return nil
+}
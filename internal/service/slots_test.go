@@ -0,0 +1,36 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/repository"
+)
+
+func TestCanReleaseSlot(t *testing.T) {
+	held := &model.SlotReservation{ID: "r1", Status: model.ReservationHeld, HeldBy: "customer-1"}
+	confirmed := &model.SlotReservation{ID: "r2", Status: model.ReservationConfirmed, HeldBy: "customer-1", DeliveryID: "d1"}
+	released := &model.SlotReservation{ID: "r3", Status: model.ReservationReleased, HeldBy: "customer-1"}
+	anonymous := &model.SlotReservation{ID: "r4", Status: model.ReservationHeld}
+
+	tests := []struct {
+		name        string
+		actor       model.Actor
+		reservation *model.SlotReservation
+		want        error
+	}{
+		{"holder", model.Actor{ID: "customer-1"}, held, nil},
+		{"staff", model.Actor{ID: "agent-7", Role: model.RoleSupport}, held, nil},
+		{"someone else", model.Actor{ID: "customer-2"}, held, errForbidden},
+		{"courier", model.Actor{ID: "courier-1", Role: model.RoleCourier}, held, errForbidden},
+		{"no actor on an anonymous hold", model.Actor{}, anonymous, errForbidden},
+		{"holder of a confirmed reservation", model.Actor{ID: "customer-1"}, confirmed, errInvalidTransition},
+		{"staff on a confirmed reservation", model.Actor{Role: model.RoleAdmin}, confirmed, errInvalidTransition},
+		{"already released", model.Actor{ID: "customer-1"}, released, repository.ErrReservationExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertError(t, canReleaseSlot(tt.actor, tt.reservation), tt.want)
+		})
+	}
+}
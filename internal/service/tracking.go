@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// TrackDeliveryView returns a delivery's tracking history together with its
+// failed attempts and the linked outbound or return legs.
+func (s *DeliveryService) TrackDeliveryView(ctx context.Context, trackingNumber string) (*model.TrackingResponse, error) {
+	trackingNumber, err := s.checkTrackingNumber(trackingNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, events, err := s.TrackDelivery(ctx, trackingNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parcel tracking numbers resolve to their delivery
+	var parcel *model.Parcel
+	if delivery == nil {
+		parcel, err = s.repo.GetParcelByTracking(ctx, trackingNumber)
+		if err != nil {
+			return nil, err
+		}
+		if parcel != nil {
+			parent, err := s.GetDelivery(ctx, parcel.DeliveryID)
+			if err != nil {
+				return nil, err
+			}
+			if parent != nil {
+				delivery, events, err = s.TrackDelivery(ctx, parent.TrackingNumber)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	if delivery == nil {
+		return nil, &Error{Code: CodeNotFound, Message: "delivery not found"}
+	}
+
+	resp := &model.TrackingResponse{
+		Delivery: delivery,
+		Parcel:   parcel,
+		Events:   events,
+		Attempts: model.AttemptHistory(events),
+	}
+
+	if delivery.ParentDeliveryID != "" {
+		parent, err := s.GetDelivery(ctx, delivery.ParentDeliveryID)
+		if err != nil {
+			return nil, err
+		}
+		if parent != nil {
+			leg, err := s.trackingLeg(ctx, parent)
+			if err != nil {
+				return nil, err
+			}
+			resp.Outbound = leg
+		}
+		return resp, nil
+	}
+
+	returns, err := s.repo.ListReturns(ctx, delivery.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range returns {
+		leg, err := s.trackingLeg(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		resp.Returns = append(resp.Returns, leg)
+	}
+
+	return resp, nil
+}
+
+func (s *DeliveryService) trackingLeg(ctx context.Context, delivery *model.Delivery) (*model.TrackingLeg, error) {
+	events, err := s.cache.GetCachedDeliveryEvents(ctx, delivery.ID)
+	if err != nil || events == nil {
+		events, err = s.repo.ListDeliveryEvents(ctx, delivery.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &model.TrackingLeg{Delivery: delivery, Events: events}, nil
+}
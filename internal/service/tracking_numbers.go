@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/repository"
+	"github.com/bharathbbg/delivery-service/internal/tracknum"
+)
+
+// maxTrackingNumberAttempts bounds how many numbers CreateDelivery draws when
+// the ones it drew are already taken.
+const maxTrackingNumberAttempts = 5
+
+// legacyTrackingNumbers matches the numbers issued under prefix before
+// tracking number schemes, which stay trackable.
+func legacyTrackingNumbers(prefix string) *regexp.Regexp {
+	return regexp.MustCompile(`^` + regexp.QuoteMeta(prefix) + `[0-9a-f]{8}(-[0-9]{2,})?$`)
+}
+
+// SetTrackingNumberScheme sets how new tracking numbers are drawn. prefix is
+// the one numbers were issued under before, so that those stay trackable.
+func (s *DeliveryService) SetTrackingNumberScheme(scheme tracknum.Scheme, prefix string) {
+	s.trackNumbers = scheme
+	s.legacyNumbers = legacyTrackingNumbers(prefix)
+}
+
+// createWithTrackingNumber saves a new delivery under a freshly generated
+// tracking number, drawing again if the number is taken.
+func (s *DeliveryService) createWithTrackingNumber(ctx context.Context, delivery *model.Delivery) (*model.Delivery, error) {
+	for attempt := 1; ; attempt++ {
+		trackingNumber, err := s.trackNumbers.Generate()
+		if err != nil {
+			return nil, err
+		}
+		delivery.TrackingNumber = trackingNumber
+
+		saved, err := s.repo.CreateDelivery(ctx, delivery)
+		if errors.Is(err, repository.ErrTrackingNumberTaken) && attempt < maxTrackingNumberAttempts {
+			continue
+		}
+		return saved, err
+	}
+}
+
+// checkTrackingNumber returns the canonical form of a tracking number, so
+// that mistyped numbers are turned away without a lookup.
+func (s *DeliveryService) checkTrackingNumber(trackingNumber string) (string, error) {
+	trackingNumber = strings.TrimSpace(trackingNumber)
+	if s.legacyNumbers.MatchString(trackingNumber) {
+		return trackingNumber, nil
+	}
+	canonical, ok := s.trackNumbers.Parse(trackingNumber)
+	if !ok {
+		return "", &Error{Code: CodeInvalidTrackingNumber, Message: "tracking number is not valid"}
+	}
+	return canonical, nil
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/bharathbbg/delivery-service/internal/tracknum"
+)
+
+func TestCheckTrackingNumber(t *testing.T) {
+	s := &DeliveryService{}
+	s.SetTrackingNumberScheme(tracknum.Crockford{Prefix: "SHIP.", Length: 4}, "SHIP.")
+	errInvalid := &Error{Code: CodeInvalidTrackingNumber}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+		err  error
+	}{
+		{"current", " ship.0014u ", "SHIP.0014U", nil},
+		{"current with a wrong check symbol", "SHIP.00140", "", errInvalid},
+		{"legacy", "SHIP.1a2b3c4d", "SHIP.1a2b3c4d", nil},
+		{"legacy parcel", "SHIP.1a2b3c4d-02", "SHIP.1a2b3c4d-02", nil},
+		{"legacy under another prefix", "TRK-1a2b3c4d", "", errInvalid},
+		{"prefix taken literally", "SHIPX1a2b3c4d", "", errInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.checkTrackingNumber(tt.in)
+			assertError(t, err, tt.err)
+			if got != tt.want {
+				t.Errorf("checkTrackingNumber(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bharathbbg/delivery-service/internal/geo"
+	"github.com/bharathbbg/delivery-service/internal/model"
+	"github.com/bharathbbg/delivery-service/internal/repository"
+	"github.com/bharathbbg/delivery-service/internal/zone"
+)
+
+func (s *DeliveryService) CheckServiceability(ctx context.Context, addr model.Address) (*model.ServiceabilityResponse, error) {
+	addr, err := s.prepareAddress(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	z, err := s.zones.Resolve(ctx, addr, geo.PointOf(addr))
+	if err != nil {
+		return nil, err
+	}
+	return &model.ServiceabilityResponse{Serviceable: z != nil, Zone: z}, nil
+}
+
+// resolveZone returns the active zone covering the address, or a
+// CodeNotServiceable error if there is none.
+func (s *DeliveryService) resolveZone(ctx context.Context, addr model.Address) (*model.Zone, error) {
+	z, err := s.zones.Resolve(ctx, addr, geo.PointOf(addr))
+	if err != nil {
+		return nil, err
+	}
+	if z == nil {
+		return nil, &Error{Code: CodeNotServiceable, Message: "address is outside all delivery zones"}
+	}
+	return z, nil
+}
+
+func (s *DeliveryService) CreateZone(ctx context.Context, z *model.Zone) (*model.Zone, error) {
+	z.Country = s.addresses.NormalizeCountry(z.Country)
+	if err := validateZone(z); err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.CreateZone(ctx, z)
+	if err != nil {
+		if errors.Is(err, repository.ErrZoneExists) {
+			return nil, &Error{Code: CodeInvalidZone, Message: err.Error()}
+		}
+		return nil, err
+	}
+	s.zones.Invalidate()
+
+	return created, nil
+}
+
+// UpdateZone changes a zone's coverage. Its name cannot change: slots,
+// service level allow-lists, carrier rules and rate cards refer to zones by
+// name.
+func (s *DeliveryService) UpdateZone(ctx context.Context, z *model.Zone) (*model.Zone, error) {
+	if z.ID == "" {
+		return nil, errors.New("zone_id is required")
+	}
+	z.Country = s.addresses.NormalizeCountry(z.Country)
+	if err := validateZone(z); err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.GetZone(ctx, z.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, errors.New("zone not found")
+	}
+	if current.Name != z.Name {
+		return nil, &Error{Code: CodeInvalidZone, Message: "zone names cannot be changed"}
+	}
+
+	updated, err := s.repo.UpdateZone(ctx, z)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, errors.New("zone not found")
+	}
+	s.zones.Invalidate()
+
+	return updated, nil
+}
+
+func (s *DeliveryService) ListZones(ctx context.Context) ([]*model.Zone, error) {
+	return s.repo.ListZones(ctx, false)
+}
+
+func validateZone(z *model.Zone) error {
+	if z.Name == "" {
+		return &Error{Code: CodeInvalidZone, Message: "name is required"}
+	}
+	if z.Country == "" {
+		return &Error{Code: CodeInvalidZone, Message: "country is required"}
+	}
+	if len(z.PostalCodes) == 0 && len(z.PostalPrefixes) == 0 && len(z.Boundary) == 0 {
+		return &Error{Code: CodeInvalidZone, Message: "zone needs postal codes, postal prefixes or a boundary"}
+	}
+	if len(z.Boundary) > 0 {
+		if err := zone.ValidateBoundary(z.Boundary); err != nil {
+			return &Error{Code: CodeInvalidZone, Message: err.Error()}
+		}
+	}
+	return nil
+}
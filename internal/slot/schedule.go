@@ -0,0 +1,73 @@
+package slot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/eta"
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// Window is a daily delivery window, e.g. 14:00-16:00.
+type Window struct {
+	Start eta.Clock
+	End   eta.Clock
+}
+
+// ParseWindows parses a comma-separated list such as "09:00-12:00,14:00-16:00".
+func ParseWindows(s string) ([]Window, error) {
+	var windows []Window
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		bounds := strings.SplitN(part, "-", 2)
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("invalid slot window %q", part)
+		}
+		start, err := eta.ParseClock(strings.TrimSpace(bounds[0]))
+		if err != nil {
+			return nil, err
+		}
+		end, err := eta.ParseClock(strings.TrimSpace(bounds[1]))
+		if err != nil {
+			return nil, err
+		}
+		if end.Hour*60+end.Minute <= start.Hour*60+start.Minute {
+			return nil, fmt.Errorf("slot window %q ends before it starts", part)
+		}
+
+		windows = append(windows, Window{Start: start, End: end})
+	}
+	return windows, nil
+}
+
+// Schedule is the default slot layout offered in every zone on business days.
+type Schedule struct {
+	Windows     []Window
+	Capacity    int           // max deliveries per slot
+	Hold        time.Duration // how long a reservation is held during checkout
+	HorizonDays int           // how many days ahead slots are offered
+}
+
+// SlotsFor lays out the schedule's windows for a zone on the given day. Days
+// that are not business days have no slots.
+func (s Schedule) SlotsFor(zone string, day time.Time, calendar *eta.Calendar) []*model.DeliverySlot {
+	if !calendar.IsBusinessDay(day) {
+		return nil
+	}
+
+	slots := make([]*model.DeliverySlot, 0, len(s.Windows))
+	for _, w := range s.Windows {
+		slots = append(slots, &model.DeliverySlot{
+			Zone:     zone,
+			StartsAt: calendar.At(day, w.Start),
+			EndsAt:   calendar.At(day, w.End),
+			Capacity: s.Capacity,
+		})
+	}
+	return slots
+}
@@ -0,0 +1,53 @@
+package tracking
+
+import (
+	"sort"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/geo"
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+// Downsampler decides which pings are kept in the location history. A ping is
+// kept once enough time has passed or the courier has moved far enough since
+// the last kept ping.
+type Downsampler struct {
+	MinInterval time.Duration
+	MinDistance float64 // meters
+}
+
+// Select returns the pings to keep, in recording order, given the last ping
+// kept for the same courier (nil if none). Pings older than last are dropped.
+func (d Downsampler) Select(last *model.CourierLocation, pings []model.CourierLocation) []model.CourierLocation {
+	sorted := make([]model.CourierLocation, len(pings))
+	copy(sorted, pings)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].RecordedAt.Before(sorted[j].RecordedAt) })
+
+	var kept []model.CourierLocation
+	for _, p := range sorted {
+		if last != nil {
+			if !p.RecordedAt.After(last.RecordedAt) {
+				continue
+			}
+			moved := geo.Distance(
+				geo.Point{Lat: last.Latitude, Lng: last.Longitude},
+				geo.Point{Lat: p.Latitude, Lng: p.Longitude},
+			)
+			if p.RecordedAt.Sub(last.RecordedAt) < d.MinInterval && moved < d.MinDistance {
+				continue
+			}
+		}
+		kept = append(kept, p)
+		last = &kept[len(kept)-1]
+	}
+	return kept
+}
+
+// Policy controls location ingestion.
+type Policy struct {
+	Downsampler  Downsampler
+	LastKnownTTL time.Duration // how long a courier's last position stays visible
+	MaxAge       time.Duration // pings recorded longer ago than this are rejected
+	MaxSkew      time.Duration // pings recorded further in the future than this are rejected
+	MaxItems     int           // pings accepted per request
+}
@@ -0,0 +1,113 @@
+// Package tracknum generates tracking numbers and checks them before they are
+// looked up.
+package tracknum
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Scheme generates tracking numbers and recognizes the ones it generated.
+type Scheme interface {
+	// Generate returns a new random tracking number.
+	Generate() (string, error)
+	// Parse returns the canonical form of a delivery or parcel tracking
+	// number, or false if it is malformed or its check symbol is wrong.
+	Parse(trackingNumber string) (string, bool)
+}
+
+// alphabet is Crockford's base32: digits and capitals without I, L, O and U.
+const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// checkAlphabet adds Crockford's five check-only symbols to alphabet, one for
+// each value of a mod 37 check.
+const checkAlphabet = alphabet + "*~$=U"
+
+// Crockford numbers are a prefix, Length random base32 symbols and a mod 37
+// check symbol, e.g. TRK-7M3QZ0K4VD*. As 37 is prime, the check catches every
+// single-symbol typo and every swap of neighbouring symbols. Parcels append
+// "-NN" to their delivery's number.
+type Crockford struct {
+	Prefix string
+	Length int
+	Random io.Reader // defaults to crypto/rand
+}
+
+func (c Crockford) Generate() (string, error) {
+	if c.Length <= 0 {
+		return "", errors.New("tracking number length must be positive")
+	}
+	random := c.Random
+	if random == nil {
+		random = rand.Reader
+	}
+
+	buf := make([]byte, c.Length)
+	if _, err := io.ReadFull(random, buf); err != nil {
+		return "", fmt.Errorf("error generating tracking number: %w", err)
+	}
+	body := make([]byte, c.Length)
+	for i, b := range buf {
+		body[i] = alphabet[b%32] // 256 is a multiple of 32, so this is unbiased
+	}
+	return c.Prefix + string(body) + string(checkSymbol(string(body))), nil
+}
+
+func (c Crockford) Parse(trackingNumber string) (string, bool) {
+	s := strings.ToUpper(strings.TrimSpace(trackingNumber))
+	prefix := strings.ToUpper(c.Prefix)
+	if !strings.HasPrefix(s, prefix) {
+		return "", false
+	}
+	s = s[len(prefix):]
+
+	// Split off a parcel suffix
+	suffix := ""
+	if i := strings.LastIndexByte(s, '-'); i >= 0 {
+		suffix = s[i:]
+		if len(suffix) < 3 || strings.Trim(suffix[1:], "0123456789") != "" {
+			return "", false
+		}
+		s = s[:i]
+	}
+
+	if len(s) != c.Length+1 {
+		return "", false
+	}
+	body := []byte(s[:c.Length])
+	for i, r := range body {
+		body[i] = normalize(r)
+		if strings.IndexByte(alphabet, body[i]) < 0 {
+			return "", false
+		}
+	}
+	check := normalize(s[c.Length])
+	if check != checkSymbol(string(body)) {
+		return "", false
+	}
+	return c.Prefix + string(body) + string(check) + suffix, true
+}
+
+// normalize maps the characters Crockford's base32 reads as look-alikes.
+func normalize(b byte) byte {
+	switch b {
+	case 'O':
+		return '0'
+	case 'I', 'L':
+		return '1'
+	}
+	return b
+}
+
+// checkSymbol computes the check symbol for body: the value of body, read as
+// a base32 number, mod 37.
+func checkSymbol(body string) byte {
+	sum := 0
+	for i := 0; i < len(body); i++ {
+		sum = (sum*32 + strings.IndexByte(alphabet, body[i])) % 37
+	}
+	return checkAlphabet[sum]
+}
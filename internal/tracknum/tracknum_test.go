@@ -0,0 +1,112 @@
+package tracknum
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCheckSymbol(t *testing.T) {
+	tests := []struct {
+		body string
+		want byte
+	}{
+		{"0000", '0'},
+		{"1", '1'},
+		{"Z", 'Z'},
+		{"10", '*'}, // 32 needs the first check-only symbol
+		{"14", 'U'}, // 36
+		{"15", '0'}, // 37 wraps around
+		{"7M3QZ0K4VD", '*'},
+	}
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			if got := checkSymbol(tt.body); got != tt.want {
+				t.Errorf("checkSymbol(%q) = %c, want %c", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckSymbolCatchesTypos(t *testing.T) {
+	const body = "7M3QZ0K4VD"
+	check := checkSymbol(body)
+
+	for i := range body {
+		for _, r := range alphabet {
+			if byte(r) == body[i] {
+				continue
+			}
+			typo := body[:i] + string(r) + body[i+1:]
+			if checkSymbol(typo) == check {
+				t.Errorf("substitution %q accepted", typo)
+			}
+		}
+	}
+	for i := 0; i+1 < len(body); i++ {
+		swapped := body[:i] + string(body[i+1]) + string(body[i]) + body[i+2:]
+		if swapped != body && checkSymbol(swapped) == check {
+			t.Errorf("swap %q accepted", swapped)
+		}
+	}
+}
+
+func TestCrockfordGenerate(t *testing.T) {
+	c := Crockford{Prefix: "TRK-", Length: 10, Random: bytes.NewReader([]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 32 + 9})}
+	got, err := c.Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	want := "TRK-0123456789" + string(checkSymbol("0123456789"))
+	if got != want {
+		t.Errorf("Generate() = %q, want %q", got, want)
+	}
+	if parsed, ok := c.Parse(got); !ok || parsed != got {
+		t.Errorf("Parse(%q) = %q, %v", got, parsed, ok)
+	}
+
+	if _, err := (Crockford{Length: 10, Random: bytes.NewReader([]byte{1, 2})}).Generate(); err == nil {
+		t.Error("Generate succeeded with too little randomness")
+	}
+	if _, err := (Crockford{}).Generate(); err == nil {
+		t.Error("Generate succeeded without a length")
+	}
+}
+
+func TestCrockfordParse(t *testing.T) {
+	c := Crockford{Prefix: "TRK-", Length: 10}
+	const body = "7M3QZ0K4VD"
+	number := "TRK-" + body + string(checkSymbol(body))
+	lower := strings.ToLower(number)
+
+	tests := []struct {
+		name string
+		in   string
+		want string // "" when rejected
+	}{
+		{"canonical", number, number},
+		{"lower case and spaces", "  " + lower + " ", number},
+		{"look-alikes", strings.Replace(number, "0", "o", 1), number},
+		{"parcel", number + "-02", number + "-02"},
+		{"parcel with long index", number + "-123", number + "-123"},
+		{"short parcel suffix", number + "-2", ""},
+		{"non-numeric parcel suffix", number + "-AB", ""},
+		{"wrong prefix", "ABC-" + body + string(checkSymbol(body)), ""},
+		{"missing prefix", body + string(checkSymbol(body)), ""},
+		{"too short", "TRK-" + body, ""},
+		{"too long", number + "0", ""},
+		{"outside alphabet", "TRK-7M3QZ0K4VU" + string(checkSymbol("7M3QZ0K4VU")), ""},
+		{"typo", "TRK-7M3QZ0K4VE" + string(checkSymbol(body)), ""},
+		{"wrong check symbol", "TRK-" + body + "U", ""},
+		{"check-only symbol in body", "TRK-7M3QZ0K4V*" + string(checkSymbol(body)), ""},
+		{"lower case check-only symbol", "TRK-00000000" + "14u", "TRK-0000000014U"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := c.Parse(tt.in)
+			if ok != (tt.want != "") || got != tt.want {
+				t.Errorf("Parse(%q) = %q, %v, want %q", tt.in, got, ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package zone
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/bharathbbg/delivery-service/internal/geo"
+)
+
+// polygon is a list of linear rings; the first is the outer boundary and the
+// rest are holes. Ring positions are GeoJSON [lng, lat] pairs.
+type polygon [][][2]float64
+
+type geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// parseBoundary accepts a GeoJSON Polygon or MultiPolygon geometry, or a
+// Feature wrapping one.
+func parseBoundary(data []byte) ([]polygon, error) {
+	var g struct {
+		geometry
+		Geometry *geometry `json:"geometry"`
+	}
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %w", err)
+	}
+
+	geom := g.geometry
+	if g.Type == "Feature" {
+		if g.Geometry == nil {
+			return nil, fmt.Errorf("GeoJSON feature has no geometry")
+		}
+		geom = *g.Geometry
+	}
+
+	switch geom.Type {
+	case "Polygon":
+		var p polygon
+		if err := json.Unmarshal(geom.Coordinates, &p); err != nil {
+			return nil, fmt.Errorf("invalid Polygon coordinates: %w", err)
+		}
+		return []polygon{p}, nil
+	case "MultiPolygon":
+		var mp []polygon
+		if err := json.Unmarshal(geom.Coordinates, &mp); err != nil {
+			return nil, fmt.Errorf("invalid MultiPolygon coordinates: %w", err)
+		}
+		return mp, nil
+	default:
+		return nil, fmt.Errorf("unsupported GeoJSON geometry %q", geom.Type)
+	}
+}
+
+func (p polygon) contains(pt geo.Point) bool {
+	if len(p) == 0 || !ringContains(p[0], pt) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if ringContains(hole, pt) {
+			return false
+		}
+	}
+	return true
+}
+
+// ringContains is the even-odd ray casting test.
+func ringContains(ring [][2]float64, pt geo.Point) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > pt.Lat) != (yj > pt.Lat) && pt.Lng < (xj-xi)*(pt.Lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,80 @@
+package zone
+
+import (
+	"testing"
+
+	"github.com/bharathbbg/delivery-service/internal/geo"
+)
+
+// A 10x10 degree square with a 2x2 hole in the middle.
+const squareWithHole = `{"type":"Polygon","coordinates":[
+	[[0,0],[10,0],[10,10],[0,10],[0,0]],
+	[[4,4],[6,4],[6,6],[4,6],[4,4]]
+]}`
+
+func TestParseBoundary(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		polygons int
+		wantErr  bool
+	}{
+		{name: "polygon", in: squareWithHole, polygons: 1},
+		{
+			name:     "multipolygon",
+			in:       `{"type":"MultiPolygon","coordinates":[[[[0,0],[1,0],[1,1],[0,0]]],[[[5,5],[6,5],[6,6],[5,5]]]]}`,
+			polygons: 2,
+		},
+		{
+			name:     "feature",
+			in:       `{"type":"Feature","properties":{},"geometry":` + squareWithHole + `}`,
+			polygons: 1,
+		},
+		{name: "feature without geometry", in: `{"type":"Feature"}`, wantErr: true},
+		{name: "point", in: `{"type":"Point","coordinates":[1,2]}`, wantErr: true},
+		{name: "bad coordinates", in: `{"type":"Polygon","coordinates":[1,2]}`, wantErr: true},
+		{name: "not json", in: `POLYGON((0 0,1 0,1 1,0 0))`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseBoundary([]byte(tt.in))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseBoundary error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.polygons {
+				t.Errorf("parseBoundary returned %d polygons, want %d", len(got), tt.polygons)
+			}
+		})
+	}
+}
+
+func TestPolygonContains(t *testing.T) {
+	polygons, err := parseBoundary([]byte(squareWithHole))
+	if err != nil {
+		t.Fatalf("parseBoundary: %v", err)
+	}
+	p := polygons[0]
+
+	tests := []struct {
+		name string
+		pt   geo.Point
+		want bool
+	}{
+		{"inside", geo.Point{Lat: 2, Lng: 2}, true},
+		{"in the hole", geo.Point{Lat: 5, Lng: 5}, false},
+		{"between hole and edge", geo.Point{Lat: 5, Lng: 8}, true},
+		{"outside", geo.Point{Lat: 5, Lng: 11}, false},
+		{"latitude and longitude not swapped", geo.Point{Lat: 11, Lng: 5}, false},
+		{"below", geo.Point{Lat: -1, Lng: 5}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.contains(tt.pt); got != tt.want {
+				t.Errorf("contains(%+v) = %v, want %v", tt.pt, got, tt.want)
+			}
+		})
+	}
+	if (polygon{}).contains(geo.Point{}) {
+		t.Error("empty polygon contains a point")
+	}
+}
@@ -0,0 +1,150 @@
+package zone
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bharathbbg/delivery-service/internal/geo"
+	"github.com/bharathbbg/delivery-service/internal/model"
+)
+
+type compiledZone struct {
+	zone     *model.Zone
+	boundary []polygon
+}
+
+// Matcher assigns addresses to zones. An exact postal code match wins over a
+// boundary match, which wins over the longest matching postal prefix.
+type Matcher struct {
+	byPostal map[string]*model.Zone // keyed by country + "|" + postal code
+	zones    []compiledZone
+}
+
+func NewMatcher(zones []*model.Zone) (*Matcher, error) {
+	m := &Matcher{byPostal: make(map[string]*model.Zone)}
+
+	for _, z := range zones {
+		if !z.Active {
+			continue
+		}
+
+		cz := compiledZone{zone: z}
+		if len(z.Boundary) > 0 {
+			boundary, err := parseBoundary(z.Boundary)
+			if err != nil {
+				return nil, err
+			}
+			cz.boundary = boundary
+		}
+		m.zones = append(m.zones, cz)
+
+		for _, code := range z.PostalCodes {
+			m.byPostal[postalKey(z.Country, code)] = z
+		}
+	}
+
+	return m, nil
+}
+
+// Match returns the zone for the address, or nil if it is outside every active
+// zone. The point is optional and only used for boundary matching.
+func (m *Matcher) Match(addr model.Address, pt *geo.Point) *model.Zone {
+	if z, ok := m.byPostal[postalKey(addr.Country, addr.ZipCode)]; ok {
+		return z
+	}
+
+	if pt != nil {
+		for _, cz := range m.zones {
+			if !sameCountry(cz.zone.Country, addr.Country) {
+				continue
+			}
+			for _, p := range cz.boundary {
+				if p.contains(*pt) {
+					return cz.zone
+				}
+			}
+		}
+	}
+
+	postal := normalizePostal(addr.ZipCode)
+	var best *model.Zone
+	bestLen := 0
+	for _, cz := range m.zones {
+		if !sameCountry(cz.zone.Country, addr.Country) {
+			continue
+		}
+		for _, prefix := range cz.zone.PostalPrefixes {
+			prefix = normalizePostal(prefix)
+			if len(prefix) > bestLen && strings.HasPrefix(postal, prefix) {
+				best, bestLen = cz.zone, len(prefix)
+			}
+		}
+	}
+	return best
+}
+
+// ValidateBoundary reports whether a zone boundary is usable GeoJSON.
+func ValidateBoundary(data []byte) error {
+	_, err := parseBoundary(data)
+	return err
+}
+
+func postalKey(country, postal string) string {
+	return strings.ToUpper(strings.TrimSpace(country)) + "|" + normalizePostal(postal)
+}
+
+func normalizePostal(postal string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(postal))
+}
+
+func sameCountry(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// Source loads the zones a Directory matches against.
+type Source interface {
+	ListZones(ctx context.Context, activeOnly bool) ([]*model.Zone, error)
+}
+
+// Directory caches a Matcher built from the source and rebuilds it once the
+// refresh interval has passed.
+type Directory struct {
+	source  Source
+	refresh time.Duration
+
+	mu       sync.Mutex
+	matcher  *Matcher
+	loadedAt time.Time
+}
+
+func NewDirectory(source Source, refresh time.Duration) *Directory {
+	return &Directory{source: source, refresh: refresh}
+}
+
+func (d *Directory) Resolve(ctx context.Context, addr model.Address, pt *geo.Point) (*model.Zone, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.matcher == nil || time.Since(d.loadedAt) > d.refresh {
+		zones, err := d.source.ListZones(ctx, true)
+		if err != nil {
+			return nil, err
+		}
+		matcher, err := NewMatcher(zones)
+		if err != nil {
+			return nil, err
+		}
+		d.matcher, d.loadedAt = matcher, time.Now()
+	}
+
+	return d.matcher.Match(addr, pt), nil
+}
+
+// Invalidate forces the next Resolve to reload zones.
+func (d *Directory) Invalidate() {
+	d.mu.Lock()
+	d.matcher = nil
+	d.mu.Unlock()
+}
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS eta_revisions (
+    id VARCHAR(36) PRIMARY KEY,
+    delivery_id VARCHAR(36) NOT NULL,
+    estimated_delivery_time TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (delivery_id) REFERENCES deliveries(id) ON DELETE CASCADE
+);
+
+CREATE INDEX eta_revision_delivery_idx ON eta_revisions(delivery_id, created_at);
+CREATE INDEX delivery_event_status_idx ON delivery_events(status, delivery_id);
@@ -0,0 +1,2 @@
+ALTER TABLE deliveries ADD COLUMN IF NOT EXISTS service_level VARCHAR(20) NOT NULL DEFAULT 'STANDARD';
+ALTER TABLE deliveries ADD COLUMN IF NOT EXISTS scheduled_for TIMESTAMP;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS delivery_slots (
+    id VARCHAR(36) PRIMARY KEY,
+    zone VARCHAR(50) NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    capacity INT NOT NULL,
+    UNIQUE (zone, starts_at)
+);
+
+CREATE TABLE IF NOT EXISTS slot_reservations (
+    id VARCHAR(36) PRIMARY KEY,
+    slot_id VARCHAR(36) NOT NULL,
+    delivery_id VARCHAR(36),
+    status VARCHAR(20) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (slot_id) REFERENCES delivery_slots(id) ON DELETE CASCADE,
+    FOREIGN KEY (delivery_id) REFERENCES deliveries(id) ON DELETE SET NULL
+);
+
+ALTER TABLE deliveries ADD COLUMN IF NOT EXISTS slot_reservation_id VARCHAR(36) REFERENCES slot_reservations(id);
+
+CREATE INDEX slot_reservation_slot_idx ON slot_reservations(slot_id, status);
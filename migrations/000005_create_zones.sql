@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS zones (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    country VARCHAR(100) NOT NULL,
+    postal_codes TEXT[] NOT NULL DEFAULT '{}',
+    postal_prefixes TEXT[] NOT NULL DEFAULT '{}',
+    boundary JSONB,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+ALTER TABLE deliveries ADD COLUMN IF NOT EXISTS zone_id VARCHAR(36) REFERENCES zones(id);
+ALTER TABLE delivery_slots ALTER COLUMN zone TYPE VARCHAR(36);
+
+CREATE INDEX delivery_zone_idx ON deliveries(zone_id);
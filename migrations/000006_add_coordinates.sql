@@ -0,0 +1,5 @@
+ALTER TABLE delivery_addresses ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE delivery_addresses ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+
+ALTER TABLE delivery_events ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE delivery_events ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
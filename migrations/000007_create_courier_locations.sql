@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS courier_locations (
+    id BIGSERIAL PRIMARY KEY,
+    courier_id VARCHAR(36) NOT NULL,
+    latitude DOUBLE PRECISION NOT NULL,
+    longitude DOUBLE PRECISION NOT NULL,
+    accuracy DOUBLE PRECISION,
+    speed DOUBLE PRECISION,
+    heading DOUBLE PRECISION,
+    recorded_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX courier_location_courier_idx ON courier_locations(courier_id, recorded_at);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS geofences (
+    id VARCHAR(36) PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL,
+    ref_id VARCHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    latitude DOUBLE PRECISION NOT NULL,
+    longitude DOUBLE PRECISION NOT NULL,
+    radius DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX geofence_kind_idx ON geofences(kind);
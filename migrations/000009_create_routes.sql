@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS routes (
+    id VARCHAR(36) PRIMARY KEY,
+    courier_id VARCHAR(36) NOT NULL,
+    start_latitude DOUBLE PRECISION NOT NULL,
+    start_longitude DOUBLE PRECISION NOT NULL,
+    start_at TIMESTAMP NOT NULL,
+    distance_meters DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS route_stops (
+    route_id VARCHAR(36) NOT NULL,
+    delivery_id VARCHAR(36) NOT NULL,
+    sequence INT NOT NULL,
+    planned_arrival TIMESTAMP NOT NULL,
+    distance_meters DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (route_id, sequence),
+    FOREIGN KEY (route_id) REFERENCES routes(id) ON DELETE CASCADE,
+    FOREIGN KEY (delivery_id) REFERENCES deliveries(id) ON DELETE CASCADE
+);
+
+CREATE INDEX route_courier_idx ON routes(courier_id, start_at);
+CREATE INDEX delivery_courier_idx ON deliveries(courier_id);
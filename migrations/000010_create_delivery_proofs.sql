@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS delivery_proofs (
+    id VARCHAR(36) PRIMARY KEY,
+    delivery_id VARCHAR(36) NOT NULL UNIQUE,
+    recipient_name VARCHAR(255) NOT NULL,
+    signature_key VARCHAR(255),
+    signature_content_type VARCHAR(100),
+    photo_key VARCHAR(255),
+    photo_content_type VARCHAR(100),
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    otp_result VARCHAR(20) NOT NULL,
+    captured_by VARCHAR(36) NOT NULL,
+    captured_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (delivery_id) REFERENCES deliveries(id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+ALTER TABLE deliveries ADD COLUMN attempt_count INT NOT NULL DEFAULT 0;
+ALTER TABLE deliveries ADD COLUMN next_attempt_at TIMESTAMP;
+
+ALTER TABLE delivery_events ADD COLUMN reason_code VARCHAR(30);
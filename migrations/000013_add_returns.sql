@@ -0,0 +1,6 @@
+ALTER TABLE deliveries ADD COLUMN delivery_type VARCHAR(20) NOT NULL DEFAULT 'OUTBOUND';
+ALTER TABLE deliveries ADD COLUMN parent_delivery_id VARCHAR(36) REFERENCES deliveries(id);
+ALTER TABLE deliveries ADD COLUMN return_reason TEXT;
+ALTER TABLE deliveries ADD COLUMN return_to VARCHAR(50);
+
+CREATE INDEX delivery_parent_idx ON deliveries(parent_delivery_id);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS parcels (
+    id VARCHAR(36) PRIMARY KEY,
+    delivery_id VARCHAR(36) NOT NULL,
+    tracking_number VARCHAR(50) NOT NULL UNIQUE,
+    status VARCHAR(20) NOT NULL,
+    weight_kg DOUBLE PRECISION NOT NULL,
+    length_cm DOUBLE PRECISION NOT NULL DEFAULT 0,
+    width_cm DOUBLE PRECISION NOT NULL DEFAULT 0,
+    height_cm DOUBLE PRECISION NOT NULL DEFAULT 0,
+    declared_value_cents BIGINT NOT NULL DEFAULT 0,
+    currency CHAR(3),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (delivery_id) REFERENCES deliveries(id) ON DELETE CASCADE
+);
+
+CREATE INDEX parcel_delivery_idx ON parcels(delivery_id);
+
+ALTER TABLE delivery_events ADD COLUMN parcel_id VARCHAR(36) REFERENCES parcels(id) ON DELETE CASCADE;
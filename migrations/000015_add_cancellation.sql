@@ -0,0 +1,4 @@
+ALTER TABLE deliveries ADD COLUMN cancel_reason VARCHAR(30);
+ALTER TABLE deliveries ADD COLUMN cancel_note TEXT;
+ALTER TABLE deliveries ADD COLUMN cancelled_by VARCHAR(36);
+ALTER TABLE deliveries ADD COLUMN cancelled_at TIMESTAMP;
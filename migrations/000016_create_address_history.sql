@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS delivery_address_history (
+    id VARCHAR(36) PRIMARY KEY,
+    delivery_id VARCHAR(36) NOT NULL,
+    old_address JSONB NOT NULL,
+    new_address JSONB NOT NULL,
+    old_zone_id VARCHAR(36),
+    new_zone_id VARCHAR(36),
+    reason TEXT,
+    changed_by VARCHAR(36) NOT NULL,
+    changed_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (delivery_id) REFERENCES deliveries(id) ON DELETE CASCADE
+);
+
+CREATE INDEX address_history_delivery_idx ON delivery_address_history(delivery_id, changed_at);
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS pickup_points (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    street VARCHAR(255) NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    state VARCHAR(100) NOT NULL,
+    country VARCHAR(100) NOT NULL,
+    zip_code VARCHAR(20) NOT NULL,
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    zone_id VARCHAR(36) REFERENCES zones(id),
+    capacity INT NOT NULL,
+    hold_days INT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+ALTER TABLE deliveries ADD COLUMN pickup_point_id VARCHAR(36) REFERENCES pickup_points(id);
+ALTER TABLE deliveries ADD COLUMN pickup_expires_at TIMESTAMP;
+
+ALTER TABLE delivery_address_history ADD COLUMN pickup_point_id VARCHAR(36);
+
+CREATE INDEX delivery_pickup_point_idx ON deliveries(pickup_point_id, status);
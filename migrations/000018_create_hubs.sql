@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS hubs (
+    id VARCHAR(36) PRIMARY KEY,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    street VARCHAR(255) NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    state VARCHAR(100) NOT NULL,
+    country VARCHAR(100) NOT NULL,
+    zip_code VARCHAR(20) NOT NULL,
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    zone_ids TEXT[] NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS transit_legs (
+    id VARCHAR(36) PRIMARY KEY,
+    delivery_id VARCHAR(36) NOT NULL REFERENCES deliveries(id),
+    sequence INT NOT NULL,
+    from_hub_id VARCHAR(36) NOT NULL REFERENCES hubs(id),
+    to_hub_id VARCHAR(36) NOT NULL REFERENCES hubs(id),
+    status VARCHAR(20) NOT NULL,
+    departed_at TIMESTAMP,
+    arrived_at TIMESTAMP,
+    UNIQUE (delivery_id, sequence)
+);
+
+ALTER TABLE deliveries ADD COLUMN current_hub_id VARCHAR(36) REFERENCES hubs(id);
+ALTER TABLE delivery_events ADD COLUMN hub_id VARCHAR(36) REFERENCES hubs(id);
+
+CREATE INDEX delivery_current_hub_idx ON deliveries(current_hub_id);
+CREATE INDEX delivery_event_hub_idx ON delivery_events(hub_id);
@@ -0,0 +1,62 @@
+CREATE TABLE IF NOT EXISTS manifests (
+    id VARCHAR(36) PRIMARY KEY,
+    courier_id VARCHAR(36) NOT NULL,
+    route_id VARCHAR(36) REFERENCES routes(id),
+    manifest_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    delivery_count INT NOT NULL,
+    parcel_count INT NOT NULL,
+    weight_kg DOUBLE PRECISION NOT NULL,
+    cod_due_cents BIGINT NOT NULL,
+    signature VARCHAR(64),
+    created_by VARCHAR(36) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    closed_by VARCHAR(36),
+    closed_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS manifest_items (
+    manifest_id VARCHAR(36) NOT NULL REFERENCES manifests(id),
+    sequence INT NOT NULL,
+    delivery_id VARCHAR(36) NOT NULL REFERENCES deliveries(id),
+    tracking_number VARCHAR(50) NOT NULL,
+    order_id VARCHAR(36) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    zip_code VARCHAR(20) NOT NULL,
+    parcels INT NOT NULL,
+    weight_kg DOUBLE PRECISION NOT NULL,
+    cod_due_cents BIGINT NOT NULL,
+    PRIMARY KEY (manifest_id, delivery_id)
+);
+
+-- Set when a closed manifest locks the delivery against reassignment
+ALTER TABLE deliveries ADD COLUMN manifest_id VARCHAR(36) REFERENCES manifests(id);
+
+CREATE INDEX manifest_courier_date_idx ON manifests(courier_id, manifest_date);
+
+-- Closed manifests are signed records and must never change
+CREATE OR REPLACE FUNCTION reject_closed_manifest_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'manifests' AND OLD.status = 'CLOSED' THEN
+        RAISE EXCEPTION 'manifest % is closed', OLD.id;
+    END IF;
+    IF TG_TABLE_NAME = 'manifest_items' AND EXISTS (
+        SELECT 1 FROM manifests WHERE id = OLD.manifest_id AND status = 'CLOSED'
+    ) THEN
+        RAISE EXCEPTION 'manifest % is closed', OLD.manifest_id;
+    END IF;
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER manifests_immutable
+    BEFORE UPDATE OR DELETE ON manifests
+    FOR EACH ROW EXECUTE FUNCTION reject_closed_manifest_change();
+
+CREATE TRIGGER manifest_items_immutable
+    BEFORE UPDATE OR DELETE ON manifest_items
+    FOR EACH ROW EXECUTE FUNCTION reject_closed_manifest_change();
@@ -0,0 +1,59 @@
+ALTER TABLE deliveries ADD COLUMN cod_amount_cents BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE deliveries ADD COLUMN cod_currency VARCHAR(3);
+
+ALTER TABLE manifests ADD COLUMN cod_currency VARCHAR(3);
+ALTER TABLE manifest_items ADD COLUMN cod_currency VARCHAR(3);
+
+CREATE TABLE IF NOT EXISTS cod_collections (
+    id VARCHAR(36) PRIMARY KEY,
+    delivery_id VARCHAR(36) NOT NULL UNIQUE REFERENCES deliveries(id),
+    courier_id VARCHAR(36),
+    due_cents BIGINT NOT NULL,
+    amount_cents BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    reference VARCHAR(100),
+    collected_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS courier_cash_ledger (
+    id VARCHAR(36) PRIMARY KEY,
+    courier_id VARCHAR(36) NOT NULL,
+    entry_type VARCHAR(20) NOT NULL,
+    delivery_id VARCHAR(36) REFERENCES deliveries(id),
+    amount_cents BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    reference VARCHAR(100),
+    recorded_by VARCHAR(36) NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS cod_reconciliations (
+    id VARCHAR(36) PRIMARY KEY,
+    courier_id VARCHAR(36) NOT NULL,
+    reconciliation_date DATE NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    due_cents BIGINT NOT NULL,
+    collected_cents BIGINT NOT NULL,
+    cash_collected_cents BIGINT NOT NULL,
+    deposited_cents BIGINT NOT NULL,
+    manifest_ids TEXT[] NOT NULL DEFAULT '{}',
+    reconciled_by VARCHAR(36) NOT NULL,
+    reconciled_at TIMESTAMP NOT NULL,
+    UNIQUE (courier_id, reconciliation_date, currency)
+);
+
+CREATE TABLE IF NOT EXISTS cod_discrepancies (
+    id VARCHAR(36) PRIMARY KEY,
+    reconciliation_id VARCHAR(36) NOT NULL REFERENCES cod_reconciliations(id) ON DELETE CASCADE,
+    kind VARCHAR(30) NOT NULL,
+    delivery_id VARCHAR(36) REFERENCES deliveries(id),
+    manifest_id VARCHAR(36) REFERENCES manifests(id),
+    expected_cents BIGINT NOT NULL,
+    actual_cents BIGINT NOT NULL
+);
+
+CREATE INDEX cod_collection_courier_idx ON cod_collections(courier_id, collected_at);
+CREATE INDEX cash_ledger_courier_idx ON courier_cash_ledger(courier_id, created_at);
+CREATE INDEX cod_reconciliation_date_idx ON cod_reconciliations(reconciliation_date);
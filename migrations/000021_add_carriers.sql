@@ -0,0 +1,11 @@
+ALTER TABLE deliveries ADD COLUMN carrier VARCHAR(50);
+ALTER TABLE deliveries ADD COLUMN carrier_tracking_number VARCHAR(100);
+
+ALTER TABLE delivery_events ADD COLUMN carrier VARCHAR(50);
+ALTER TABLE delivery_events ADD COLUMN carrier_event_id VARCHAR(100);
+
+CREATE INDEX delivery_carrier_idx ON deliveries(carrier) WHERE carrier IS NOT NULL;
+
+-- Carrier updates are fetched repeatedly; each is recorded once
+CREATE UNIQUE INDEX delivery_event_carrier_idx ON delivery_events(delivery_id, carrier_event_id)
+    WHERE carrier_event_id IS NOT NULL;
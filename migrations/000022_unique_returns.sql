@@ -0,0 +1,4 @@
+-- A delivery can be returned once; concurrent return requests race past the
+-- service's check, so the database settles it
+CREATE UNIQUE INDEX delivery_return_parent_idx ON deliveries(parent_delivery_id)
+    WHERE delivery_type = 'RETURN';
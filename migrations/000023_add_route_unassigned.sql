@@ -0,0 +1 @@
+ALTER TABLE routes ADD COLUMN unassigned TEXT[];
@@ -0,0 +1,2 @@
+-- Who placed a hold, so only they (or staff) can release it
+ALTER TABLE slot_reservations ADD COLUMN held_by VARCHAR(36);
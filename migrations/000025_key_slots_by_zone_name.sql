@@ -0,0 +1,10 @@
+-- Slots, like service level allow-lists, carrier rules and rate cards, refer
+-- to zones by name; names are unique and no longer change
+CREATE UNIQUE INDEX zone_name_idx ON zones(name);
+
+ALTER TABLE delivery_slots ALTER COLUMN zone TYPE VARCHAR(100);
+
+UPDATE delivery_slots s 
+SET zone = z.name 
+FROM zones z 
+WHERE s.zone = z.id;